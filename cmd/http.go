@@ -0,0 +1,288 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	authcredentials "cloud.google.com/go/auth/credentials"
+	container "cloud.google.com/go/container/apiv1"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/auth"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/certreload"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/credentials"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/health"
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/rs/cors"
+)
+
+const readinessCacheTTL = 30 * time.Second
+
+// serveHTTP serves the MCP server over the streamable HTTP transport until ctx
+// is cancelled, then drains in-flight requests before returning.
+func serveHTTP(ctx context.Context, s *mcp.Server, c *config.Config, opts startOptions) error {
+	inflight := &inflightRequests{}
+	s.AddReceivingMiddleware(inflight.middleware)
+	if c.ForwardAccessToken() {
+		s.AddReceivingMiddleware(credentials.ForwardAccessToken())
+	}
+
+	mcpHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
+		return s
+	}, nil)
+
+	verifier, err := auth.NewVerifier(ctx, opts.auth)
+	if err != nil {
+		return fmt.Errorf("failed to configure authentication: %w", err)
+	}
+	if verifier == nil {
+		log.Printf("No authentication configured for HTTP mode; anyone who can reach %s:%d can call every tool.", opts.serverHost, opts.serverPort)
+	}
+	handler := newHTTPHandler(mcpHandler, opts.allowedOrigins, verifier)
+
+	gkeAPI := &gkeAPICheck{c: c}
+	defer gkeAPI.close()
+	readiness := health.NewReadiness(readinessCacheTTL,
+		health.Check{Name: "adc", Run: adcCredentialsCheck},
+		health.Check{Name: "gke_api", Run: gkeAPI.run},
+	)
+
+	// Health endpoints are served without authentication so probes can reach them.
+	mux := http.NewServeMux()
+	mux.Handle("/healthz", health.Liveness())
+	mux.Handle("/readyz", readiness)
+	mux.Handle("/", handler)
+
+	addr := fmt.Sprintf("%s:%d", opts.serverHost, opts.serverPort)
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+		ReadTimeout:       opts.readTimeout,
+		WriteTimeout:      opts.writeTimeout,
+		IdleTimeout:       opts.idleTimeout,
+	}
+
+	useTLS := opts.tlsCertFile != "" || opts.tlsKeyFile != ""
+	if useTLS {
+		if opts.tlsCertFile == "" || opts.tlsKeyFile == "" {
+			return fmt.Errorf("both --tls-cert and --tls-key must be set to serve TLS")
+		}
+		reloader, err := certreload.New(opts.tlsCertFile, opts.tlsKeyFile)
+		if err != nil {
+			return err
+		}
+		server.TLSConfig = &tls.Config{
+			MinVersion:     tls.VersionTLS12,
+			GetCertificate: reloader.GetCertificate,
+		}
+	}
+
+	serveErr := make(chan error, 1)
+	go func() {
+		if useTLS {
+			log.Printf("Listening for HTTPS connections on: %s", addr)
+			serveErr <- server.ListenAndServeTLS("", "")
+		} else {
+			log.Printf("Listening for HTTP connections on: %s", addr)
+			serveErr <- server.ListenAndServe()
+		}
+	}()
+
+	select {
+	case err := <-serveErr:
+		return err
+	case <-ctx.Done():
+	}
+
+	log.Printf("Shutting down, draining in-flight requests for up to %s", opts.shutdownTimeout)
+	readiness.SetDraining()
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), opts.shutdownTimeout)
+	defer cancel()
+
+	// Shutdown stops accepting new connections and waits for active ones. Open
+	// sessions keep their event streams alive, so close them once in-flight
+	// requests have finished or the drain timeout expires.
+	shutdownErr := make(chan error, 1)
+	go func() {
+		shutdownErr <- server.Shutdown(shutdownCtx)
+	}()
+	if err := inflight.wait(shutdownCtx); err != nil {
+		log.Printf("Timed out waiting for in-flight requests: %v", err)
+	}
+	for ss := range s.Sessions() {
+		if err := ss.Close(); err != nil {
+			log.Printf("Failed to close session %s: %v", ss.ID(), err)
+		}
+	}
+	if err := <-shutdownErr; err != nil {
+		log.Printf("Graceful shutdown failed, closing remaining connections: %v", err)
+		if err := server.Close(); err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// newHTTPHandler wraps the MCP handler with authentication, when verifier is
+// set, and CORS. CORS is applied first so browser preflight requests, which
+// never carry credentials, are answered without being rejected. Browsers may
+// send the bearer token and the streamable HTTP transport's session headers.
+func newHTTPHandler(mcpHandler http.Handler, allowedOrigins []string, verifier mcpauth.TokenVerifier) http.Handler {
+	if verifier != nil {
+		mcpHandler = auth.Middleware(verifier)(mcpHandler)
+	}
+	corsHandler := cors.New(cors.Options{
+		AllowedOrigins: allowedOrigins,
+		AllowedMethods: []string{http.MethodGet, http.MethodPost, http.MethodDelete},
+		AllowedHeaders: []string{"Accept", "Content-Type", "Authorization", "Mcp-Session-Id", "Mcp-Protocol-Version", "Last-Event-Id"},
+		ExposedHeaders: []string{"Mcp-Session-Id"},
+		Debug:          true, // Enable debug logging to see what the library is doing
+	})
+	return corsHandler.Handler(mcpHandler)
+}
+
+// adcCredentialsCheck verifies Application Default Credentials can mint a token.
+func adcCredentialsCheck(ctx context.Context) error {
+	creds, err := authcredentials.DetectDefault(&authcredentials.DetectOptions{
+		Scopes: []string{"https://www.googleapis.com/auth/cloud-platform"},
+	})
+	if err != nil {
+		return err
+	}
+	_, err = creds.Token(ctx)
+	return err
+}
+
+// gkeAPICheck verifies the GKE API is reachable with the server's
+// credentials. One client is reused across probes.
+type gkeAPICheck struct {
+	c *config.Config
+
+	mu       sync.Mutex
+	cmClient *container.ClusterManagerClient
+}
+
+func (g *gkeAPICheck) run(ctx context.Context) error {
+	// Without a default project there's nothing to ask the GKE API about, so
+	// only check that there are credentials to call it with.
+	if g.c.DefaultProjectID() == "" {
+		return adcCredentialsCheck(ctx)
+	}
+	cmClient, err := g.client(ctx)
+	if err != nil {
+		return err
+	}
+	return getServerConfig(ctx, g.c, cmClient)
+}
+
+func (g *gkeAPICheck) client(ctx context.Context) (*container.ClusterManagerClient, error) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cmClient == nil {
+		// The client outlives this probe, so don't tie it to the probe's timeout.
+		cmClient, err := container.NewClusterManagerClient(context.WithoutCancel(ctx), g.c.ClientOptions(config.ServiceContainer)...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create cluster manager client: %w", err)
+		}
+		g.cmClient = cmClient
+	}
+	return g.cmClient, nil
+}
+
+func (g *gkeAPICheck) close() {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if g.cmClient == nil {
+		return
+	}
+	if err := g.cmClient.Close(); err != nil {
+		log.Printf("Failed to close cluster manager client: %v\n", err)
+	}
+	g.cmClient = nil
+}
+
+// inflightRequests tracks MCP requests that are being handled. Once wait is
+// called, new requests are rejected so the count can only go down.
+type inflightRequests struct {
+	mu       sync.Mutex
+	count    int
+	draining bool
+	idle     chan struct{} // closed when draining and count drops to zero
+}
+
+func (r *inflightRequests) middleware(next mcp.MethodHandler) mcp.MethodHandler {
+	return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+		if !r.start() {
+			return nil, errShuttingDown
+		}
+		defer r.done()
+		return next(ctx, method, req)
+	}
+}
+
+var errShuttingDown = errors.New("server is shutting down")
+
+func (r *inflightRequests) start() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.draining {
+		return false
+	}
+	r.count++
+	return true
+}
+
+func (r *inflightRequests) done() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.count--
+	if r.draining && r.count == 0 {
+		close(r.idle)
+	}
+}
+
+// wait starts draining and waits until the requests being handled finish.
+func (r *inflightRequests) wait(ctx context.Context) error {
+	r.mu.Lock()
+	if !r.draining {
+		r.draining = true
+		r.idle = make(chan struct{})
+		if r.count == 0 {
+			close(r.idle)
+		}
+	}
+	idle := r.idle
+	r.mu.Unlock()
+	select {
+	case <-idle:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// errServerClosed reports whether err is the expected result of a shutdown.
+func errServerClosed(err error) bool {
+	return errors.Is(err, http.ErrServerClosed) || errors.Is(err, context.Canceled)
+}
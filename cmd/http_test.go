@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cmd
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/auth"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestNewHTTPHandler(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := newHTTPHandler(next, []string{"https://app.example.com"}, auth.StaticTokenVerifier("secret"))
+
+	tests := []struct {
+		name       string
+		method     string
+		header     string
+		wantStatus int
+		wantOrigin string
+	}{
+		{name: "preflight", method: http.MethodOptions, wantStatus: http.StatusNoContent, wantOrigin: "https://app.example.com"},
+		{name: "missing token", method: http.MethodPost, wantStatus: http.StatusUnauthorized, wantOrigin: "https://app.example.com"},
+		{name: "valid token", method: http.MethodPost, header: "Bearer secret", wantStatus: http.StatusOK, wantOrigin: "https://app.example.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/mcp", nil)
+			req.Header.Set("Origin", "https://app.example.com")
+			if tt.method == http.MethodOptions {
+				req.Header.Set("Access-Control-Request-Method", http.MethodPost)
+				req.Header.Set("Access-Control-Request-Headers", "authorization, content-type")
+			}
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.wantStatus {
+				t.Errorf("status = %d, want %d", rec.Code, tt.wantStatus)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != tt.wantOrigin {
+				t.Errorf("Access-Control-Allow-Origin = %q, want %q", got, tt.wantOrigin)
+			}
+		})
+	}
+}
+
+func TestGKEAPICheckWithoutProject(t *testing.T) {
+	t.Setenv("CLOUDSDK_CONFIG", t.TempDir())
+	t.Setenv("CLOUDSDK_CORE_PROJECT", "")
+	t.Setenv("GKE_MCP_PROJECT", "")
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", filepath.Join(t.TempDir(), "missing.json"))
+	c := config.New("test")
+	if c.DefaultProjectID() != "" {
+		t.Fatalf("DefaultProjectID() = %q, want none", c.DefaultProjectID())
+	}
+
+	g := &gkeAPICheck{c: c}
+	defer g.close()
+	if err := g.run(context.Background()); err == nil {
+		t.Errorf("run() succeeded without credentials, want error")
+	}
+}
+
+func TestInflightRequests(t *testing.T) {
+	r := &inflightRequests{}
+	release := make(chan struct{})
+	h := r.middleware(func(context.Context, string, mcp.Request) (mcp.Result, error) {
+		<-release
+		return nil, nil
+	})
+	handled := make(chan error)
+	go func() {
+		_, err := h(context.Background(), "tools/call", nil)
+		handled <- err
+	}()
+	for {
+		r.mu.Lock()
+		count := r.count
+		r.mu.Unlock()
+		if count == 1 {
+			break
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := r.wait(ctx); err == nil {
+		t.Errorf("wait() with a request in flight succeeded, want timeout")
+	}
+	if _, err := h(context.Background(), "tools/call", nil); !errors.Is(err, errShuttingDown) {
+		t.Errorf("request while draining error = %v, want %v", err, errShuttingDown)
+	}
+
+	close(release)
+	if err := <-handled; err != nil {
+		t.Errorf("in-flight request error = %v", err)
+	}
+	if err := r.wait(context.Background()); err != nil {
+		t.Errorf("wait() after the request finished error = %v", err)
+	}
+}
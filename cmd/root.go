@@ -17,39 +17,56 @@ package cmd
 
 import (
 	"context"
-	"errors"
 	"fmt"
 	"log"
-	"net/http"
 	"os"
+	"os/signal"
 	"runtime/debug"
 	"strings"
+	"syscall"
 	"time"
 
 	container "cloud.google.com/go/container/apiv1"
 	"cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/auth"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/credentials"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/install"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/prompts"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
-	"github.com/rs/cors"
 	"github.com/spf13/cobra"
-	"google.golang.org/api/option"
 )
 
 const (
 	geminiInstructionsURI = "mcp://gke/pkg/install/GEMINI.md"
+
+	// gcloudImpersonateEnvVar makes gcloud impersonate a service account.
+	gcloudImpersonateEnvVar = "CLOUDSDK_AUTH_IMPERSONATE_SERVICE_ACCOUNT"
 )
 
 var (
 	version = "(unknown)"
 
 	// command flags
-	serverMode     string
-	serverHost     string
-	serverPort     int
-	allowedOrigins []string
+	serverMode      string
+	serverHost      string
+	serverPort      int
+	allowedOrigins  []string
+	authTokenFile   string
+	oidcIssuer      string
+	oidcAudience    string
+	tlsCertFile     string
+	tlsKeyFile      string
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+	readOnly        bool
+	showSecrets     bool
+	configFile      string
+	impersonateSA   string
+	forwardToken    bool
 
 	// rootCmd represents the base command when called without any subcommands
 	rootCmd = &cobra.Command{
@@ -94,7 +111,9 @@ var (
 // Execute adds all child commands to the root command and sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	err := rootCmd.Execute()
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err := rootCmd.ExecuteContext(ctx)
+	stop()
 	if err != nil {
 		os.Exit(1)
 	}
@@ -111,6 +130,20 @@ func init() {
 	rootCmd.Flags().StringVar(&serverHost, "server-host", "127.0.0.1", "server host to use when server-mode is http; defaults to 127.0.0.1")
 	rootCmd.Flags().IntVar(&serverPort, "server-port", 8080, "server port to use when server-mode is http; defaults to 8080")
 	rootCmd.Flags().StringSliceVar(&allowedOrigins, "allowed-origins", []string{"http://localhost"}, "comma-separated list of allowed Origin headers")
+	rootCmd.Flags().StringVar(&authTokenFile, "auth-token-file", "", "file containing a static bearer token required for requests when server-mode is http; the token can also be set with the "+auth.TokenEnvVar+" environment variable")
+	rootCmd.Flags().StringVar(&oidcIssuer, "oidc-issuer", "", "OIDC issuer URL used to validate bearer JWTs when server-mode is http")
+	rootCmd.Flags().StringVar(&oidcAudience, "oidc-audience", "", "expected audience of bearer JWTs; required with --oidc-issuer")
+	rootCmd.Flags().StringVar(&tlsCertFile, "tls-cert", "", "TLS certificate file to serve HTTPS when server-mode is http; reloaded when it changes")
+	rootCmd.Flags().StringVar(&tlsKeyFile, "tls-key", "", "TLS private key file to serve HTTPS when server-mode is http; reloaded when it changes")
+	rootCmd.Flags().DurationVar(&readTimeout, "http-read-timeout", 30*time.Second, "maximum duration for reading an HTTP request, including the body")
+	rootCmd.Flags().DurationVar(&writeTimeout, "http-write-timeout", 0, "maximum duration for writing an HTTP response; 0 disables the timeout so long tool calls and event streams aren't cut off")
+	rootCmd.Flags().DurationVar(&idleTimeout, "http-idle-timeout", 120*time.Second, "maximum time to wait for the next request on a keep-alive connection")
+	rootCmd.Flags().StringVar(&configFile, "config", os.Getenv(config.EnvConfigFile), "path to a YAML or JSON configuration file; defaults to $"+config.EnvConfigFile)
+	rootCmd.Flags().BoolVar(&readOnly, "read-only", false, "only register tools that don't modify resources")
+	rootCmd.Flags().BoolVar(&showSecrets, "show-secrets", false, "return the values of Kubernetes Secrets from tools reading resources instead of redacting them")
+	rootCmd.Flags().StringVar(&impersonateSA, "impersonate-service-account", "", "email of a service account to impersonate for Google Cloud API calls, using Application Default Credentials as the source")
+	rootCmd.Flags().BoolVar(&forwardToken, "forward-access-token", false, "when server-mode is http, make each tool call act as the caller using the Google OAuth access token in its "+credentials.ForwardedAccessTokenHeader+" header")
+	rootCmd.Flags().DurationVar(&shutdownTimeout, "shutdown-timeout", 30*time.Second, "maximum time to drain in-flight requests on SIGTERM or SIGINT when server-mode is http")
 	rootCmd.AddCommand(installCmd)
 
 	installCmd.AddCommand(installGeminiCLICmd)
@@ -130,6 +163,20 @@ type startOptions struct {
 	serverHost     string
 	serverPort     int
 	allowedOrigins []string
+	auth           auth.Options
+
+	tlsCertFile     string
+	tlsKeyFile      string
+	readTimeout     time.Duration
+	writeTimeout    time.Duration
+	idleTimeout     time.Duration
+	shutdownTimeout time.Duration
+
+	readOnly           bool
+	showSecrets        bool
+	configFile         string
+	impersonateSA      string
+	forwardAccessToken bool
 }
 
 func runRootCmd(cmd *cobra.Command, _ []string) {
@@ -138,12 +185,59 @@ func runRootCmd(cmd *cobra.Command, _ []string) {
 		serverHost:     serverHost,
 		serverPort:     serverPort,
 		allowedOrigins: allowedOrigins,
+		auth:           auth.OptionsFromEnv(),
+
+		tlsCertFile:     tlsCertFile,
+		tlsKeyFile:      tlsKeyFile,
+		readTimeout:     readTimeout,
+		writeTimeout:    writeTimeout,
+		idleTimeout:     idleTimeout,
+		shutdownTimeout: shutdownTimeout,
+
+		readOnly:           readOnly,
+		showSecrets:        showSecrets,
+		configFile:         configFile,
+		impersonateSA:      impersonateSA,
+		forwardAccessToken: forwardToken,
 	}
+	opts.auth.TokenFile = authTokenFile
+	opts.auth.OIDCIssuer = oidcIssuer
+	opts.auth.OIDCAudience = oidcAudience
 	startMCPServer(cmd.Context(), opts)
 }
 
 func startMCPServer(ctx context.Context, opts startOptions) {
-	c := config.New(version)
+	var file *config.File
+	if opts.configFile != "" {
+		var err error
+		if file, err = config.LoadFile(opts.configFile); err != nil {
+			log.Fatalf("Failed to load config: %v\n", err)
+		}
+	}
+	// Flags take precedence over environment variables, which take precedence
+	// over the config file.
+	configOpts := []config.Option{config.WithFile(file), config.WithEnv()}
+	if opts.readOnly {
+		configOpts = append(configOpts, config.WithReadOnly(true))
+	}
+	if opts.showSecrets {
+		configOpts = append(configOpts, config.WithShowSecrets(true))
+	}
+	configOpts = append(configOpts, config.WithImpersonateServiceAccount(opts.impersonateSA))
+	if opts.forwardAccessToken {
+		if opts.serverMode != "http" {
+			log.Fatalf("--forward-access-token requires --server-mode=http")
+		}
+		configOpts = append(configOpts, config.WithForwardedAccessToken(true))
+	}
+	c := config.New(version, configOpts...)
+
+	// Make gcloud commands run by tools act as the same service account.
+	if sa := c.ImpersonateServiceAccount(); sa != "" && os.Getenv(gcloudImpersonateEnvVar) == "" {
+		if err := os.Setenv(gcloudImpersonateEnvVar, sa); err != nil {
+			log.Printf("Failed to set %s: %v", gcloudImpersonateEnvVar, err)
+		}
+	}
 
 	instructions := ""
 	if err := adcAuthCheck(ctx, c); err != nil {
@@ -152,6 +246,12 @@ func startMCPServer(ctx context.Context, opts startOptions) {
 			instructions += "GKE API calls requires Application Default Credentials (https://cloud.google.com/docs/authentication/application-default-credentials). Get credentials with `gcloud auth application-default login` before calling MCP tools."
 		}
 	}
+	if c.ReadOnly() {
+		if instructions != "" {
+			instructions += "\n\n"
+		}
+		instructions += "This server is running in read-only mode. Tools that create, modify or delete resources are not available; don't try to work around this by running equivalent gcloud or kubectl commands."
+	}
 
 	s := mcp.NewServer(
 		&mcp.Implementation{
@@ -201,35 +301,14 @@ func startMCPServer(ctx context.Context, opts startOptions) {
 		tr := &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: log.Writer()}
 		err = s.Run(ctx, tr)
 	case "http":
-		mcpHandler := mcp.NewStreamableHTTPHandler(func(_ *http.Request) *mcp.Server {
-			return s
-		}, nil)
-
-		// Create a new CORS handler
-		c := cors.New(cors.Options{
-			AllowedOrigins: allowedOrigins,
-			Debug:          true, // Enable debug logging to see what the library is doing
-		})
-		corsHandler := c.Handler(mcpHandler)
-
-		addr := fmt.Sprintf("%s:%d", opts.serverHost, opts.serverPort)
-		log.Printf("Listening for HTTP connections on port: %s", addr)
-		server := &http.Server{
-			Addr:              addr,
-			Handler:           corsHandler,
-			ReadHeaderTimeout: 5 * time.Second,
-			ReadTimeout:       5 * time.Second,
-			WriteTimeout:      10 * time.Second,
-			IdleTimeout:       120 * time.Second,
-		}
-		err = server.ListenAndServe()
+		err = serveHTTP(ctx, s, c, opts)
 	default:
 		log.Printf("Unknown mode '%s', defaulting to 'stdio'", opts.serverMode)
 		tr := &mcp.LoggingTransport{Transport: &mcp.StdioTransport{}, Writer: log.Writer()}
 		err = s.Run(ctx, tr)
 	}
 	if err != nil {
-		if errors.Is(err, context.Canceled) {
+		if errServerClosed(err) {
 			log.Printf("Server shutting down.")
 		} else {
 			log.Printf("Server error: %v\n", err)
@@ -238,19 +317,12 @@ func startMCPServer(ctx context.Context, opts startOptions) {
 }
 
 func adcAuthCheck(ctx context.Context, c *config.Config) error {
-	projectID := c.DefaultProjectID()
 	// Can't do a pre-flight check without a default project.
-	if projectID == "" {
+	if c.DefaultProjectID() == "" {
 		return nil
 	}
 
-	location := c.DefaultLocation()
-	// Without a default location try checking us-central1.
-	if location == "" {
-		location = "us-central1"
-	}
-
-	cmClient, err := container.NewClusterManagerClient(ctx, option.WithUserAgent(c.UserAgent()))
+	cmClient, err := container.NewClusterManagerClient(ctx, c.ClientOptions(config.ServiceContainer)...)
 	if err != nil {
 		return fmt.Errorf("failed to create cluster manager client: %w", err)
 	}
@@ -259,9 +331,18 @@ func adcAuthCheck(ctx context.Context, c *config.Config) error {
 			log.Printf("Failed to close cluster manager client: %v\n", err)
 		}
 	}()
+	return getServerConfig(ctx, c, cmClient)
+}
 
-	_, err = cmClient.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{
-		Name: fmt.Sprintf("projects/%s/locations/%s", projectID, location),
+// getServerConfig calls the GKE API for the default project and location.
+func getServerConfig(ctx context.Context, c *config.Config, cmClient *container.ClusterManagerClient) error {
+	location := c.DefaultLocation()
+	// Without a default location try checking us-central1.
+	if location == "" {
+		location = "us-central1"
+	}
+	_, err := cmClient.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s", c.DefaultProjectID(), location),
 	})
 	return err
 }
@@ -0,0 +1,175 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package auth provides bearer token authentication for the HTTP transport.
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+// TokenEnvVar is the environment variable holding a static bearer token.
+const TokenEnvVar = "GKE_MCP_AUTH_TOKEN"
+
+// staticTokenTTL is the expiration reported for requests authenticated with a
+// static token. Static tokens don't expire, but the MCP SDK requires one.
+const staticTokenTTL = time.Minute
+
+// Options configures how HTTP requests are authenticated.
+type Options struct {
+	// TokenFile is a file containing a static bearer token.
+	TokenFile string
+	// Token is a static bearer token, typically read from TokenEnvVar.
+	Token string
+	// OIDCIssuer is the issuer URL used to discover OIDC signing keys.
+	OIDCIssuer string
+	// OIDCAudience is the expected "aud" claim of OIDC tokens.
+	OIDCAudience string
+}
+
+// OptionsFromEnv returns Options with the static token read from TokenEnvVar.
+func OptionsFromEnv() Options {
+	return Options{
+		Token: os.Getenv(TokenEnvVar),
+	}
+}
+
+// Enabled reports whether any authentication method is configured.
+func (o Options) Enabled() bool {
+	return o.TokenFile != "" || o.Token != "" || o.OIDCIssuer != ""
+}
+
+// NewVerifier builds a token verifier from the configured authentication
+// methods. When several methods are configured, a token is accepted if any of
+// them accepts it. NewVerifier returns nil if no method is configured.
+func NewVerifier(ctx context.Context, opts Options) (mcpauth.TokenVerifier, error) {
+	var verifiers []mcpauth.TokenVerifier
+
+	token := opts.Token
+	if opts.TokenFile != "" {
+		b, err := os.ReadFile(opts.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read auth token file: %w", err)
+		}
+		token = strings.TrimSpace(string(b))
+		if token == "" {
+			return nil, fmt.Errorf("auth token file %s is empty", opts.TokenFile)
+		}
+	}
+	if token != "" {
+		verifiers = append(verifiers, StaticTokenVerifier(token))
+	}
+
+	if opts.OIDCIssuer != "" {
+		if opts.OIDCAudience == "" {
+			return nil, fmt.Errorf("an OIDC audience is required when an OIDC issuer is set")
+		}
+		v, err := OIDCVerifier(ctx, opts.OIDCIssuer, opts.OIDCAudience)
+		if err != nil {
+			return nil, err
+		}
+		verifiers = append(verifiers, v)
+	} else if opts.OIDCAudience != "" {
+		return nil, fmt.Errorf("an OIDC issuer is required when an OIDC audience is set")
+	}
+
+	switch len(verifiers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return verifiers[0], nil
+	default:
+		return anyVerifier(verifiers), nil
+	}
+}
+
+// StaticTokenVerifier accepts only the given token.
+func StaticTokenVerifier(token string) mcpauth.TokenVerifier {
+	want := []byte(token)
+	return func(_ context.Context, got string, _ *http.Request) (*mcpauth.TokenInfo, error) {
+		if subtle.ConstantTimeCompare(want, []byte(got)) != 1 {
+			return nil, fmt.Errorf("%w: token mismatch", mcpauth.ErrInvalidToken)
+		}
+		return &mcpauth.TokenInfo{
+			UserID:     "static-token",
+			Expiration: time.Now().Add(staticTokenTTL),
+		}, nil
+	}
+}
+
+// OIDCVerifier validates JWTs signed by the given issuer for the given audience.
+// The issuer's signing keys are discovered through its OIDC discovery document.
+func OIDCVerifier(ctx context.Context, issuer, audience string) (mcpauth.TokenVerifier, error) {
+	provider, err := oidc.NewProvider(ctx, issuer)
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover OIDC issuer %s: %w", issuer, err)
+	}
+	return newOIDCVerifier(provider.Verifier(&oidc.Config{ClientID: audience})), nil
+}
+
+func newOIDCVerifier(v *oidc.IDTokenVerifier) mcpauth.TokenVerifier {
+	return func(ctx context.Context, raw string, _ *http.Request) (*mcpauth.TokenInfo, error) {
+		token, err := v.Verify(ctx, raw)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", mcpauth.ErrInvalidToken, err)
+		}
+		var claims struct {
+			Email string `json:"email"`
+			Scope string `json:"scope"`
+		}
+		if err := token.Claims(&claims); err != nil {
+			return nil, fmt.Errorf("%w: %v", mcpauth.ErrInvalidToken, err)
+		}
+		userID := token.Subject
+		if claims.Email != "" {
+			userID = claims.Email
+		}
+		return &mcpauth.TokenInfo{
+			UserID:     userID,
+			Scopes:     strings.Fields(claims.Scope),
+			Expiration: token.Expiry,
+		}, nil
+	}
+}
+
+func anyVerifier(verifiers []mcpauth.TokenVerifier) mcpauth.TokenVerifier {
+	return func(ctx context.Context, token string, req *http.Request) (*mcpauth.TokenInfo, error) {
+		var errs []error
+		for _, v := range verifiers {
+			info, err := v(ctx, token, req)
+			if err == nil {
+				return info, nil
+			}
+			errs = append(errs, err)
+		}
+		return nil, errors.Join(errs...)
+	}
+}
+
+// Middleware returns HTTP middleware that rejects requests without a valid
+// bearer token. Authenticated token info is available to MCP handlers through
+// the request's TokenInfo.
+func Middleware(verifier mcpauth.TokenVerifier) func(http.Handler) http.Handler {
+	return mcpauth.RequireBearerToken(verifier, nil)
+}
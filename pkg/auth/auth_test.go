@@ -0,0 +1,238 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"github.com/go-jose/go-jose/v4"
+	mcpauth "github.com/modelcontextprotocol/go-sdk/auth"
+)
+
+func TestStaticTokenVerifier(t *testing.T) {
+	v := StaticTokenVerifier("secret")
+
+	if _, err := v(context.Background(), "secret", nil); err != nil {
+		t.Errorf("verifier rejected the configured token: %v", err)
+	}
+	_, err := v(context.Background(), "wrong", nil)
+	if !errors.Is(err, mcpauth.ErrInvalidToken) {
+		t.Errorf("verifier error = %v, want ErrInvalidToken", err)
+	}
+}
+
+func TestNewVerifier(t *testing.T) {
+	dir := t.TempDir()
+	tokenFile := filepath.Join(dir, "token")
+	if err := os.WriteFile(tokenFile, []byte("file-secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	emptyFile := filepath.Join(dir, "empty")
+	if err := os.WriteFile(emptyFile, nil, 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name      string
+		opts      Options
+		wantNil   bool
+		wantErr   bool
+		wantToken string
+	}{
+		{
+			name:    "no auth configured",
+			wantNil: true,
+		},
+		{
+			name:      "token from env",
+			opts:      Options{Token: "env-secret"},
+			wantToken: "env-secret",
+		},
+		{
+			name:      "token file takes precedence",
+			opts:      Options{Token: "env-secret", TokenFile: tokenFile},
+			wantToken: "file-secret",
+		},
+		{
+			name:    "missing token file",
+			opts:    Options{TokenFile: filepath.Join(dir, "missing")},
+			wantErr: true,
+		},
+		{
+			name:    "empty token file",
+			opts:    Options{TokenFile: emptyFile},
+			wantErr: true,
+		},
+		{
+			name:    "audience without issuer",
+			opts:    Options{OIDCAudience: "gke-mcp"},
+			wantErr: true,
+		},
+		{
+			name:    "issuer without audience",
+			opts:    Options{OIDCIssuer: "https://issuer.example.com"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := NewVerifier(context.Background(), tt.opts)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewVerifier() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if (v == nil) != tt.wantNil {
+				t.Fatalf("NewVerifier() = %v, wantNil %v", v, tt.wantNil)
+			}
+			if tt.wantToken != "" {
+				if _, err := v(context.Background(), tt.wantToken, nil); err != nil {
+					t.Errorf("verifier rejected %q: %v", tt.wantToken, err)
+				}
+			}
+		})
+	}
+}
+
+func TestOIDCVerifier(t *testing.T) {
+	const (
+		issuer   = "https://issuer.example.com"
+		audience = "gke-mcp"
+	)
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	signer, err := jose.NewSigner(jose.SigningKey{Algorithm: jose.RS256, Key: key}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(claims map[string]any) string {
+		payload, err := json.Marshal(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		jws, err := signer.Sign(payload)
+		if err != nil {
+			t.Fatal(err)
+		}
+		raw, err := jws.CompactSerialize()
+		if err != nil {
+			t.Fatal(err)
+		}
+		return raw
+	}
+
+	keySet := &oidc.StaticKeySet{PublicKeys: []crypto.PublicKey{key.Public()}}
+	v := newOIDCVerifier(oidc.NewVerifier(issuer, keySet, &oidc.Config{ClientID: audience}))
+
+	exp := time.Now().Add(time.Hour).Unix()
+	tests := []struct {
+		name       string
+		claims     map[string]any
+		wantErr    bool
+		wantUserID string
+	}{
+		{
+			name:       "valid token",
+			claims:     map[string]any{"iss": issuer, "aud": audience, "sub": "123", "email": "user@example.com", "exp": exp},
+			wantUserID: "user@example.com",
+		},
+		{
+			name:       "valid token without email",
+			claims:     map[string]any{"iss": issuer, "aud": audience, "sub": "123", "exp": exp},
+			wantUserID: "123",
+		},
+		{
+			name:    "wrong audience",
+			claims:  map[string]any{"iss": issuer, "aud": "other", "sub": "123", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "wrong issuer",
+			claims:  map[string]any{"iss": "https://other.example.com", "aud": audience, "sub": "123", "exp": exp},
+			wantErr: true,
+		},
+		{
+			name:    "expired",
+			claims:  map[string]any{"iss": issuer, "aud": audience, "sub": "123", "exp": time.Now().Add(-time.Hour).Unix()},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := v(context.Background(), sign(tt.claims), nil)
+			if tt.wantErr {
+				if !errors.Is(err, mcpauth.ErrInvalidToken) {
+					t.Errorf("verifier error = %v, want ErrInvalidToken", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("verifier error = %v", err)
+			}
+			if info.UserID != tt.wantUserID {
+				t.Errorf("UserID = %s, want %s", info.UserID, tt.wantUserID)
+			}
+		})
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	next := http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	})
+	h := Middleware(StaticTokenVerifier("secret"))(next)
+
+	tests := []struct {
+		name   string
+		header string
+		want   int
+	}{
+		{"missing header", "", http.StatusUnauthorized},
+		{"wrong token", "Bearer wrong", http.StatusUnauthorized},
+		{"wrong scheme", "Basic secret", http.StatusUnauthorized},
+		{"valid token", "Bearer secret", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/mcp", nil)
+			if tt.header != "" {
+				req.Header.Set("Authorization", tt.header)
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != tt.want {
+				t.Errorf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
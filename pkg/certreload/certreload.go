@@ -0,0 +1,110 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package certreload serves TLS certificates that are reloaded from disk when
+// they change.
+package certreload
+
+import (
+	"crypto/tls"
+	"fmt"
+	"log"
+	"os"
+	"sync"
+	"time"
+)
+
+// Reloader loads a certificate and key pair and reloads them whenever either
+// file's modification time changes.
+type Reloader struct {
+	certFile string
+	keyFile  string
+
+	mu       sync.Mutex
+	cert     *tls.Certificate
+	certMod  time.Time
+	keyMod   time.Time
+	lastStat time.Time
+}
+
+// statInterval bounds how often the files are checked for changes.
+const statInterval = time.Second
+
+// New loads the certificate and key pair and returns a Reloader serving it.
+func New(certFile, keyFile string) (*Reloader, error) {
+	r := &Reloader{
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		return nil, err
+	}
+	if err := r.load(certMod, keyMod); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// GetCertificate returns the current certificate. It is meant to be used as
+// tls.Config.GetCertificate. If reloading a changed pair fails, the previously
+// loaded certificate keeps being served.
+func (r *Reloader) GetCertificate(_ *tls.ClientHelloInfo) (*tls.Certificate, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if time.Since(r.lastStat) < statInterval {
+		return r.cert, nil
+	}
+	r.lastStat = time.Now()
+
+	certMod, keyMod, err := r.modTimes()
+	if err != nil {
+		log.Printf("Failed to check TLS certificate for changes: %v", err)
+		return r.cert, nil
+	}
+	if certMod.Equal(r.certMod) && keyMod.Equal(r.keyMod) {
+		return r.cert, nil
+	}
+	if err := r.load(certMod, keyMod); err != nil {
+		log.Printf("Failed to reload TLS certificate, keeping the previous one: %v", err)
+		return r.cert, nil
+	}
+	log.Printf("Reloaded TLS certificate from %s", r.certFile)
+	return r.cert, nil
+}
+
+// load must be called with r.mu held, or before r is shared.
+func (r *Reloader) load(certMod, keyMod time.Time) error {
+	cert, err := tls.LoadX509KeyPair(r.certFile, r.keyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS key pair: %w", err)
+	}
+	r.cert = &cert
+	r.certMod = certMod
+	r.keyMod = keyMod
+	return nil
+}
+
+func (r *Reloader) modTimes() (time.Time, time.Time, error) {
+	certInfo, err := os.Stat(r.certFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to stat TLS certificate: %w", err)
+	}
+	keyInfo, err := os.Stat(r.keyFile)
+	if err != nil {
+		return time.Time{}, time.Time{}, fmt.Errorf("failed to stat TLS key: %w", err)
+	}
+	return certInfo.ModTime(), keyInfo.ModTime(), nil
+}
@@ -12,20 +12,83 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package config loads configuration derived from local gcloud defaults.
+// Package config loads runtime configuration from a configuration file,
+// environment variables and, as a fallback, local gcloud defaults.
 package config
 
 import (
 	"log"
-	"os/exec"
-	"strings"
+
+	"cloud.google.com/go/auth"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/credentials"
+	"google.golang.org/api/option"
+)
+
+// Services whose API endpoints can be overridden.
+const (
+	ServiceCompute         = "compute"
+	ServiceContainer       = "container"
+	ServiceLogging         = "logging"
+	ServiceMonitoring      = "monitoring"
+	ServiceRecommender     = "recommender"
+	ServiceResourceManager = "resourcemanager"
 )
 
+var services = []string{ServiceCompute, ServiceContainer, ServiceLogging, ServiceMonitoring, ServiceRecommender, ServiceResourceManager}
+
+// DefaultProtectionLabel is the cluster label that protects clusters from
+// deletion unless configured otherwise.
+const DefaultProtectionLabel = "gke-mcp-protected"
+
 // Config contains runtime configuration derived from the environment.
 type Config struct {
-	userAgent        string
-	defaultProjectID string
-	defaultLocation  string
+	userAgent                 string
+	defaultProjectID          string
+	defaultLocation           string
+	quotaProject              string
+	impersonateServiceAccount string
+	forwardAccessToken        bool
+	credentials               *auth.Credentials
+	endpoints                 map[string]string
+	readOnly                  bool
+	showSecrets               bool
+	protectionLabel           string
+	toolFilter                *Filter
+	promptFilter              *Filter
+}
+
+// Option customizes a Config created by New.
+type Option func(*Config)
+
+// WithReadOnly sets whether the server only exposes tools that don't modify resources.
+func WithReadOnly(readOnly bool) Option {
+	return func(c *Config) {
+		c.readOnly = readOnly
+	}
+}
+
+// WithShowSecrets sets whether tools return the values of Kubernetes Secrets
+// instead of redacting them.
+func WithShowSecrets(show bool) Option {
+	return func(c *Config) {
+		c.showSecrets = show
+	}
+}
+
+// WithImpersonateServiceAccount sets the service account API calls act as.
+func WithImpersonateServiceAccount(email string) Option {
+	return func(c *Config) {
+		setIfNotEmpty(&c.impersonateServiceAccount, email)
+	}
+}
+
+// WithForwardedAccessToken sets whether API calls use the caller's access
+// token from the context instead of the server's credentials. See
+// credentials.ForwardAccessToken.
+func WithForwardedAccessToken(forward bool) Option {
+	return func(c *Config) {
+		c.forwardAccessToken = forward
+	}
 }
 
 // UserAgent returns the user agent string for outbound API calls.
@@ -43,13 +106,99 @@ func (c *Config) DefaultLocation() string {
 	return c.defaultLocation
 }
 
-// New constructs a Config populated from gcloud and build version.
-func New(version string) *Config {
-	return &Config{
-		userAgent:        "gke-mcp/" + version,
-		defaultProjectID: getDefaultProjectID(),
-		defaultLocation:  getDefaultLocation(),
+// QuotaProject returns the project used for quota and billing of API calls, if set.
+func (c *Config) QuotaProject() string {
+	return c.quotaProject
+}
+
+// ImpersonateServiceAccount returns the service account API calls act as, if set.
+func (c *Config) ImpersonateServiceAccount() string {
+	return c.impersonateServiceAccount
+}
+
+// ForwardAccessToken reports whether API calls use the caller's forwarded
+// access token.
+func (c *Config) ForwardAccessToken() bool {
+	return c.forwardAccessToken
+}
+
+// Credentials returns the credentials API calls use with impersonation or
+// forwarded access tokens, or nil when clients find ADC themselves.
+func (c *Config) Credentials() *auth.Credentials {
+	return c.credentials
+}
+
+// Endpoint returns the API endpoint override for service, if set.
+func (c *Config) Endpoint(service string) string {
+	return c.endpoints[service]
+}
+
+// ClientOptions returns the options for creating a client of the given service.
+func (c *Config) ClientOptions(service string) []option.ClientOption {
+	opts := []option.ClientOption{option.WithUserAgent(c.userAgent)}
+	if c.quotaProject != "" {
+		opts = append(opts, option.WithQuotaProject(c.quotaProject))
+	}
+	if endpoint := c.Endpoint(service); endpoint != "" {
+		opts = append(opts, option.WithEndpoint(endpoint))
+	}
+	if c.credentials != nil {
+		opts = append(opts, option.WithAuthCredentials(c.credentials))
+	}
+	return opts
+}
+
+// ReadOnly reports whether only read-only tools should be registered.
+func (c *Config) ReadOnly() bool {
+	return c.readOnly
+}
+
+// ShowSecrets reports whether tools return the values of Kubernetes Secrets.
+func (c *Config) ShowSecrets() bool {
+	return c.showSecrets
+}
+
+// ProtectionLabel returns the cluster label that protects clusters from
+// deletion.
+func (c *Config) ProtectionLabel() string {
+	return c.protectionLabel
+}
+
+// ToolFilter returns the filter selecting which tools are registered. A nil
+// Filter enables every tool.
+func (c *Config) ToolFilter() *Filter {
+	return c.toolFilter
+}
+
+// PromptFilter returns the filter selecting which prompts are registered. A nil
+// Filter enables every prompt.
+func (c *Config) PromptFilter() *Filter {
+	return c.promptFilter
+}
+
+// New constructs a Config for the given build version. Options are applied in
+// order, so later ones take precedence. The default project and location fall
+// back to gcloud's configuration when no option sets them.
+func New(version string, opts ...Option) *Config {
+	c := &Config{
+		userAgent:       "gke-mcp/" + version,
+		endpoints:       map[string]string{},
+		protectionLabel: DefaultProtectionLabel,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	if c.defaultProjectID == "" {
+		c.defaultProjectID = getDefaultProjectID()
+	}
+	if c.defaultLocation == "" {
+		c.defaultLocation = getDefaultLocation()
 	}
+	// Without impersonation or forwarded tokens, clients find ADC themselves.
+	if c.impersonateServiceAccount != "" || c.forwardAccessToken {
+		c.credentials = credentials.New(c.impersonateServiceAccount, c.forwardAccessToken)
+	}
+	return c
 }
 
 func getDefaultProjectID() string {
@@ -66,17 +215,10 @@ func getDefaultLocation() string {
 	if err == nil {
 		return region
 	}
-	zone, err := getGcloudConfig("compute/zone")
-	if err == nil {
+	zone, zoneErr := getGcloudConfig("compute/zone")
+	if zoneErr == nil {
 		return zone
 	}
+	log.Printf("Failed to get default location: %v; %v", err, zoneErr)
 	return ""
 }
-
-func getGcloudConfig(key string) (string, error) {
-	out, err := exec.Command("gcloud", "config", "get", key).Output()
-	if err != nil {
-		return "", err
-	}
-	return strings.TrimSpace(string(out)), nil
-}
@@ -45,6 +45,24 @@ func TestConfigGetters(t *testing.T) {
 	}
 }
 
+func TestNewWithReadOnly(t *testing.T) {
+	if New("1.0.0").ReadOnly() {
+		t.Errorf("ReadOnly() = true, want false by default")
+	}
+	if !New("1.0.0", WithReadOnly(true)).ReadOnly() {
+		t.Errorf("ReadOnly() = false, want true with WithReadOnly(true)")
+	}
+}
+
+func TestNewWithShowSecrets(t *testing.T) {
+	if New("1.0.0").ShowSecrets() {
+		t.Errorf("ShowSecrets() = true, want false by default")
+	}
+	if !New("1.0.0", WithShowSecrets(true)).ShowSecrets() {
+		t.Errorf("ShowSecrets() = false, want true with WithShowSecrets(true)")
+	}
+}
+
 func TestNewConfigWithVersion(t *testing.T) {
 	testVersion := "0.1.0"
 	cfg := New(testVersion)
@@ -103,7 +121,7 @@ func TestGetGcloudConfigTrimsWhitespace(t *testing.T) {
 
 	out, err := getGcloudConfig("core/project")
 	if err != nil {
-		t.Logf("gcloud config get failed (expected if not configured): %v", err)
+		t.Logf("gcloud config read failed (expected if not configured): %v", err)
 	}
 	if out != "" {
 		result := out
@@ -169,3 +187,97 @@ func TestNewConfigDifferentVersions(t *testing.T) {
 		})
 	}
 }
+
+func TestConfigPrecedence(t *testing.T) {
+	file := &File{
+		Project:         "file-project",
+		Location:        "us-east1",
+		QuotaProject:    "file-quota",
+		ProtectionLabel: "file-protected",
+		Endpoints: map[string]string{
+			ServiceContainer: "file-container.example.com:443",
+			ServiceLogging:   "file-logging.example.com:443",
+		},
+	}
+	t.Setenv(EnvProject, "env-project")
+	t.Setenv(EnvLocation, "")
+	t.Setenv(EnvQuotaProject, "")
+	t.Setenv(EnvImpersonateServiceAccount, "sa@env-project.iam.gserviceaccount.com")
+	t.Setenv(EnvReadOnly, "true")
+	t.Setenv(EnvProtectionLabel, "")
+	t.Setenv("GKE_MCP_CONTAINER_ENDPOINT", "env-container.example.com:443")
+
+	cfg := New("test", WithFile(file), WithEnv())
+
+	tests := []struct {
+		name     string
+		got      string
+		expected string
+	}{
+		{"DefaultProjectID", cfg.DefaultProjectID(), "env-project"},
+		{"DefaultLocation", cfg.DefaultLocation(), "us-east1"},
+		{"QuotaProject", cfg.QuotaProject(), "file-quota"},
+		{"ImpersonateServiceAccount", cfg.ImpersonateServiceAccount(), "sa@env-project.iam.gserviceaccount.com"},
+		{"Endpoint(container)", cfg.Endpoint(ServiceContainer), "env-container.example.com:443"},
+		{"Endpoint(logging)", cfg.Endpoint(ServiceLogging), "file-logging.example.com:443"},
+		{"Endpoint(monitoring)", cfg.Endpoint(ServiceMonitoring), ""},
+		{"ProtectionLabel", cfg.ProtectionLabel(), "file-protected"},
+		{"default ProtectionLabel", New("test", WithEnv()).ProtectionLabel(), DefaultProtectionLabel},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.got != tt.expected {
+				t.Errorf("%s = %s, want %s", tt.name, tt.got, tt.expected)
+			}
+		})
+	}
+	if !cfg.ReadOnly() {
+		t.Errorf("ReadOnly() = false, want true from %s", EnvReadOnly)
+	}
+}
+
+func TestWithEnvInvalidReadOnly(t *testing.T) {
+	t.Setenv(EnvProject, "p")
+	t.Setenv(EnvLocation, "l")
+	t.Setenv(EnvReadOnly, "maybe")
+	cfg := New("test", WithFile(&File{ReadOnly: true}), WithEnv())
+	if !cfg.ReadOnly() {
+		t.Errorf("ReadOnly() = false, want the file setting to be kept")
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	cfg := &Config{userAgent: "test-agent"}
+	if got := len(cfg.ClientOptions(ServiceContainer)); got != 1 {
+		t.Errorf("len(ClientOptions()) = %d, want 1", got)
+	}
+
+	cfg = &Config{
+		userAgent:    "test-agent",
+		quotaProject: "quota",
+		endpoints:    map[string]string{ServiceContainer: "container.example.com:443"},
+	}
+	if got := len(cfg.ClientOptions(ServiceContainer)); got != 3 {
+		t.Errorf("len(ClientOptions(container)) = %d, want 3", got)
+	}
+	if got := len(cfg.ClientOptions(ServiceLogging)); got != 2 {
+		t.Errorf("len(ClientOptions(logging)) = %d, want 2", got)
+	}
+}
+
+func TestClientOptionsWithCredentials(t *testing.T) {
+	t.Setenv(EnvProject, "p")
+	t.Setenv(EnvLocation, "l")
+	cfg := New("test", WithEnv())
+	if got := len(cfg.ClientOptions(ServiceContainer)); got != 1 || cfg.Credentials() != nil {
+		t.Errorf("len(ClientOptions()) = %d, Credentials() = %v, want 1 and none without impersonation", got, cfg.Credentials())
+	}
+	cfg = New("test", WithEnv(), WithImpersonateServiceAccount("sa@p.iam.gserviceaccount.com"))
+	if got := len(cfg.ClientOptions(ServiceContainer)); got != 2 || cfg.Credentials() == nil {
+		t.Errorf("len(ClientOptions()) = %d, Credentials() = %v, want 2 and credentials with impersonation", got, cfg.Credentials())
+	}
+	cfg = New("test", WithEnv(), WithForwardedAccessToken(true))
+	if !cfg.ForwardAccessToken() || len(cfg.ClientOptions(ServiceContainer)) != 2 {
+		t.Errorf("WithForwardedAccessToken(true) didn't configure credentials")
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"log"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// Environment variables that override configuration file settings.
+const (
+	EnvConfigFile                = "GKE_MCP_CONFIG"
+	EnvProject                   = "GKE_MCP_PROJECT"
+	EnvLocation                  = "GKE_MCP_LOCATION"
+	EnvQuotaProject              = "GKE_MCP_QUOTA_PROJECT"
+	EnvImpersonateServiceAccount = "GKE_MCP_IMPERSONATE_SERVICE_ACCOUNT"
+	EnvReadOnly                  = "GKE_MCP_READ_ONLY"
+	EnvProtectionLabel           = "GKE_MCP_PROTECTION_LABEL"
+)
+
+// endpointEnvVar returns the environment variable overriding a service's
+// endpoint, e.g. GKE_MCP_CONTAINER_ENDPOINT.
+func endpointEnvVar(service string) string {
+	return "GKE_MCP_" + strings.ToUpper(service) + "_ENDPOINT"
+}
+
+// WithEnv applies settings from GKE_MCP_* environment variables.
+func WithEnv() Option {
+	return func(c *Config) {
+		setIfNotEmpty(&c.defaultProjectID, os.Getenv(EnvProject))
+		setIfNotEmpty(&c.defaultLocation, os.Getenv(EnvLocation))
+		setIfNotEmpty(&c.quotaProject, os.Getenv(EnvQuotaProject))
+		setIfNotEmpty(&c.impersonateServiceAccount, os.Getenv(EnvImpersonateServiceAccount))
+		setIfNotEmpty(&c.protectionLabel, os.Getenv(EnvProtectionLabel))
+		for _, service := range services {
+			if endpoint := os.Getenv(endpointEnvVar(service)); endpoint != "" {
+				c.endpoints[service] = endpoint
+			}
+		}
+		if v := os.Getenv(EnvReadOnly); v != "" {
+			readOnly, err := strconv.ParseBool(v)
+			if err != nil {
+				log.Printf("Ignoring invalid %s=%q: %v", EnvReadOnly, v, err)
+				return
+			}
+			c.readOnly = readOnly
+		}
+	}
+}
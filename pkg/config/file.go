@@ -0,0 +1,101 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"sigs.k8s.io/yaml"
+)
+
+// File is the YAML or JSON configuration file passed with --config.
+type File struct {
+	// Project is the default GCP project ID.
+	Project string `json:"project,omitempty"`
+	// Location is the default GCP region or zone.
+	Location string `json:"location,omitempty"`
+	// QuotaProject is the project used for quota and billing of API calls.
+	QuotaProject string `json:"quota_project,omitempty"`
+	// ImpersonateServiceAccount is the email of a service account API calls act as.
+	ImpersonateServiceAccount string `json:"impersonate_service_account,omitempty"`
+	// Endpoints overrides API endpoints by service: compute, container,
+	// logging, monitoring, recommender or resourcemanager.
+	Endpoints map[string]string `json:"endpoints,omitempty"`
+
+	// ReadOnly only registers tools that don't modify resources.
+	ReadOnly bool `json:"read_only,omitempty"`
+	// ProtectionLabel is the cluster label that protects clusters from
+	// deletion, DefaultProtectionLabel if empty.
+	ProtectionLabel string `json:"protection_label,omitempty"`
+	// Tools are filter rules selecting which tools are registered.
+	Tools []string `json:"tools,omitempty"`
+	// Prompts are filter rules selecting which prompts are registered.
+	Prompts []string `json:"prompts,omitempty"`
+
+	toolFilter   *Filter
+	promptFilter *Filter
+}
+
+// LoadFile reads and validates a configuration file. Unknown fields are rejected.
+func LoadFile(path string) (*File, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+	f := &File{}
+	if err := yaml.UnmarshalStrict(b, f); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %w", path, err)
+	}
+	for service := range f.Endpoints {
+		if !slices.Contains(services, service) {
+			return nil, fmt.Errorf("unknown service %q in endpoints of config file %s, must be one of %v", service, path, services)
+		}
+	}
+	if f.toolFilter, err = NewFilter(f.Tools); err != nil {
+		return nil, fmt.Errorf("invalid tools in config file %s: %w", path, err)
+	}
+	if f.promptFilter, err = NewFilter(f.Prompts); err != nil {
+		return nil, fmt.Errorf("invalid prompts in config file %s: %w", path, err)
+	}
+	return f, nil
+}
+
+// WithFile applies settings from a configuration file loaded with LoadFile.
+func WithFile(f *File) Option {
+	return func(c *Config) {
+		if f == nil {
+			return
+		}
+		setIfNotEmpty(&c.defaultProjectID, f.Project)
+		setIfNotEmpty(&c.defaultLocation, f.Location)
+		setIfNotEmpty(&c.quotaProject, f.QuotaProject)
+		setIfNotEmpty(&c.impersonateServiceAccount, f.ImpersonateServiceAccount)
+		for service, endpoint := range f.Endpoints {
+			c.endpoints[service] = endpoint
+		}
+		c.readOnly = c.readOnly || f.ReadOnly
+		setIfNotEmpty(&c.protectionLabel, f.ProtectionLabel)
+		c.toolFilter = f.toolFilter
+		c.promptFilter = f.promptFilter
+	}
+}
+
+func setIfNotEmpty(dst *string, value string) {
+	if value != "" {
+		*dst = value
+	}
+}
@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+)
+
+// Filter enables or disables tools or prompts using an ordered list of glob
+// rules. Each rule is matched against both the bare name (e.g. "create_cluster")
+// and the name qualified by its package (e.g. "cluster.create_cluster"). A rule
+// prefixed with "!" disables matching items. The last matching rule wins. If
+// the rules contain no enabling rule, items are enabled by default; otherwise
+// only items matched by an enabling rule are enabled.
+//
+// A nil Filter enables everything.
+type Filter struct {
+	rules          []filterRule
+	defaultEnabled bool
+}
+
+type filterRule struct {
+	pattern string
+	negate  bool
+}
+
+// NewFilter parses rules into a Filter.
+func NewFilter(rules []string) (*Filter, error) {
+	f := &Filter{defaultEnabled: true}
+	for _, r := range rules {
+		rule := filterRule{pattern: strings.TrimSpace(r)}
+		if strings.HasPrefix(rule.pattern, "!") {
+			rule.negate = true
+			rule.pattern = strings.TrimSpace(strings.TrimPrefix(rule.pattern, "!"))
+		}
+		if rule.pattern == "" {
+			return nil, fmt.Errorf("empty filter rule %q", r)
+		}
+		if _, err := path.Match(rule.pattern, ""); err != nil {
+			return nil, fmt.Errorf("invalid filter rule %q: %w", r, err)
+		}
+		if !rule.negate {
+			f.defaultEnabled = false
+		}
+		f.rules = append(f.rules, rule)
+	}
+	return f, nil
+}
+
+// Enabled reports whether the item name registered by package pkg is enabled.
+func (f *Filter) Enabled(pkg, name string) bool {
+	if f == nil {
+		return true
+	}
+	enabled := f.defaultEnabled
+	for _, r := range f.rules {
+		if r.matches(pkg, name) {
+			enabled = !r.negate
+		}
+	}
+	return enabled
+}
+
+// Validate returns an error if a rule doesn't match any of the known items,
+// which are given as a map from package name to item names.
+func (f *Filter) Validate(items map[string][]string) error {
+	if f == nil {
+		return nil
+	}
+	var unknown []string
+	for _, r := range f.rules {
+		found := false
+		for pkg, names := range items {
+			for _, name := range names {
+				if r.matches(pkg, name) {
+					found = true
+					break
+				}
+			}
+			if found {
+				break
+			}
+		}
+		if !found {
+			unknown = append(unknown, r.pattern)
+		}
+	}
+	if len(unknown) > 0 {
+		return fmt.Errorf("filter rules don't match any known name: %s", strings.Join(unknown, ", "))
+	}
+	return nil
+}
+
+// Disabled returns the sorted names of the items the filter disables, given as
+// a map from package name to item names. It fails if a rule doesn't match any
+// item, which usually means a typo in the configuration.
+func (f *Filter) Disabled(items map[string][]string) ([]string, error) {
+	if err := f.Validate(items); err != nil {
+		return nil, err
+	}
+	var disabled []string
+	for pkg, names := range items {
+		for _, name := range names {
+			if !f.Enabled(pkg, name) {
+				disabled = append(disabled, name)
+			}
+		}
+	}
+	slices.Sort(disabled)
+	return disabled, nil
+}
+
+func (r filterRule) matches(pkg, name string) bool {
+	if ok, _ := path.Match(r.pattern, name); ok {
+		return true
+	}
+	ok, _ := path.Match(r.pattern, pkg+"."+name)
+	return ok
+}
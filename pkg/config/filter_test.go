@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestFilterEnabled(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules []string
+		pkg   string
+		item  string
+		want  bool
+	}{
+		{"no rules", nil, "cluster", "create_cluster", true},
+		{"deny by name", []string{"!create_cluster"}, "cluster", "create_cluster", false},
+		{"deny other name", []string{"!create_cluster"}, "cluster", "get_cluster", true},
+		{"deny by package glob", []string{"!giq.*"}, "giq", "giq_generate_manifest", false},
+		{"allow list excludes others", []string{"logging.*"}, "cluster", "get_cluster", false},
+		{"allow list includes match", []string{"logging.*"}, "logging", "query_logs", true},
+		{"last rule wins", []string{"!cluster.*", "get_cluster"}, "cluster", "get_cluster", true},
+		{"last rule wins deny", []string{"*", "!cluster.*"}, "cluster", "get_cluster", false},
+		{"glob on name", []string{"!*_cluster"}, "cluster", "create_cluster", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFilter(tt.rules)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			if got := f.Enabled(tt.pkg, tt.item); got != tt.want {
+				t.Errorf("Enabled(%s, %s) = %v, want %v", tt.pkg, tt.item, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNilFilter(t *testing.T) {
+	var f *Filter
+	if !f.Enabled("cluster", "create_cluster") {
+		t.Errorf("nil Filter should enable everything")
+	}
+	if err := f.Validate(nil); err != nil {
+		t.Errorf("nil Filter Validate() error = %v", err)
+	}
+}
+
+func TestNewFilterInvalid(t *testing.T) {
+	for _, rules := range [][]string{{""}, {"!"}, {"[bad"}} {
+		if _, err := NewFilter(rules); err == nil {
+			t.Errorf("NewFilter(%q) should fail", rules)
+		}
+	}
+}
+
+func TestFilterValidate(t *testing.T) {
+	items := map[string][]string{
+		"cluster": {"get_cluster", "create_cluster"},
+		"logging": {"query_logs"},
+	}
+	f, err := NewFilter([]string{"logging.*", "!create_cluster"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Validate(items); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+
+	f, err = NewFilter([]string{"!create_clsuter", "monitoring.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := f.Validate(items); err == nil {
+		t.Errorf("Validate() should fail for unknown names")
+	}
+}
+
+func TestFilterDisabled(t *testing.T) {
+	items := map[string][]string{
+		"cluster": {"get_cluster", "create_cluster"},
+		"logging": {"query_logs"},
+	}
+	f, err := NewFilter([]string{"!create_cluster", "!logging.*"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := f.Disabled(items)
+	if err != nil {
+		t.Fatalf("Disabled() error = %v", err)
+	}
+	if diff := cmp.Diff([]string{"create_cluster", "query_logs"}, got); diff != "" {
+		t.Errorf("Disabled() mismatch (-want +got):\n%s", diff)
+	}
+
+	var nilFilter *Filter
+	if got, err := nilFilter.Disabled(items); err != nil || len(got) != 0 {
+		t.Errorf("Disabled() of a nil filter = %v, %v, want none", got, err)
+	}
+	if _, err := f.Disabled(map[string][]string{"cluster": {"get_cluster"}}); err == nil {
+		t.Errorf("Disabled() should fail for unknown names")
+	}
+}
+
+func TestLoadFile(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		p := filepath.Join(dir, name)
+		if err := os.WriteFile(p, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return p
+	}
+
+	yamlFile := write("config.yaml", "tools:\n- '!giq.*'\n- '!create_cluster'\nprompts:\n- '!cost.*'\n")
+	f, err := LoadFile(yamlFile)
+	if err != nil {
+		t.Fatalf("LoadFile() error = %v", err)
+	}
+	c := New("test", WithFile(f))
+	if c.ToolFilter().Enabled("giq", "giq_generate_manifest") {
+		t.Errorf("giq tools should be disabled")
+	}
+	if !c.ToolFilter().Enabled("cluster", "get_cluster") {
+		t.Errorf("get_cluster should be enabled")
+	}
+	if c.PromptFilter().Enabled("cost", "gke:cost") {
+		t.Errorf("cost prompts should be disabled")
+	}
+
+	jsonFile := write("config.json", `{"tools": ["logging.*"]}`)
+	if _, err := LoadFile(jsonFile); err != nil {
+		t.Errorf("LoadFile() JSON error = %v", err)
+	}
+
+	full := write("full.yaml", "project: p\nlocation: us-central1\nquota_project: q\nimpersonate_service_account: sa@p.iam.gserviceaccount.com\nendpoints:\n  container: container.example.com:443\nread_only: true\n")
+	if f, err := LoadFile(full); err != nil {
+		t.Errorf("LoadFile() error = %v", err)
+	} else if c := New("test", WithFile(f)); c.DefaultProjectID() != "p" || c.DefaultLocation() != "us-central1" || !c.ReadOnly() {
+		t.Errorf("LoadFile() settings not applied: %+v", c)
+	}
+
+	for name, content := range map[string]string{
+		"unknown.yaml":  "tool: ['x']\n",
+		"endpoint.yaml": "endpoints:\n  storage: storage.example.com:443\n",
+		"badrule.yaml":  "tools: ['[bad']\n",
+		"invalid.yaml":  "tools: [\n",
+	} {
+		if _, err := LoadFile(write(name, content)); err == nil {
+			t.Errorf("LoadFile(%s) should fail", name)
+		}
+	}
+	if _, err := LoadFile(filepath.Join(dir, "missing.yaml")); err == nil {
+		t.Errorf("LoadFile() should fail for a missing file")
+	}
+}
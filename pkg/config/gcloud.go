@@ -0,0 +1,158 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// Environment variables read by gcloud that are honored when reading its
+// configuration.
+const (
+	envCloudSDKConfig           = "CLOUDSDK_CONFIG"
+	envCloudSDKActiveConfigName = "CLOUDSDK_ACTIVE_CONFIG_NAME"
+)
+
+// ErrGcloudPropertyNotSet is returned, wrapped in a *GcloudConfigError, when
+// a property isn't set in the active gcloud configuration.
+var ErrGcloudPropertyNotSet = errors.New("property not set")
+
+// GcloudConfigError describes a failure to read a gcloud property and the
+// source that was consulted.
+type GcloudConfigError struct {
+	// Property is the property that was read, e.g. "core/project".
+	Property string
+	// Source is the environment variable or configuration file that was read.
+	Source string
+	Err    error
+}
+
+func (e *GcloudConfigError) Error() string {
+	return fmt.Sprintf("failed to read gcloud property %s from %s: %v", e.Property, e.Source, e.Err)
+}
+
+func (e *GcloudConfigError) Unwrap() error {
+	return e.Err
+}
+
+// getGcloudConfig returns a property of the active gcloud configuration, such
+// as "core/project". Like gcloud, a CLOUDSDK_<SECTION>_<NAME> environment
+// variable takes precedence over the configuration file.
+func getGcloudConfig(key string) (string, error) {
+	section, name, ok := strings.Cut(key, "/")
+	if !ok {
+		return "", fmt.Errorf("invalid gcloud property %q, want section/name", key)
+	}
+
+	envVar := "CLOUDSDK_" + strings.ToUpper(section) + "_" + strings.ToUpper(name)
+	if v, ok := os.LookupEnv(envVar); ok {
+		if v = strings.TrimSpace(v); v != "" {
+			return v, nil
+		}
+		return "", &GcloudConfigError{Property: key, Source: "environment variable " + envVar, Err: ErrGcloudPropertyNotSet}
+	}
+
+	dir, err := gcloudConfigDir()
+	if err != nil {
+		return "", &GcloudConfigError{Property: key, Source: envCloudSDKConfig, Err: err}
+	}
+	path := filepath.Join(dir, "configurations", "config_"+activeGcloudConfigName(dir))
+	properties, err := readINI(path)
+	if err != nil {
+		return "", &GcloudConfigError{Property: key, Source: path, Err: err}
+	}
+	v := properties[section][name]
+	if v == "" {
+		return "", &GcloudConfigError{Property: key, Source: path, Err: ErrGcloudPropertyNotSet}
+	}
+	return v, nil
+}
+
+// gcloudConfigDir returns gcloud's configuration directory.
+func gcloudConfigDir() (string, error) {
+	if dir := os.Getenv(envCloudSDKConfig); dir != "" {
+		return dir, nil
+	}
+	if runtime.GOOS == "windows" {
+		if appData := os.Getenv("APPDATA"); appData != "" {
+			return filepath.Join(appData, "gcloud"), nil
+		}
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".config", "gcloud"), nil
+}
+
+// activeGcloudConfigName returns the name of the active gcloud configuration.
+func activeGcloudConfigName(dir string) string {
+	if name := os.Getenv(envCloudSDKActiveConfigName); name != "" {
+		return name
+	}
+	b, err := os.ReadFile(filepath.Join(dir, "active_config"))
+	if err == nil {
+		if name := strings.TrimSpace(string(b)); name != "" {
+			return name
+		}
+	}
+	return "default"
+}
+
+// readINI parses the INI file format used by gcloud configurations into a map
+// from section to property values.
+func readINI(path string) (map[string]map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = f.Close() }()
+
+	sections := map[string]map[string]string{}
+	var current map[string]string
+	scanner := bufio.NewScanner(f)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") || strings.HasPrefix(line, ";") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			name := strings.TrimSpace(line[1 : len(line)-1])
+			if sections[name] == nil {
+				sections[name] = map[string]string{}
+			}
+			current = sections[name]
+			continue
+		}
+		key, value, ok := strings.Cut(line, "=")
+		if !ok {
+			key, value, ok = strings.Cut(line, ":")
+		}
+		if !ok || current == nil {
+			return nil, fmt.Errorf("line %d: invalid property %q", lineNum, line)
+		}
+		current[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return sections, nil
+}
@@ -0,0 +1,172 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package config
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// writeGcloudConfig creates a gcloud configuration directory with the given
+// configurations and points CLOUDSDK_CONFIG at it.
+func writeGcloudConfig(t *testing.T, active string, configs map[string]string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "configurations"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if active != "" {
+		if err := os.WriteFile(filepath.Join(dir, "active_config"), []byte(active+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	for name, content := range configs {
+		if err := os.WriteFile(filepath.Join(dir, "configurations", "config_"+name), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv(envCloudSDKConfig, dir)
+	t.Setenv(envCloudSDKActiveConfigName, "")
+	_ = os.Unsetenv(envCloudSDKActiveConfigName)
+	for _, v := range []string{"CLOUDSDK_CORE_PROJECT", "CLOUDSDK_COMPUTE_REGION", "CLOUDSDK_COMPUTE_ZONE"} {
+		t.Setenv(v, "")
+		_ = os.Unsetenv(v)
+	}
+	return dir
+}
+
+const (
+	defaultGcloudConfig = `[core]
+account = user@example.com
+project = default-project
+
+[compute]
+region = us-central1
+zone = us-central1-a
+`
+	otherGcloudConfig = `# comment
+[core]
+project = other-project
+[compute]
+zone = europe-west1-b
+`
+)
+
+func TestGetGcloudConfig(t *testing.T) {
+	writeGcloudConfig(t, "other", map[string]string{
+		"default": defaultGcloudConfig,
+		"other":   otherGcloudConfig,
+	})
+
+	tests := []struct {
+		name string
+		key  string
+		want string
+	}{
+		{"project from active config", "core/project", "other-project"},
+		{"zone from active config", "compute/zone", "europe-west1-b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := getGcloudConfig(tt.key)
+			if err != nil {
+				t.Fatalf("getGcloudConfig(%q) error = %v", tt.key, err)
+			}
+			if got != tt.want {
+				t.Errorf("getGcloudConfig(%q) = %q, want %q", tt.key, got, tt.want)
+			}
+		})
+	}
+
+	if got := getDefaultLocation(); got != "europe-west1-b" {
+		t.Errorf("getDefaultLocation() = %q, want zone fallback europe-west1-b", got)
+	}
+}
+
+func TestGetGcloudConfigActiveConfigEnv(t *testing.T) {
+	writeGcloudConfig(t, "other", map[string]string{
+		"default": defaultGcloudConfig,
+		"other":   otherGcloudConfig,
+	})
+	t.Setenv(envCloudSDKActiveConfigName, "default")
+
+	if got := getDefaultProjectID(); got != "default-project" {
+		t.Errorf("getDefaultProjectID() = %q, want default-project", got)
+	}
+	if got := getDefaultLocation(); got != "us-central1" {
+		t.Errorf("getDefaultLocation() = %q, want us-central1", got)
+	}
+}
+
+func TestGetGcloudConfigDefaultName(t *testing.T) {
+	writeGcloudConfig(t, "", map[string]string{"default": defaultGcloudConfig})
+
+	if got := getDefaultProjectID(); got != "default-project" {
+		t.Errorf("getDefaultProjectID() = %q, want default-project", got)
+	}
+}
+
+func TestGetGcloudConfigEnvOverride(t *testing.T) {
+	writeGcloudConfig(t, "", map[string]string{"default": defaultGcloudConfig})
+	t.Setenv("CLOUDSDK_CORE_PROJECT", "env-project")
+
+	if got := getDefaultProjectID(); got != "env-project" {
+		t.Errorf("getDefaultProjectID() = %q, want env-project", got)
+	}
+}
+
+func TestGetGcloudConfigErrors(t *testing.T) {
+	dir := writeGcloudConfig(t, "missing", map[string]string{"other": otherGcloudConfig})
+
+	_, err := getGcloudConfig("core/project")
+	var configErr *GcloudConfigError
+	if !errors.As(err, &configErr) {
+		t.Fatalf("getGcloudConfig() error = %v, want *GcloudConfigError", err)
+	}
+	if want := filepath.Join(dir, "configurations", "config_missing"); configErr.Source != want {
+		t.Errorf("Source = %q, want %q", configErr.Source, want)
+	}
+	if !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("getGcloudConfig() error = %v, want os.ErrNotExist", err)
+	}
+
+	t.Setenv(envCloudSDKActiveConfigName, "other")
+	_, err = getGcloudConfig("compute/region")
+	if !errors.Is(err, ErrGcloudPropertyNotSet) {
+		t.Errorf("getGcloudConfig() error = %v, want ErrGcloudPropertyNotSet", err)
+	}
+
+	t.Setenv("CLOUDSDK_CORE_PROJECT", "")
+	_, err = getGcloudConfig("core/project")
+	if !errors.As(err, &configErr) || configErr.Source != "environment variable CLOUDSDK_CORE_PROJECT" {
+		t.Errorf("getGcloudConfig() error = %v, want source CLOUDSDK_CORE_PROJECT", err)
+	}
+
+	if _, err := getGcloudConfig("project"); err == nil {
+		t.Errorf("getGcloudConfig(\"project\") error = nil, want invalid property error")
+	}
+}
+
+func TestReadINIInvalid(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("project = p\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := readINI(path); err == nil {
+		t.Errorf("readINI() error = nil, want error for property outside a section")
+	}
+}
@@ -0,0 +1,155 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package credentials selects the Google credentials used for API calls.
+//
+// By default clients use Application Default Credentials. The server can
+// instead impersonate a service account, and in HTTP mode each request can
+// carry the caller's own OAuth access token, so API calls act as that caller.
+package credentials
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+
+	"cloud.google.com/go/auth"
+	"cloud.google.com/go/auth/credentials"
+	"cloud.google.com/go/auth/credentials/impersonate"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// ForwardedAccessTokenHeader is the HTTP header carrying the caller's Google
+// OAuth access token, as set by proxies such as oauth2-proxy.
+const ForwardedAccessTokenHeader = "X-Forwarded-Access-Token"
+
+const cloudPlatformScope = "https://www.googleapis.com/auth/cloud-platform"
+
+type accessTokenKey struct{}
+
+// ContextWithAccessToken returns a context whose API calls use token instead
+// of the server's credentials.
+func ContextWithAccessToken(ctx context.Context, token string) context.Context {
+	return context.WithValue(ctx, accessTokenKey{}, token)
+}
+
+// AccessTokenFromContext returns the access token set by ContextWithAccessToken.
+func AccessTokenFromContext(ctx context.Context) (string, bool) {
+	token, ok := ctx.Value(accessTokenKey{}).(string)
+	return token, ok && token != ""
+}
+
+// New returns credentials that use the access token in the call's context if
+// there is one, and otherwise the server's own credentials: ADC, or the given
+// service account impersonated with ADC when it isn't empty.
+//
+// The server's credentials are detected on first use, so a missing ADC only
+// fails calls that need it.
+//
+// Clients ask for the quota project once, when they're created. With
+// forwardAccessTokens the server's quota project isn't offered, since every
+// caller's calls would be billed to it; only a configured quota project is
+// sent then.
+func New(impersonateServiceAccount string, forwardAccessTokens bool) *auth.Credentials {
+	p := &tokenProvider{impersonateServiceAccount: impersonateServiceAccount}
+	opts := &auth.CredentialsOptions{TokenProvider: p}
+	if !forwardAccessTokens {
+		opts.QuotaProjectIDProvider = auth.CredentialsPropertyFunc(p.quotaProjectID)
+	}
+	return auth.NewCredentials(opts)
+}
+
+type tokenProvider struct {
+	impersonateServiceAccount string
+
+	once    sync.Once
+	base    *auth.Credentials
+	baseErr error
+}
+
+func (p *tokenProvider) Token(ctx context.Context) (*auth.Token, error) {
+	if token, ok := AccessTokenFromContext(ctx); ok {
+		return &auth.Token{Value: token, Type: "Bearer"}, nil
+	}
+	base, err := p.baseCredentials()
+	if err != nil {
+		return nil, err
+	}
+	return base.Token(ctx)
+}
+
+// quotaProjectID returns the quota project of the server's credentials, or
+// none when they can't be found, so that creating a client doesn't fail.
+func (p *tokenProvider) quotaProjectID(ctx context.Context) (string, error) {
+	if _, ok := AccessTokenFromContext(ctx); ok {
+		return "", nil
+	}
+	base, err := p.baseCredentials()
+	if err != nil {
+		return "", nil
+	}
+	return base.QuotaProjectID(ctx)
+}
+
+func (p *tokenProvider) baseCredentials() (*auth.Credentials, error) {
+	p.once.Do(func() {
+		if p.impersonateServiceAccount == "" {
+			p.base, p.baseErr = credentials.DetectDefault(&credentials.DetectOptions{
+				Scopes: []string{cloudPlatformScope},
+			})
+			return
+		}
+		p.base, p.baseErr = impersonate.NewCredentials(&impersonate.CredentialsOptions{
+			TargetPrincipal: p.impersonateServiceAccount,
+			Scopes:          []string{cloudPlatformScope},
+		})
+		if p.baseErr != nil {
+			p.baseErr = fmt.Errorf("failed to impersonate %s: %w", p.impersonateServiceAccount, p.baseErr)
+		}
+	})
+	return p.base, p.baseErr
+}
+
+// ForwardAccessToken returns MCP middleware that makes API calls of each tool
+// call use the access token from the ForwardedAccessTokenHeader of its HTTP
+// request. Tool calls without the header are rejected, so they never fall
+// back to the server's identity.
+func ForwardAccessToken() mcp.Middleware {
+	return func(next mcp.MethodHandler) mcp.MethodHandler {
+		return func(ctx context.Context, method string, req mcp.Request) (mcp.Result, error) {
+			if method != "tools/call" {
+				return next(ctx, method, req)
+			}
+			token := forwardedAccessToken(req)
+			if token == "" {
+				return nil, fmt.Errorf("missing %s header: this server acts as the caller and requires the caller's Google access token", ForwardedAccessTokenHeader)
+			}
+			return next(ContextWithAccessToken(ctx, token), method, req)
+		}
+	}
+}
+
+func forwardedAccessToken(req mcp.Request) string {
+	extra := req.GetExtra()
+	if extra == nil || extra.Header == nil {
+		return ""
+	}
+	token := strings.TrimSpace(extra.Header.Get(ForwardedAccessTokenHeader))
+	// Accept the token with or without the "Bearer " prefix.
+	if len(token) > len("bearer ") && strings.EqualFold(token[:len("bearer ")], "bearer ") {
+		token = strings.TrimSpace(token[len("bearer "):])
+	}
+	return token
+}
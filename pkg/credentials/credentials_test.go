@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package credentials
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+
+	container "cloud.google.com/go/container/apiv1"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/api/option"
+)
+
+func TestNewUsesContextToken(t *testing.T) {
+	creds := New("sa@example.iam.gserviceaccount.com", true)
+	ctx := ContextWithAccessToken(context.Background(), "caller-token")
+
+	token, err := creds.Token(ctx)
+	if err != nil {
+		t.Fatalf("Token() error = %v", err)
+	}
+	if token.Value != "caller-token" {
+		t.Errorf("Token() = %q, want caller-token", token.Value)
+	}
+	if qp, err := creds.QuotaProjectID(ctx); err != nil || qp != "" {
+		t.Errorf("QuotaProjectID() = %q, %v, want empty for a forwarded token", qp, err)
+	}
+}
+
+// setADC points Application Default Credentials at a file with the given
+// content, or at a missing file when content is empty.
+func setADC(t *testing.T, content string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "adc.json")
+	if content != "" {
+		if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	t.Setenv("GOOGLE_APPLICATION_CREDENTIALS", path)
+	t.Setenv("GOOGLE_CLOUD_QUOTA_PROJECT", "")
+}
+
+func TestNewWithoutADC(t *testing.T) {
+	setADC(t, "")
+	for _, forward := range []bool{true, false} {
+		creds := New("", forward)
+		client, err := container.NewClusterManagerClient(context.Background(), option.WithAuthCredentials(creds))
+		if err != nil {
+			t.Fatalf("NewClusterManagerClient() with forwardAccessTokens=%v error = %v", forward, err)
+		}
+		_ = client.Close()
+		if _, err := creds.Token(context.Background()); err == nil {
+			t.Errorf("Token() without ADC or a forwarded token succeeded, want error")
+		}
+	}
+}
+
+func TestNewQuotaProject(t *testing.T) {
+	setADC(t, `{"type": "authorized_user", "client_id": "id", "client_secret": "secret", "refresh_token": "token", "quota_project_id": "adc-quota"}`)
+	tests := []struct {
+		forward bool
+		want    string
+	}{
+		{forward: false, want: "adc-quota"},
+		{forward: true, want: ""},
+	}
+	for _, tt := range tests {
+		qp, err := New("", tt.forward).QuotaProjectID(context.Background())
+		if err != nil || qp != tt.want {
+			t.Errorf("QuotaProjectID() with forwardAccessTokens=%v = %q, %v, want %q", tt.forward, qp, err, tt.want)
+		}
+	}
+}
+
+func TestForwardAccessToken(t *testing.T) {
+	tests := []struct {
+		name      string
+		method    string
+		header    string
+		wantToken string
+		wantErr   bool
+	}{
+		{name: "token", method: "tools/call", header: "abc", wantToken: "abc"},
+		{name: "bearer prefix", method: "tools/call", header: "Bearer abc", wantToken: "abc"},
+		{name: "missing header", method: "tools/call", wantErr: true},
+		{name: "other method", method: "tools/list"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var gotToken string
+			next := func(ctx context.Context, _ string, _ mcp.Request) (mcp.Result, error) {
+				gotToken, _ = AccessTokenFromContext(ctx)
+				return nil, nil
+			}
+			header := http.Header{}
+			if tt.header != "" {
+				header.Set(ForwardedAccessTokenHeader, tt.header)
+			}
+			req := &mcp.CallToolRequest{Extra: &mcp.RequestExtra{Header: header}}
+
+			_, err := ForwardAccessToken()(next)(context.Background(), tt.method, req)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("middleware error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if gotToken != tt.wantToken {
+				t.Errorf("token in context = %q, want %q", gotToken, tt.wantToken)
+			}
+		})
+	}
+}
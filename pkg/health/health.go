@@ -0,0 +1,126 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package health provides liveness and readiness HTTP handlers.
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Check is a named readiness check.
+type Check struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Status is the JSON body served by the health handlers.
+type Status struct {
+	Status string            `json:"status"`
+	Checks map[string]string `json:"checks,omitempty"`
+}
+
+const (
+	statusOK       = "ok"
+	statusFailing  = "failing"
+	statusDraining = "draining"
+)
+
+// Liveness returns a handler that reports the process is up.
+func Liveness() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		writeStatus(w, http.StatusOK, Status{Status: statusOK})
+	})
+}
+
+// Readiness serves the result of a set of checks. Results are cached for a
+// TTL so that frequent probes don't turn into a stream of API calls.
+type Readiness struct {
+	checks  []Check
+	ttl     time.Duration
+	timeout time.Duration
+
+	draining atomic.Bool
+
+	mu      sync.Mutex
+	last    Status
+	checked time.Time
+}
+
+// NewReadiness returns a Readiness running the given checks at most once per ttl.
+func NewReadiness(ttl time.Duration, checks ...Check) *Readiness {
+	return &Readiness{
+		checks:  checks,
+		ttl:     ttl,
+		timeout: 10 * time.Second,
+	}
+}
+
+// SetDraining marks the server as shutting down, after which it reports not ready.
+func (r *Readiness) SetDraining() {
+	r.draining.Store(true)
+}
+
+// ServeHTTP implements http.Handler.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.draining.Load() {
+		writeStatus(w, http.StatusServiceUnavailable, Status{Status: statusDraining})
+		return
+	}
+	status := r.status(req.Context())
+	code := http.StatusOK
+	if status.Status != statusOK {
+		code = http.StatusServiceUnavailable
+	}
+	writeStatus(w, code, status)
+}
+
+func (r *Readiness) status(ctx context.Context) Status {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if !r.checked.IsZero() && time.Since(r.checked) < r.ttl {
+		return r.last
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	status := Status{Status: statusOK, Checks: map[string]string{}}
+	for _, c := range r.checks {
+		if err := c.Run(ctx); err != nil {
+			status.Status = statusFailing
+			status.Checks[c.Name] = err.Error()
+			continue
+		}
+		status.Checks[c.Name] = statusOK
+	}
+	r.last = status
+	r.checked = time.Now()
+	return status
+}
+
+func writeStatus(w http.ResponseWriter, code int, status Status) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	if err := json.NewEncoder(w).Encode(status); err != nil {
+		log.Printf("Failed to write health status: %v", err)
+	}
+}
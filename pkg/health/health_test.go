@@ -0,0 +1,77 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func serve(t *testing.T, h http.Handler) (int, Status) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	var status Status
+	if err := json.Unmarshal(rec.Body.Bytes(), &status); err != nil {
+		t.Fatalf("failed to decode status: %v", err)
+	}
+	return rec.Code, status
+}
+
+func TestLiveness(t *testing.T) {
+	code, status := serve(t, Liveness())
+	if code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("Liveness() = %d %+v, want 200 ok", code, status)
+	}
+}
+
+func TestReadiness(t *testing.T) {
+	calls := 0
+	var apiErr error
+	r := NewReadiness(time.Hour,
+		Check{Name: "adc", Run: func(context.Context) error { return nil }},
+		Check{Name: "api", Run: func(context.Context) error { calls++; return apiErr }},
+	)
+
+	code, status := serve(t, r)
+	if code != http.StatusOK || status.Status != "ok" {
+		t.Errorf("Readiness() = %d %+v, want 200 ok", code, status)
+	}
+
+	// Cached result is served without re-running checks.
+	apiErr = errors.New("unreachable")
+	if code, _ := serve(t, r); code != http.StatusOK {
+		t.Errorf("Readiness() = %d, want cached 200", code)
+	}
+	if calls != 1 {
+		t.Errorf("checks ran %d times, want 1", calls)
+	}
+
+	r.checked = time.Time{}
+	code, status = serve(t, r)
+	if code != http.StatusServiceUnavailable || status.Checks["api"] != "unreachable" || status.Checks["adc"] != "ok" {
+		t.Errorf("Readiness() = %d %+v, want 503 with failing api check", code, status)
+	}
+
+	r.SetDraining()
+	if code, status := serve(t, r); code != http.StatusServiceUnavailable || status.Status != "draining" {
+		t.Errorf("Readiness() = %d %+v, want 503 draining", code, status)
+	}
+}
@@ -23,25 +23,45 @@ import (
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/prompts/deploy"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/prompts/upgraderiskreport"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/prompts/upgradesbestpracticesriskreport"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/registry"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
 type installer func(ctx context.Context, s *mcp.Server, c *config.Config) error
 
+// promptPackage is a set of prompts installed together. Its name is used to
+// select the package's prompts in filter rules, e.g. "cost.*".
+type promptPackage struct {
+	name    string
+	install installer
+}
+
 // Install registers all prompt handlers with the MCP server.
 func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
-	installers := []installer{
-		cost.Install,
-		deploy.Install,
-		upgraderiskreport.Install,
-		upgradesbestpracticesriskreport.Install,
+	packages := []promptPackage{
+		{"cost", cost.Install},
+		{"deploy", deploy.Install},
+		{"upgraderiskreport", upgraderiskreport.Install},
+		{"upgradesbestpracticesriskreport", upgradesbestpracticesriskreport.Install},
 	}
 
-	for _, installer := range installers {
-		if err := installer(ctx, s, c); err != nil {
+	byPackage := map[string][]string{}
+	registered := map[string]bool{}
+	for _, p := range packages {
+		if err := p.install(ctx, s, c); err != nil {
 			return err
 		}
+		prompts, err := registry.Prompts(ctx, s)
+		if err != nil {
+			return err
+		}
+		for _, prompt := range prompts {
+			if !registered[prompt.Name] {
+				registered[prompt.Name] = true
+				byPackage[p.name] = append(byPackage[p.name], prompt.Name)
+			}
+		}
 	}
 
-	return nil
+	return registry.RemoveDisabled("prompt", c.PromptFilter(), byPackage, s.RemovePrompts)
 }
@@ -43,8 +43,8 @@ Produce a report outlining potential risks, and actionable recommendations to en
 **4. Handling Missing Target Version:**
 If 'Target Version' is not provided:
   a. State that the target version is required.
-  b. Use ` + "`gcloud container get-server-config`" + ` to fetch available GKE versions.
-  c. Filter this list to show only versions NEWER than the cluster's current control plane version and compatible with the cluster's release channel.
+  b. Use the ` + "`get_server_config`" + ` tool with the cluster name to fetch the available GKE versions NEWER than the cluster's current control plane version.
+  c. Keep only the versions of the cluster's release channel, if it is enrolled in one.
   d. Present these versions to the user to help them choose a 'Target Version'.
 
 **5. Information Gathering & Tools:**
@@ -50,7 +50,7 @@ Assume you have the ability to run the following commands to gather necessary in
 
 **Context:** If a cluster doesn't have a maintenance window set, GKE can perform automatic upgrades at any time. Upgrades are rolled out across different regions over several days, so the exact timing of an automatic upgrade without a maintenance window can be unpredictable. A significant number of clusters do not have a maintenance window set, which can lead to unexpected disruptions. There is no default maintenance window configured when a GKE cluster is created. User must explicitly create a maintenance window to control when automatic upgrades can occur.
 
-**Analysis:** You must check whether the cluster has maintenance window set and it is not allowing upgrades at any time.
+**Analysis:** You must check whether the cluster has maintenance window set and it is not allowing upgrades at any time. Use the ` + "`get_maintenance_policy`" + ` tool to read the window, the exclusions and the next windows. As mitigation, propose a window the user can apply with the ` + "`set_maintenance_policy`" + ` tool.
 
 **5.2. Pod Disruption Budgets (PDBs)**
 
@@ -0,0 +1,92 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package registry inspects and filters the tools and prompts registered with
+// an MCP server.
+//
+// The server doesn't expose its feature sets directly, so they are listed over
+// an in-memory client session.
+package registry
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// Tools returns the tools registered with s.
+func Tools(ctx context.Context, s *mcp.Server) ([]*mcp.Tool, error) {
+	var tools []*mcp.Tool
+	err := withSession(ctx, s, func(cs *mcp.ClientSession) error {
+		for t, err := range cs.Tools(ctx, nil) {
+			if err != nil {
+				return fmt.Errorf("failed to list tools: %w", err)
+			}
+			tools = append(tools, t)
+		}
+		return nil
+	})
+	return tools, err
+}
+
+// Prompts returns the prompts registered with s.
+func Prompts(ctx context.Context, s *mcp.Server) ([]*mcp.Prompt, error) {
+	var prompts []*mcp.Prompt
+	err := withSession(ctx, s, func(cs *mcp.ClientSession) error {
+		for p, err := range cs.Prompts(ctx, nil) {
+			if err != nil {
+				return fmt.Errorf("failed to list prompts: %w", err)
+			}
+			prompts = append(prompts, p)
+		}
+		return nil
+	})
+	return prompts, err
+}
+
+// RemoveDisabled removes the items of the given kind, "tool" or "prompt", that
+// f disables. byPackage maps package names to the names of their items and
+// remove unregisters items, like (*mcp.Server).RemoveTools.
+func RemoveDisabled(kind string, f *config.Filter, byPackage map[string][]string, remove func(names ...string)) error {
+	disabled, err := f.Disabled(byPackage)
+	if err != nil {
+		return fmt.Errorf("invalid %s configuration: %w", kind, err)
+	}
+	if len(disabled) > 0 {
+		log.Printf("Disabled by configuration: removing %ss %v", kind, disabled)
+		remove(disabled...)
+	}
+	return nil
+}
+
+func withSession(ctx context.Context, s *mcp.Server, f func(cs *mcp.ClientSession) error) error {
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer func() { _ = ss.Close() }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "gke-mcp-registry"}, nil)
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		return fmt.Errorf("failed to connect to server: %w", err)
+	}
+	defer func() { _ = cs.Close() }()
+
+	return f(cs)
+}
@@ -0,0 +1,64 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package toolresult builds tool results that pair structured output with a
+// concise text summary.
+//
+// The MCP SDK publishes a tool's output schema from the Go type its handler
+// returns and sends the returned value as structuredContent. Clients that only
+// read text content still get the result, as JSON or as the tool's own text
+// form of it, after the summary.
+package toolresult
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+)
+
+// New returns a result whose text content is summary followed by out as JSON.
+func New(summary string, out any) (*mcp.CallToolResult, error) {
+	b, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal tool output: %w", err)
+	}
+	return NewText(summary, string(b)), nil
+}
+
+// NewText returns a result whose text content is summary followed by text, a
+// compact text form of the tool's output, such as log lines or file contents
+// that would otherwise be escaped inside JSON.
+func NewText(summary, text string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{Content: []mcp.Content{
+		&mcp.TextContent{Text: summary},
+		&mcp.TextContent{Text: text},
+	}}
+}
+
+// ProtoMap converts m to a JSON object using protobuf's JSON mapping, so
+// enums are names and well-known types keep their canonical form.
+func ProtoMap(m proto.Message) (map[string]any, error) {
+	b, err := protojson.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
@@ -0,0 +1,86 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package toolresult
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestNew(t *testing.T) {
+	type output struct {
+		Name string `json:"name"`
+	}
+	tests := []struct {
+		name string
+		out  output
+		want []string
+	}{
+		{
+			name: "small output",
+			out:  output{Name: "my-cluster"},
+			want: []string{"Found 1 cluster.", `{"name":"my-cluster"}`},
+		},
+		{
+			name: "large output",
+			out:  output{Name: strings.Repeat("x", 8<<10)},
+			want: []string{"Found 1 cluster.", `{"name":"` + strings.Repeat("x", 8<<10) + `"}`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			res, err := New("Found 1 cluster.", tt.out)
+			if err != nil {
+				t.Fatalf("New() error = %v", err)
+			}
+			var got []string
+			for _, c := range res.Content {
+				got = append(got, c.(*mcp.TextContent).Text)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("New() content mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewText(t *testing.T) {
+	res := NewText("Got 2 lines.", "a\nb\n")
+	var got []string
+	for _, c := range res.Content {
+		got = append(got, c.(*mcp.TextContent).Text)
+	}
+	if diff := cmp.Diff([]string{"Got 2 lines.", "a\nb\n"}, got); diff != "" {
+		t.Errorf("NewText() content mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestProtoMap(t *testing.T) {
+	m, err := structpb.NewStruct(map[string]any{"a": "b", "n": 1.0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	got, err := ProtoMap(m)
+	if err != nil {
+		t.Fatalf("ProtoMap() error = %v", err)
+	}
+	if diff := cmp.Diff(map[string]any{"a": "b", "n": 1.0}, got); diff != "" {
+		t.Errorf("ProtoMap() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,130 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type deleteClusterArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location    string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster     string `json:"cluster" jsonschema:"GKE cluster name. Never select it yourself, the user must provide it."`
+	ConfirmName string `json:"confirm_name" jsonschema:"The fully qualified name of the cluster, projects/PROJECT/locations/LOCATION/clusters/NAME, exactly as confirmed by the user."`
+}
+
+type deleteClusterOutput struct {
+	Cluster   string           `json:"cluster" jsonschema:"Full resource name of the deleted cluster."`
+	Operation operationSummary `json:"operation"`
+}
+
+// installDelete registers the delete_cluster tool, unless the server is
+// read-only. Read-only mode removes every mutating tool anyway; checking here
+// too keeps deletion out even if the annotations change.
+func (h *handlers) installDelete(s *mcp.Server) {
+	if h.c.ReadOnly() {
+		return
+	}
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "delete_cluster",
+		Description: "Delete a GKE cluster with all its nodes and workloads. This can't be undone. " +
+			"The user must confirm the fully qualified cluster name, which is passed back in confirm_name. " +
+			"Clusters with the deletion protection label are refused.",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.deleteCluster)
+}
+
+func (h *handlers) deleteCluster(ctx context.Context, req *mcp.CallToolRequest, args *deleteClusterArgs) (*mcp.CallToolResult, *deleteClusterOutput, error) {
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.ConfirmName != name {
+		return nil, nil, fmt.Errorf("confirm_name %q doesn't match the cluster to delete: ask the user to confirm deleting %s and pass that exact name as confirm_name", args.ConfirmName, name)
+	}
+
+	cluster, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	if err := checkDeletionProtection(cluster, h.c.ProtectionLabel()); err != nil {
+		return nil, nil, err
+	}
+	if req != nil {
+		if err := confirmDeletion(ctx, req.Session, name); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	op, err := h.cmClient.DeleteCluster(ctx, &containerpb.DeleteClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &deleteClusterOutput{Cluster: name, Operation: newOperationSummary(op)}
+	summary := fmt.Sprintf("Started deleting cluster %s, operation %s is %s. Use wait_operation to wait for it.", name, out.Operation.Name, out.Operation.Status)
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// checkDeletionProtection refuses clusters carrying the protection label,
+// unless its value is "false".
+func checkDeletionProtection(c *containerpb.Cluster, label string) error {
+	if label == "" {
+		return nil
+	}
+	if v, ok := c.GetResourceLabels()[label]; ok && v != "false" {
+		return fmt.Errorf("cluster %s is protected from deletion by its %s=%s label; remove the label first if it really should be deleted", c.GetName(), label, v)
+	}
+	return nil
+}
+
+// confirmDeletion asks the user to confirm deleting the cluster through MCP
+// elicitation, if the client supports it.
+func confirmDeletion(ctx context.Context, ss *mcp.ServerSession, name string) error {
+	if ss == nil {
+		return nil
+	}
+	if p := ss.InitializeParams(); p == nil || p.Capabilities == nil || p.Capabilities.Elicitation == nil {
+		return nil
+	}
+	res, err := ss.Elicit(ctx, &mcp.ElicitParams{
+		Message: fmt.Sprintf("Delete GKE cluster %s? All its nodes and workloads will be deleted. This can't be undone.", name),
+		RequestedSchema: map[string]any{
+			"type": "object",
+			"properties": map[string]any{
+				"delete": map[string]any{
+					"type":        "boolean",
+					"title":       "Delete the cluster",
+					"description": "Check to delete " + name + ".",
+				},
+			},
+			"required": []string{"delete"},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to ask the user to confirm deleting %s: %w", name, err)
+	}
+	if res.Action != "accept" || res.Content["delete"] != true {
+		return fmt.Errorf("the user didn't confirm deleting cluster %s, it was not deleted", name)
+	}
+	return nil
+}
@@ -0,0 +1,120 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/registry"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestCheckDeletionProtection(t *testing.T) {
+	tests := []struct {
+		name    string
+		labels  map[string]string
+		label   string
+		wantErr bool
+	}{
+		{name: "unlabeled", label: config.DefaultProtectionLabel},
+		{name: "protected", labels: map[string]string{config.DefaultProtectionLabel: "true"}, label: config.DefaultProtectionLabel, wantErr: true},
+		{name: "protected with any value", labels: map[string]string{"keep": ""}, label: "keep", wantErr: true},
+		{name: "explicitly unprotected", labels: map[string]string{"keep": "false"}, label: "keep"},
+		{name: "protection disabled", labels: map[string]string{"keep": "true"}, label: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := &containerpb.Cluster{Name: "prod", ResourceLabels: tt.labels}
+			if err := checkDeletionProtection(c, tt.label); (err != nil) != tt.wantErr {
+				t.Errorf("checkDeletionProtection() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestInstallDeleteReadOnly(t *testing.T) {
+	t.Setenv(config.EnvProject, "p")
+	t.Setenv(config.EnvLocation, "l")
+	h := &handlers{c: config.New("test", config.WithEnv(), config.WithReadOnly(true))}
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	h.installDelete(s)
+
+	tools, err := registry.Tools(context.Background(), s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tools) > 0 {
+		t.Errorf("registered %d tools in read-only mode, want none", len(tools))
+	}
+}
+
+// callConfirmDeletion runs confirmDeletion in a tool call from a client
+// using the given elicitation handler, which may be nil.
+func callConfirmDeletion(t *testing.T, handler func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error)) error {
+	t.Helper()
+	ctx := context.Background()
+	var confirmErr error
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(s, &mcp.Tool{Name: "confirm"}, func(ctx context.Context, req *mcp.CallToolRequest, _ struct{}) (*mcp.CallToolResult, any, error) {
+		confirmErr = confirmDeletion(ctx, req.Session, "projects/p/locations/l/clusters/c")
+		return &mcp.CallToolResult{}, nil, nil
+	})
+	serverTransport, clientTransport := mcp.NewInMemoryTransports()
+	ss, err := s.Connect(ctx, serverTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = ss.Close() }()
+
+	client := mcp.NewClient(&mcp.Implementation{Name: "client"}, &mcp.ClientOptions{ElicitationHandler: handler})
+	cs, err := client.Connect(ctx, clientTransport, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer func() { _ = cs.Close() }()
+	if _, err := cs.CallTool(ctx, &mcp.CallToolParams{Name: "confirm"}); err != nil {
+		t.Fatal(err)
+	}
+	return confirmErr
+}
+
+func TestConfirmDeletion(t *testing.T) {
+	respond := func(action string, content map[string]any) func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+		return func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error) {
+			return &mcp.ElicitResult{Action: action, Content: content}, nil
+		}
+	}
+	tests := []struct {
+		name    string
+		handler func(context.Context, *mcp.ElicitRequest) (*mcp.ElicitResult, error)
+		wantErr bool
+	}{
+		{name: "no elicitation support"},
+		{name: "accepted", handler: respond("accept", map[string]any{"delete": true})},
+		{name: "accepted unchecked", handler: respond("accept", map[string]any{"delete": false}), wantErr: true},
+		{name: "declined", handler: respond("decline", nil), wantErr: true},
+		{name: "cancelled", handler: respond("cancel", nil), wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := callConfirmDeletion(t, tt.handler); (err != nil) != tt.wantErr {
+				t.Errorf("confirmDeletion() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
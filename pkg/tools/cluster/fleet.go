@@ -0,0 +1,306 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	resourcemanager "cloud.google.com/go/resourcemanager/apiv3"
+	resourcemanagerpb "cloud.google.com/go/resourcemanager/apiv3/resourcemanagerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/sync/errgroup"
+	"google.golang.org/api/iterator"
+)
+
+const (
+	defaultFleetParallelism = 8
+	maxFleetParallelism     = 32
+)
+
+type listFleetClustersArgs struct {
+	ProjectIDs   []string `json:"project_ids,omitempty" jsonschema:"GCP project IDs to list clusters in."`
+	Folder       string   `json:"folder,omitempty" jsonschema:"Folder ID, e.g. 123456789 or folders/123456789. Lists clusters in every project in the folder and its subfolders."`
+	Organization string   `json:"organization,omitempty" jsonschema:"Organization ID, e.g. 123456789 or organizations/123456789. Lists clusters in every project in the organization."`
+	Location     string   `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty to list all locations."`
+	View         string   `json:"view,omitempty" jsonschema:"How much of each cluster to return: 'summary' (default) or 'full'. Avoid 'full' for a fleet; use fields instead."`
+	Fields       string   `json:"fields,omitempty" jsonschema:"Only return these parts of each cluster resource, as a comma-separated protobuf field mask or a JSONPath expression, as in list_clusters."`
+	Parallelism  int      `json:"parallelism,omitempty" jsonschema:"Maximum number of projects listed concurrently. Defaults to 8, at most 32."`
+}
+
+// fleetError reports a project or folder that couldn't be listed.
+type fleetError struct {
+	Resource string `json:"resource" jsonschema:"The project or folder that couldn't be listed, e.g. projects/my-project."`
+	Error    string `json:"error"`
+}
+
+type listFleetClustersOutput struct {
+	Projects     []string      `json:"projects,omitempty" jsonschema:"Projects that were listed."`
+	Clusters     []clusterInfo `json:"clusters,omitempty"`
+	Errors       []fleetError  `json:"errors,omitempty" jsonschema:"Projects and folders that couldn't be listed, so their clusters are missing."`
+	MissingZones []string      `json:"missing_zones,omitempty" jsonschema:"Unreachable zones as project/zone, so their clusters may be missing."`
+}
+
+// resourceManager lists the contents of folders and organizations.
+type resourceManager interface {
+	// listProjects returns the IDs of the active projects directly under parent.
+	listProjects(ctx context.Context, parent string) ([]string, error)
+	// listFolders returns the resource names of the folders directly under parent.
+	listFolders(ctx context.Context, parent string) ([]string, error)
+}
+
+type rmClient struct {
+	projects *resourcemanager.ProjectsClient
+	folders  *resourcemanager.FoldersClient
+}
+
+func (c *rmClient) listProjects(ctx context.Context, parent string) ([]string, error) {
+	var ids []string
+	it := c.projects.ListProjects(ctx, &resourcemanagerpb.ListProjectsRequest{Parent: parent})
+	for {
+		p, err := it.Next()
+		if err == iterator.Done {
+			return ids, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if p.GetState() == resourcemanagerpb.Project_ACTIVE {
+			ids = append(ids, p.GetProjectId())
+		}
+	}
+}
+
+func (c *rmClient) listFolders(ctx context.Context, parent string) ([]string, error) {
+	var names []string
+	it := c.folders.ListFolders(ctx, &resourcemanagerpb.ListFoldersRequest{Parent: parent})
+	for {
+		f, err := it.Next()
+		if err == iterator.Done {
+			return names, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if f.GetState() == resourcemanagerpb.Folder_ACTIVE {
+			names = append(names, f.GetName())
+		}
+	}
+}
+
+// resolveFleetProjects returns the de-duplicated projects named by args,
+// walking the folder and organization trees. Folders that can't be listed are
+// reported as errors rather than failing the whole walk.
+func resolveFleetProjects(ctx context.Context, rm resourceManager, args *listFleetClustersArgs) ([]string, []fleetError) {
+	var (
+		projects []string
+		errs     []fleetError
+		seen     = map[string]bool{}
+	)
+	add := func(id string) {
+		if id != "" && !seen[id] {
+			seen[id] = true
+			projects = append(projects, id)
+		}
+	}
+	for _, id := range args.ProjectIDs {
+		add(strings.TrimPrefix(strings.TrimSpace(id), "projects/"))
+	}
+
+	var queue []string
+	if args.Folder != "" {
+		queue = append(queue, resourceName("folders/", args.Folder))
+	}
+	if args.Organization != "" {
+		queue = append(queue, resourceName("organizations/", args.Organization))
+	}
+	visited := map[string]bool{}
+	for len(queue) > 0 {
+		parent := queue[0]
+		queue = queue[1:]
+		if visited[parent] {
+			continue
+		}
+		visited[parent] = true
+
+		ids, err := rm.listProjects(ctx, parent)
+		if err != nil {
+			errs = append(errs, fleetError{Resource: parent, Error: fmt.Sprintf("failed to list projects: %v", err)})
+			continue
+		}
+		for _, id := range ids {
+			add(id)
+		}
+		folders, err := rm.listFolders(ctx, parent)
+		if err != nil {
+			errs = append(errs, fleetError{Resource: parent, Error: fmt.Sprintf("failed to list folders: %v", err)})
+			continue
+		}
+		queue = append(queue, folders...)
+	}
+	return projects, errs
+}
+
+// resourceName returns id with the given collection prefix, accepting ids
+// with or without it.
+func resourceName(prefix, id string) string {
+	id = strings.TrimSpace(id)
+	if strings.HasPrefix(id, prefix) {
+		return id
+	}
+	return prefix + id
+}
+
+type projectClusters struct {
+	projectID string
+	clusters  []*containerpb.Cluster
+	missing   []string
+	err       error
+}
+
+// listProjectsClusters calls list for each project with at most parallelism
+// calls in flight, returning the results in the order of projects.
+func listProjectsClusters(ctx context.Context, projects []string, parallelism int, list func(ctx context.Context, projectID string) (*containerpb.ListClustersResponse, error)) []projectClusters {
+	results := make([]projectClusters, len(projects))
+	var g errgroup.Group
+	g.SetLimit(parallelism)
+	for i, projectID := range projects {
+		g.Go(func() error {
+			resp, err := list(ctx, projectID)
+			results[i] = projectClusters{
+				projectID: projectID,
+				clusters:  resp.GetClusters(),
+				missing:   resp.GetMissingZones(),
+				err:       err,
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+	return results
+}
+
+// fleetCluster is a cluster and the project it was listed in.
+type fleetCluster struct {
+	projectID string
+	cluster   *containerpb.Cluster
+}
+
+// mergeFleetClusters flattens the clusters of all projects, dropping
+// duplicates and ordering them by project, location and name.
+func mergeFleetClusters(results []projectClusters) ([]fleetCluster, []string, []fleetError) {
+	var (
+		clusters []fleetCluster
+		missing  []string
+		errs     []fleetError
+		seen     = map[string]bool{}
+	)
+	for _, r := range results {
+		if r.err != nil {
+			errs = append(errs, fleetError{Resource: "projects/" + r.projectID, Error: r.err.Error()})
+			continue
+		}
+		for _, zone := range r.missing {
+			missing = append(missing, r.projectID+"/"+zone)
+		}
+		for _, c := range r.clusters {
+			key := c.GetSelfLink()
+			if key == "" {
+				key = fmt.Sprintf("projects/%s/locations/%s/clusters/%s", r.projectID, c.GetLocation(), c.GetName())
+			}
+			if seen[key] {
+				continue
+			}
+			seen[key] = true
+			clusters = append(clusters, fleetCluster{projectID: r.projectID, cluster: c})
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool {
+		a, b := clusters[i], clusters[j]
+		if a.projectID != b.projectID {
+			return a.projectID < b.projectID
+		}
+		if a.cluster.GetLocation() != b.cluster.GetLocation() {
+			return a.cluster.GetLocation() < b.cluster.GetLocation()
+		}
+		return a.cluster.GetName() < b.cluster.GetName()
+	})
+	return clusters, missing, errs
+}
+
+func (h *handlers) listFleetClusters(ctx context.Context, _ *mcp.CallToolRequest, args *listFleetClustersArgs) (*mcp.CallToolResult, *listFleetClustersOutput, error) {
+	if len(args.ProjectIDs) == 0 && args.Folder == "" && args.Organization == "" {
+		return nil, nil, fmt.Errorf("one of project_ids, folder or organization must be set")
+	}
+	if args.Location == "" {
+		args.Location = "-"
+	}
+	parallelism := args.Parallelism
+	if parallelism <= 0 {
+		parallelism = defaultFleetParallelism
+	}
+	if parallelism > maxFleetParallelism {
+		parallelism = maxFleetParallelism
+	}
+	view, err := parseView(args.View, viewSummary)
+	if err != nil {
+		return nil, nil, err
+	}
+	proj, err := newProjection((&containerpb.Cluster{}).ProtoReflect().Descriptor(), args.Fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	projects, errs := resolveFleetProjects(ctx, h.rm, args)
+
+	results := listProjectsClusters(ctx, projects, parallelism, func(ctx context.Context, projectID string) (*containerpb.ListClustersResponse, error) {
+		return h.cmClient.ListClusters(ctx, &containerpb.ListClustersRequest{
+			Parent: fmt.Sprintf("projects/%s/locations/%s", projectID, args.Location),
+		})
+	})
+	clusters, missing, listErrs := mergeFleetClusters(results)
+
+	out := &listFleetClustersOutput{
+		Projects:     projects,
+		Errors:       append(errs, listErrs...),
+		MissingZones: missing,
+	}
+	for _, c := range clusters {
+		info, err := newClusterInfoForView(c.cluster, view, proj)
+		if err != nil {
+			return nil, nil, err
+		}
+		info.ProjectID = c.projectID
+		out.Clusters = append(out.Clusters, info)
+	}
+
+	summary := fmt.Sprintf("Found %d clusters in %d projects.", len(out.Clusters), len(projects))
+	if len(out.Clusters) > 0 {
+		summary += "\n\n" + clusterTable(out.Clusters)
+	}
+	for _, e := range out.Errors {
+		summary += fmt.Sprintf("\nError: %s: %s", e.Resource, e.Error)
+	}
+	if len(out.MissingZones) > 0 {
+		summary += fmt.Sprintf("\nWarning: clusters in unreachable zones %s may be missing.", strings.Join(out.MissingZones, ", "))
+	}
+
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
@@ -0,0 +1,157 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+)
+
+type fakeResourceManager struct {
+	projects map[string][]string
+	folders  map[string][]string
+	failing  map[string]bool
+}
+
+func (f *fakeResourceManager) listProjects(_ context.Context, parent string) ([]string, error) {
+	if f.failing[parent] {
+		return nil, errors.New("permission denied")
+	}
+	return f.projects[parent], nil
+}
+
+func (f *fakeResourceManager) listFolders(_ context.Context, parent string) ([]string, error) {
+	return f.folders[parent], nil
+}
+
+func TestResolveFleetProjects(t *testing.T) {
+	rm := &fakeResourceManager{
+		projects: map[string][]string{
+			"organizations/1": {"org-project"},
+			"folders/10":      {"a", "b"},
+			"folders/11":      {"b", "c"},
+		},
+		folders: map[string][]string{
+			"organizations/1": {"folders/10", "folders/12"},
+			"folders/10":      {"folders/11"},
+		},
+		failing: map[string]bool{"folders/12": true},
+	}
+
+	tests := []struct {
+		name         string
+		args         listFleetClustersArgs
+		wantProjects []string
+		wantErrs     []fleetError
+	}{
+		{
+			name:         "project list",
+			args:         listFleetClustersArgs{ProjectIDs: []string{"x", "projects/y", "x"}},
+			wantProjects: []string{"x", "y"},
+		},
+		{
+			name:         "folder with subfolders",
+			args:         listFleetClustersArgs{Folder: "10"},
+			wantProjects: []string{"a", "b", "c"},
+		},
+		{
+			name:         "organization with unreadable folder",
+			args:         listFleetClustersArgs{Organization: "organizations/1", ProjectIDs: []string{"a"}},
+			wantProjects: []string{"a", "org-project", "b", "c"},
+			wantErrs:     []fleetError{{Resource: "folders/12", Error: "failed to list projects: permission denied"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			projects, errs := resolveFleetProjects(context.Background(), rm, &tt.args)
+			if diff := cmp.Diff(tt.wantProjects, projects); diff != "" {
+				t.Errorf("projects mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantErrs, errs); diff != "" {
+				t.Errorf("errors mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestListProjectsClustersParallelism(t *testing.T) {
+	var inFlight, maxInFlight atomic.Int32
+	projects := []string{"p1", "p2", "p3", "p4", "p5", "p6"}
+
+	results := listProjectsClusters(context.Background(), projects, 2, func(_ context.Context, projectID string) (*containerpb.ListClustersResponse, error) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			m := maxInFlight.Load()
+			if n <= m || maxInFlight.CompareAndSwap(m, n) {
+				break
+			}
+		}
+		time.Sleep(10 * time.Millisecond)
+		if projectID == "p3" {
+			return nil, errors.New("API not enabled")
+		}
+		return &containerpb.ListClustersResponse{Clusters: []*containerpb.Cluster{{Name: projectID + "-cluster"}}}, nil
+	})
+
+	if got := maxInFlight.Load(); got > 2 {
+		t.Errorf("max concurrent calls = %d, want at most 2", got)
+	}
+	for i, r := range results {
+		if r.projectID != projects[i] {
+			t.Errorf("results[%d].projectID = %q, want %q", i, r.projectID, projects[i])
+		}
+	}
+	if results[2].err == nil {
+		t.Errorf("results[2].err = nil, want the p3 error")
+	}
+}
+
+func TestMergeFleetClusters(t *testing.T) {
+	shared := &containerpb.Cluster{Name: "shared", Location: "us-central1", SelfLink: "https://container.googleapis.com/v1/projects/b/locations/us-central1/clusters/shared"}
+	results := []projectClusters{
+		{projectID: "b", clusters: []*containerpb.Cluster{
+			{Name: "z", Location: "us-east1"},
+			shared,
+			{Name: "a", Location: "us-east1"},
+		}, missing: []string{"us-west1-a"}},
+		{projectID: "a", clusters: []*containerpb.Cluster{{Name: "c", Location: "europe-west1"}}},
+		{projectID: "b", clusters: []*containerpb.Cluster{shared}},
+		{projectID: "broken", err: errors.New("permission denied")},
+	}
+
+	clusters, missing, errs := mergeFleetClusters(results)
+
+	var got []string
+	for _, c := range clusters {
+		got = append(got, c.projectID+"/"+c.cluster.GetLocation()+"/"+c.cluster.GetName())
+	}
+	want := []string{"a/europe-west1/c", "b/us-central1/shared", "b/us-east1/a", "b/us-east1/z"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("clusters mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]string{"b/us-west1-a"}, missing); diff != "" {
+		t.Errorf("missing zones mismatch (-want +got):\n%s", diff)
+	}
+	if diff := cmp.Diff([]fleetError{{Resource: "projects/broken", Error: "permission denied"}}, errs); diff != "" {
+		t.Errorf("errors mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,269 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"cloud.google.com/go/auth"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/httpstream"
+	"k8s.io/client-go/discovery/cached/memory"
+	"k8s.io/client-go/dynamic"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/restmapper"
+	"k8s.io/client-go/tools/remotecommand"
+)
+
+// kubeClients are the Kubernetes API clients of one GKE cluster.
+type kubeClients struct {
+	clientset kubernetes.Interface
+	dynamic   dynamic.Interface
+	mapper    meta.RESTMapper
+	// config is the REST config the clients were created from, for
+	// subresources such as exec that need their own connection. It's nil in
+	// tests.
+	config *rest.Config
+	// execFn replaces exec in tests.
+	execFn func(ctx context.Context, namespace, pod string, command []string, stdout, stderr io.Writer) error
+	// nodeLogsFn replaces nodeLogs in tests.
+	nodeLogsFn func(ctx context.Context, node string, params url.Values) (io.ReadCloser, error)
+}
+
+// kubeClientsFunc returns the clients of a GKE cluster.
+type kubeClientsFunc func(ctx context.Context, projectID, location, name string) (*kubeClients, error)
+
+// kubeClientsTTL is how long the clients of a cluster are reused, so that a
+// rotated endpoint or CA certificate is eventually picked up.
+const kubeClientsTTL = 10 * time.Minute
+
+// kubeClientsCache keeps the clients of each cluster, so API discovery runs
+// once per cluster rather than on every call. The clients authenticate each
+// request with the credentials of its own context, so they can be shared
+// between callers.
+type kubeClientsCache struct {
+	mu      sync.Mutex
+	entries map[string]kubeClientsEntry
+}
+
+type kubeClientsEntry struct {
+	clients *kubeClients
+	created time.Time
+}
+
+func (c *kubeClientsCache) get(ctx context.Context, key string, create func(context.Context) (*kubeClients, error)) (*kubeClients, error) {
+	c.mu.Lock()
+	e, ok := c.entries[key]
+	c.mu.Unlock()
+	if ok && time.Since(e.created) < kubeClientsTTL {
+		return e.clients, nil
+	}
+	// Don't hold the lock while creating, which calls the GKE API. Concurrent
+	// calls may both create clients; the last ones are kept.
+	k, err := create(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.entries == nil {
+		c.entries = map[string]kubeClientsEntry{}
+	}
+	c.entries[key] = kubeClientsEntry{clients: k, created: time.Now()}
+	return k, nil
+}
+
+// kubeClientsFor returns the clients of a GKE cluster, applying the default
+// project and location.
+func (h *handlers) kubeClientsFor(ctx context.Context, projectID, location, name string) (*kubeClients, error) {
+	if projectID == "" {
+		projectID = h.c.DefaultProjectID()
+	}
+	if location == "" {
+		location = h.c.DefaultLocation()
+	}
+	if name == "" {
+		return nil, fmt.Errorf("cluster argument cannot be empty")
+	}
+	if h.kube == nil {
+		key := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name)
+		return h.kubeCache.get(ctx, key, func(ctx context.Context) (*kubeClients, error) {
+			return h.newKubeClients(ctx, projectID, location, name)
+		})
+	}
+	return h.kube(ctx, projectID, location, name)
+}
+
+// newKubeClients connects to the control plane of a GKE cluster through its
+// default endpoint, authenticating with the server's Google credentials
+// rather than a kubeconfig context.
+func (h *handlers) newKubeClients(ctx context.Context, projectID, location, name string) (*kubeClients, error) {
+	c, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, name),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	ep, err := clusterEndpoint(c, endpointDefault)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := &rest.Config{
+		Host:            ep.server,
+		TLSClientConfig: rest.TLSClientConfig{CAData: ep.caData},
+		UserAgent:       h.c.UserAgent(),
+		WrapTransport: func(rt http.RoundTripper) http.RoundTripper {
+			return &googleAuthTransport{creds: h.kubeCreds, base: rt}
+		},
+	}
+	clientset, err := kubernetes.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes client: %w", err)
+	}
+	dyn, err := dynamic.NewForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Kubernetes dynamic client: %w", err)
+	}
+	disc := memory.NewMemCacheClient(clientset.Discovery())
+	mapper := restmapper.NewShortcutExpander(restmapper.NewDeferredDiscoveryRESTMapper(disc), disc, func(s string) {
+		log.Printf("Kubernetes API warning: %s", s)
+	})
+	return &kubeClients{clientset: clientset, dynamic: dyn, mapper: mapper, config: cfg}, nil
+}
+
+// googleAuthTransport authenticates Kubernetes API requests with a Google
+// OAuth access token, like gke-gcloud-auth-plugin does for kubectl.
+type googleAuthTransport struct {
+	creds *auth.Credentials
+	base  http.RoundTripper
+}
+
+func (t *googleAuthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	token, err := t.creds.Token(req.Context())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get access token: %w", err)
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.Value)
+	return t.base.RoundTrip(req)
+}
+
+// exec runs command in the first container of a pod through the exec
+// subresource, streaming its output to stdout and stderr. Like kubectl, it
+// uses WebSockets and falls back to SPDY for older control planes.
+func (k *kubeClients) exec(ctx context.Context, namespace, pod string, command []string, stdout, stderr io.Writer) error {
+	if k.execFn != nil {
+		return k.execFn(ctx, namespace, pod, command, stdout, stderr)
+	}
+	req := k.clientset.CoreV1().RESTClient().Post().
+		Resource("pods").
+		Namespace(namespace).
+		Name(pod).
+		SubResource("exec").
+		VersionedParams(&corev1.PodExecOptions{
+			Command: command,
+			Stdout:  true,
+			Stderr:  true,
+		}, scheme.ParameterCodec)
+	spdy, err := remotecommand.NewSPDYExecutor(k.config, "POST", req.URL())
+	if err != nil {
+		return fmt.Errorf("failed to create SPDY executor: %w", err)
+	}
+	ws, err := remotecommand.NewWebSocketExecutor(k.config, "GET", req.URL().String())
+	if err != nil {
+		return fmt.Errorf("failed to create WebSocket executor: %w", err)
+	}
+	executor, err := remotecommand.NewFallbackExecutor(ws, spdy, func(err error) bool {
+		return httpstream.IsUpgradeFailure(err) || httpstream.IsHTTPSProxyError(err)
+	})
+	if err != nil {
+		return err
+	}
+	return executor.StreamWithContext(ctx, remotecommand.StreamOptions{Stdout: stdout, Stderr: stderr})
+}
+
+// nodeLogs streams the logs of a node through the kubelet's node log query
+// endpoint, proxied by the API server. The kubelet only serves it with the
+// NodeLogQuery feature and enableSystemLogQuery enabled.
+func (k *kubeClients) nodeLogs(ctx context.Context, node string, params url.Values) (io.ReadCloser, error) {
+	if k.nodeLogsFn != nil {
+		return k.nodeLogsFn(ctx, node, params)
+	}
+	// The trailing slash is needed: the kubelet serves /logs/.
+	req := k.clientset.CoreV1().RESTClient().Get().AbsPath("/api/v1/nodes/" + node + "/proxy/logs/")
+	for key, values := range params {
+		for _, v := range values {
+			req = req.Param(key, v)
+		}
+	}
+	return req.Stream(ctx)
+}
+
+// resolveResource maps a kind, resource name or short name such as
+// "Deployment", "deployments", "deploy" or "deployments.apps" to its REST
+// mapping. apiVersion selects the group and version, and defaults to the
+// preferred version of whichever group has the resource.
+func resolveResource(mapper meta.RESTMapper, apiVersion, kind string) (*meta.RESTMapping, error) {
+	if kind == "" {
+		return nil, fmt.Errorf("kind argument cannot be empty")
+	}
+	gvr := schema.ParseGroupResource(strings.ToLower(kind)).WithVersion("")
+	if apiVersion != "" {
+		gv, err := schema.ParseGroupVersion(apiVersion)
+		if err != nil {
+			return nil, fmt.Errorf("invalid api_version %q: %w", apiVersion, err)
+		}
+		gvr = gv.WithResource(gvr.Resource)
+	}
+	gvk, err := mapper.KindFor(gvr)
+	if r, ok := mapper.(meta.ResettableRESTMapper); ok && meta.IsNoMatchError(err) {
+		// Discovery is cached per cluster; the type may have been added since.
+		r.Reset()
+		gvk, err = mapper.KindFor(gvr)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource type %q: %w", kind, err)
+	}
+	mapping, err := mapper.RESTMapping(gvk.GroupKind(), gvk.Version)
+	if err != nil {
+		return nil, fmt.Errorf("unknown resource type %q: %w", kind, err)
+	}
+	return mapping, nil
+}
+
+// resourceInterface returns the dynamic client of a resource type in
+// namespace. Namespaced resources in an empty namespace are listed across all
+// namespaces, and cluster-scoped resources ignore namespace.
+func (k *kubeClients) resourceInterface(mapping *meta.RESTMapping, namespace string) dynamic.ResourceInterface {
+	r := k.dynamic.Resource(mapping.Resource)
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace && namespace != "" {
+		return r.Namespace(namespace)
+	}
+	return r
+}
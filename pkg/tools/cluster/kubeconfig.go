@@ -0,0 +1,290 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/tools/clientcmd"
+	k8sClientApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Control plane endpoint types a kubeconfig can connect through.
+const (
+	endpointDefault        = "default"
+	endpointPublic         = "public"
+	endpointPrivate        = "private"
+	endpointDNS            = "dns"
+	endpointConnectGateway = "connect_gateway"
+)
+
+const (
+	// kubeconfigLockTimeout is how long to wait for another writer to
+	// release the kubeconfig lock.
+	kubeconfigLockTimeout = 10 * time.Second
+	// kubeconfigStaleLock is the age after which a lock file is assumed to
+	// be left behind by a crashed writer. Writers hold it for milliseconds.
+	kubeconfigStaleLock = time.Minute
+)
+
+// getKubeconfigArgs defines arguments for getting a GKE cluster's kubeconfig.
+type getKubeconfigArgs struct {
+	ProjectID          string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location           string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Name               string `json:"name" jsonschema:"GKE cluster name. Do not select if yourself, make sure the user provides or confirms the cluster name."`
+	Path               string `json:"path,omitempty" jsonschema:"Kubeconfig file to write. Defaults to the kubectl default, $KUBECONFIG or ~/.kube/config. Use a separate file to keep the user's kubeconfig untouched."`
+	KeepCurrentContext bool   `json:"keep_current_context,omitempty" jsonschema:"Don't switch the current context to the cluster, so kubectl in the user's other terminals keeps using its current cluster."`
+	EndpointType       string `json:"endpoint_type,omitempty" jsonschema:"Control plane endpoint to connect to: 'default' (the endpoint GKE reports, like gcloud), 'public', 'private', 'dns' for the DNS-based endpoint, or 'connect_gateway' to go through the fleet's Connect Gateway."`
+}
+
+// getKubeconfig retrieves GKE cluster details and constructs a kubeconfig file.
+// It appends/updates the configuration in the user's kubeconfig file.
+func (h *handlers) getKubeconfig(ctx context.Context, _ *mcp.CallToolRequest, args *getKubeconfigArgs) (*mcp.CallToolResult, *getKubeconfigOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	if args.EndpointType == "" {
+		args.EndpointType = endpointDefault
+	}
+	path, err := kubeconfigPath(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	req := &containerpb.GetClusterRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s/clusters/%s", args.ProjectID, args.Location, args.Name),
+	}
+	resp, err := h.cmClient.GetCluster(ctx, req)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster %s: %w", args.Name, err)
+	}
+	ep, err := clusterEndpoint(resp, args.EndpointType)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	contextName := kubeconfigContextName(args.ProjectID, args.Location, args.Name)
+	err = updateKubeconfig(path, func(cfg *k8sClientApi.Config) error {
+		setKubeconfigEntries(cfg, contextName, ep)
+		if !args.KeepCurrentContext {
+			cfg.CurrentContext = contextName
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &getKubeconfigOutput{
+		ProjectID:      args.ProjectID,
+		Location:       args.Location,
+		Cluster:        args.Name,
+		Context:        contextName,
+		KubeconfigPath: path,
+		CurrentContext: !args.KeepCurrentContext,
+		EndpointType:   args.EndpointType,
+		Server:         ep.server,
+	}
+	summary := fmt.Sprintf("Kubeconfig for cluster %s (Project: %s, Location: %s) successfully appended/updated in %s, connecting to %s (%s endpoint).", args.Name, args.ProjectID, args.Location, path, ep.server, args.EndpointType)
+	if args.KeepCurrentContext {
+		summary += fmt.Sprintf(" The current context wasn't changed; use context %s to reach the cluster.", contextName)
+	} else {
+		summary += fmt.Sprintf(" Current context set to %s.", contextName)
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// kubeconfigContextName returns the context name gcloud uses for a cluster.
+func kubeconfigContextName(projectID, location, name string) string {
+	return fmt.Sprintf("gke_%s_%s_%s", projectID, location, name)
+}
+
+// kubeconfigPath returns the kubeconfig file to write: path with ~ expanded,
+// or kubectl's default file.
+func kubeconfigPath(path string) (string, error) {
+	if path == "" {
+		return clientcmd.NewDefaultPathOptions().GetDefaultFilename(), nil
+	}
+	if rest, ok := strings.CutPrefix(path, "~"); ok && (rest == "" || rest[0] == '/' || rest[0] == filepath.Separator) {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return "", fmt.Errorf("failed to expand %s: %w", path, err)
+		}
+		path = filepath.Join(home, rest)
+	}
+	return filepath.Abs(path)
+}
+
+// endpoint is where a kubeconfig connects to a cluster's control plane.
+type endpoint struct {
+	server string
+	// caData is the CA certificate to trust, or nil for endpoints with
+	// publicly trusted certificates.
+	caData []byte
+}
+
+// clusterEndpoint returns the endpoint of the given type for cluster.
+func clusterEndpoint(c *containerpb.Cluster, endpointType string) (endpoint, error) {
+	var host string
+	withCA := true
+	ip := c.GetControlPlaneEndpointsConfig().GetIpEndpointsConfig()
+	private := c.GetPrivateClusterConfig()
+	switch endpointType {
+	case endpointDefault:
+		host = c.GetEndpoint()
+	case endpointPublic:
+		host = firstNonEmpty(ip.GetPublicEndpoint(), private.GetPublicEndpoint())
+		if host == "" && !private.GetEnablePrivateEndpoint() && (ip.EnablePublicEndpoint == nil || ip.GetEnablePublicEndpoint()) {
+			host = c.GetEndpoint()
+		}
+	case endpointPrivate:
+		host = firstNonEmpty(ip.GetPrivateEndpoint(), private.GetPrivateEndpoint())
+	case endpointDNS:
+		host = c.GetControlPlaneEndpointsConfig().GetDnsEndpointConfig().GetEndpoint()
+		withCA = false
+	case endpointConnectGateway:
+		membership := strings.TrimPrefix(c.GetFleet().GetMembership(), "//gkehub.googleapis.com/")
+		if membership == "" {
+			return endpoint{}, fmt.Errorf("cluster %s isn't registered to a fleet, so it can't be reached through Connect Gateway", c.GetName())
+		}
+		gateway := strings.Replace(membership, "/memberships/", "/gkeMemberships/", 1)
+		return endpoint{server: "https://connectgateway.googleapis.com/v1/" + gateway}, nil
+	default:
+		return endpoint{}, fmt.Errorf("invalid endpoint_type %q, must be one of %s, %s, %s, %s or %s", endpointType, endpointDefault, endpointPublic, endpointPrivate, endpointDNS, endpointConnectGateway)
+	}
+	if host == "" {
+		return endpoint{}, fmt.Errorf("cluster %s has no %s endpoint", c.GetName(), endpointType)
+	}
+
+	ep := endpoint{server: host}
+	// Ensure the endpoint starts with "https://"
+	if !strings.HasPrefix(ep.server, "https://") {
+		ep.server = "https://" + ep.server
+	}
+	if withCA {
+		clusterCaCertificate := c.GetMasterAuth().GetClusterCaCertificate()
+		if clusterCaCertificate == "" {
+			return endpoint{}, fmt.Errorf("clusterCaCertificate not found for cluster %s", c.GetName())
+		}
+		var err error
+		// The API may or may not pad the certificate.
+		ep.caData, err = base64.RawStdEncoding.DecodeString(strings.TrimRight(clusterCaCertificate, "="))
+		if err != nil {
+			return endpoint{}, fmt.Errorf("failed to decode clusterCaCertificate: %w", err)
+		}
+	}
+	return ep, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// setKubeconfigEntries adds or replaces the cluster, user and context entries
+// named name, connecting to ep with gke-gcloud-auth-plugin.
+func setKubeconfigEntries(cfg *k8sClientApi.Config, name string, ep endpoint) {
+	cfg.Clusters[name] = &k8sClientApi.Cluster{
+		CertificateAuthorityData: ep.caData,
+		Server:                   ep.server,
+	}
+	cfg.AuthInfos[name] = &k8sClientApi.AuthInfo{
+		Exec: &k8sClientApi.ExecConfig{
+			APIVersion:         "client.authentication.k8s.io/v1beta1",
+			Command:            "gke-gcloud-auth-plugin",
+			InstallHint:        "Install gke-gcloud-auth-plugin for use with kubectl by following https://cloud.google.com/kubernetes-engine/docs/how-to/cluster-access-for-kubectl#install_plugin",
+			ProvideClusterInfo: true,
+		},
+	}
+	cfg.Contexts[name] = &k8sClientApi.Context{
+		Cluster:  name,
+		AuthInfo: name,
+	}
+}
+
+// updateKubeconfig applies modify to the kubeconfig file at path, creating
+// it if needed. The file is locked while it's read, modified and written,
+// using the same lock file as kubectl.
+func updateKubeconfig(path string, modify func(*k8sClientApi.Config) error) error {
+	unlock, err := lockKubeconfig(path)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	cfg, err := clientcmd.LoadFromFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		cfg = k8sClientApi.NewConfig()
+	} else if err != nil {
+		return fmt.Errorf("failed to load kubeconfig %s: %w", path, err)
+	}
+	if err := modify(cfg); err != nil {
+		return err
+	}
+	if err := clientcmd.WriteToFile(*cfg, path); err != nil {
+		return fmt.Errorf("failed to write kubeconfig %s: %w", path, err)
+	}
+	return nil
+}
+
+// lockKubeconfig takes the lock kubectl uses for the kubeconfig file at path,
+// waiting for up to kubeconfigLockTimeout if another writer holds it.
+func lockKubeconfig(path string) (func(), error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create kubeconfig directory: %w", err)
+	}
+	lock := path + ".lock"
+	deadline := time.Now().Add(kubeconfigLockTimeout)
+	for {
+		f, err := os.OpenFile(lock, os.O_CREATE|os.O_EXCL, 0)
+		if err == nil {
+			_ = f.Close()
+			return func() { _ = os.Remove(lock) }, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to lock kubeconfig %s: %w", path, err)
+		}
+		if fi, err := os.Stat(lock); err == nil && time.Since(fi.ModTime()) > kubeconfigStaleLock {
+			// Left behind by a writer that crashed; take it over.
+			_ = os.Remove(lock)
+			continue
+		}
+		if time.Now().After(deadline) {
+			return nil, fmt.Errorf("timed out waiting for the lock on kubeconfig %s; remove %s if no other program is writing the kubeconfig", path, lock)
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+}
@@ -0,0 +1,174 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	"k8s.io/client-go/tools/clientcmd"
+	k8sClientApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+var testCA = []byte("-----BEGIN CERTIFICATE-----\n")
+
+func testEndpointsCluster() *containerpb.Cluster {
+	return &containerpb.Cluster{
+		Name:       "c",
+		Endpoint:   "10.0.0.2",
+		MasterAuth: &containerpb.MasterAuth{ClusterCaCertificate: base64.StdEncoding.EncodeToString(testCA)},
+		ControlPlaneEndpointsConfig: &containerpb.ControlPlaneEndpointsConfig{
+			DnsEndpointConfig: &containerpb.ControlPlaneEndpointsConfig_DNSEndpointConfig{Endpoint: "gke-123.us-central1.gke.goog"},
+			IpEndpointsConfig: &containerpb.ControlPlaneEndpointsConfig_IPEndpointsConfig{
+				PublicEndpoint:  "34.1.2.3",
+				PrivateEndpoint: "10.0.0.2",
+			},
+		},
+		Fleet: &containerpb.Fleet{Membership: "//gkehub.googleapis.com/projects/123/locations/us-central1/memberships/c"},
+	}
+}
+
+func TestClusterEndpoint(t *testing.T) {
+	privateOnly := &containerpb.Cluster{
+		Name:                 "c",
+		Endpoint:             "10.0.0.2",
+		MasterAuth:           &containerpb.MasterAuth{ClusterCaCertificate: base64.RawStdEncoding.EncodeToString(testCA)},
+		PrivateClusterConfig: &containerpb.PrivateClusterConfig{EnablePrivateEndpoint: true, PrivateEndpoint: "10.0.0.2"},
+	}
+	tests := []struct {
+		name         string
+		cluster      *containerpb.Cluster
+		endpointType string
+		want         endpoint
+		wantErr      bool
+	}{
+		{name: "default", cluster: testEndpointsCluster(), endpointType: endpointDefault, want: endpoint{server: "https://10.0.0.2", caData: testCA}},
+		{name: "public", cluster: testEndpointsCluster(), endpointType: endpointPublic, want: endpoint{server: "https://34.1.2.3", caData: testCA}},
+		{name: "private", cluster: testEndpointsCluster(), endpointType: endpointPrivate, want: endpoint{server: "https://10.0.0.2", caData: testCA}},
+		{name: "dns", cluster: testEndpointsCluster(), endpointType: endpointDNS, want: endpoint{server: "https://gke-123.us-central1.gke.goog"}},
+		{
+			name:         "connect gateway",
+			cluster:      testEndpointsCluster(),
+			endpointType: endpointConnectGateway,
+			want:         endpoint{server: "https://connectgateway.googleapis.com/v1/projects/123/locations/us-central1/gkeMemberships/c"},
+		},
+		{name: "unpadded CA", cluster: privateOnly, endpointType: endpointPrivate, want: endpoint{server: "https://10.0.0.2", caData: testCA}},
+		{name: "public endpoint disabled", cluster: privateOnly, endpointType: endpointPublic, wantErr: true},
+		{name: "no DNS endpoint", cluster: privateOnly, endpointType: endpointDNS, wantErr: true},
+		{name: "not in a fleet", cluster: privateOnly, endpointType: endpointConnectGateway, wantErr: true},
+		{name: "invalid type", cluster: privateOnly, endpointType: "internal", wantErr: true},
+		{name: "no CA", cluster: &containerpb.Cluster{Endpoint: "1.2.3.4"}, endpointType: endpointDefault, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := clusterEndpoint(tt.cluster, tt.endpointType)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("clusterEndpoint() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(endpoint{})); diff != "" {
+				t.Errorf("clusterEndpoint() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestKubeconfigPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	tests := []struct {
+		path string
+		want string
+	}{
+		{path: "~/.kube/gke", want: filepath.Join(home, ".kube", "gke")},
+		{path: "/tmp/kubeconfig", want: "/tmp/kubeconfig"},
+	}
+	for _, tt := range tests {
+		got, err := kubeconfigPath(tt.path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tt.want {
+			t.Errorf("kubeconfigPath(%q) = %q, want %q", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "kube", "config")
+	ep := endpoint{server: "https://1.2.3.4", caData: testCA}
+
+	// Creates the file.
+	if err := updateKubeconfig(path, func(cfg *k8sClientApi.Config) error {
+		setKubeconfigEntries(cfg, "other", ep)
+		cfg.CurrentContext = "other"
+		return nil
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Concurrent writers don't lose each other's entries.
+	var wg sync.WaitGroup
+	for i := range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := updateKubeconfig(path, func(cfg *k8sClientApi.Config) error {
+				setKubeconfigEntries(cfg, fmt.Sprintf("gke_p_l_c%d", i), ep)
+				return nil
+			}); err != nil {
+				t.Error(err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	cfg, err := clientcmd.LoadFromFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cfg.Contexts) != 11 {
+		t.Errorf("kubeconfig has %d contexts, want 11", len(cfg.Contexts))
+	}
+	if cfg.CurrentContext != "other" {
+		t.Errorf("current context = %q, want other", cfg.CurrentContext)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock file wasn't removed: %v", err)
+	}
+}
+
+func TestLockKubeconfigStale(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	lock := path + ".lock"
+	if err := os.WriteFile(lock, nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * kubeconfigStaleLock)
+	if err := os.Chtimes(lock, old, old); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err := lockKubeconfig(path)
+	if err != nil {
+		t.Fatalf("lockKubeconfig() didn't take over the stale lock: %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,373 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"k8s.io/client-go/tools/clientcmd"
+	k8sClientApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+// Statuses of GKE kubeconfig contexts.
+const (
+	contextOK               = "ok"
+	contextStale            = "stale"
+	contextEndpointMismatch = "endpoint_mismatch"
+	contextCAMismatch       = "ca_mismatch"
+	contextBroken           = "broken"
+	contextUnknown          = "unknown"
+)
+
+type checkKubeconfigArgs struct {
+	Path string `json:"path,omitempty" jsonschema:"Kubeconfig file to check. Defaults to the kubectl default, $KUBECONFIG or ~/.kube/config."`
+}
+
+type cleanKubeconfigArgs struct {
+	Path     string   `json:"path,omitempty" jsonschema:"Kubeconfig file to clean. Defaults to the kubectl default, $KUBECONFIG or ~/.kube/config."`
+	Prune    bool     `json:"prune,omitempty" jsonschema:"Remove the contexts of clusters that no longer exist, with their cluster and user entries."`
+	Refresh  bool     `json:"refresh,omitempty" jsonschema:"Rewrite the endpoint and CA certificate of contexts that don't match their cluster any more."`
+	Contexts []string `json:"contexts,omitempty" jsonschema:"Only clean these contexts. Defaults to all GKE contexts."`
+}
+
+type kubeconfigContext struct {
+	Context   string `json:"context"`
+	ProjectID string `json:"project_id"`
+	Location  string `json:"location"`
+	Cluster   string `json:"cluster"`
+	Status    string `json:"status" jsonschema:"ok; stale if the cluster no longer exists; endpoint_mismatch or ca_mismatch if the cluster entry doesn't match the cluster; broken if the context's cluster entry is missing; unknown if the project's clusters couldn't be listed."`
+	Detail    string `json:"detail,omitempty"`
+	Server    string `json:"server,omitempty"`
+	Action    string `json:"action,omitempty" jsonschema:"pruned or refreshed, if the context was changed."`
+
+	// cluster is the GKE cluster the context is for, if it exists.
+	cluster *containerpb.Cluster
+	// endpointType is the endpoint type to refresh the context with.
+	endpointType string
+}
+
+type kubeconfigContextsOutput struct {
+	Path           string              `json:"path"`
+	CurrentContext string              `json:"current_context,omitempty"`
+	Contexts       []kubeconfigContext `json:"contexts,omitempty" jsonschema:"Contexts named like gke_PROJECT_LOCATION_CLUSTER, as get_kubeconfig and gcloud create them."`
+}
+
+// installKubeconfigContexts registers the kubeconfig hygiene tools.
+func (h *handlers) installKubeconfigContexts(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "check_kubeconfig",
+		Description: "Check the GKE contexts in a kubeconfig file against the clusters that exist, reporting stale contexts of deleted clusters and contexts whose endpoint or CA certificate no longer matches the cluster.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.checkKubeconfig)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "clean_kubeconfig",
+		Description: "Prune the kubeconfig contexts of GKE clusters that no longer exist, and refresh contexts whose endpoint or CA certificate no longer matches the cluster. Run check_kubeconfig first and confirm the changes with the user.",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.cleanKubeconfig)
+}
+
+func (h *handlers) checkKubeconfig(ctx context.Context, _ *mcp.CallToolRequest, args *checkKubeconfigArgs) (*mcp.CallToolResult, *kubeconfigContextsOutput, error) {
+	path, err := kubeconfigPath(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := loadExistingKubeconfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	results := h.listContextClusters(ctx, cfg)
+
+	out := &kubeconfigContextsOutput{
+		Path:           path,
+		CurrentContext: cfg.CurrentContext,
+		Contexts:       checkKubeconfigContexts(cfg, results),
+	}
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+func (h *handlers) cleanKubeconfig(ctx context.Context, _ *mcp.CallToolRequest, args *cleanKubeconfigArgs) (*mcp.CallToolResult, *kubeconfigContextsOutput, error) {
+	if !args.Prune && !args.Refresh {
+		return nil, nil, fmt.Errorf("no changes requested: set prune, refresh or both")
+	}
+	path, err := kubeconfigPath(args.Path)
+	if err != nil {
+		return nil, nil, err
+	}
+	cfg, err := loadExistingKubeconfig(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	// List the clusters before locking the file, so it's only locked while
+	// it's being changed.
+	results := h.listContextClusters(ctx, cfg)
+
+	out := &kubeconfigContextsOutput{Path: path}
+	err = updateKubeconfig(path, func(cfg *k8sClientApi.Config) error {
+		out.Contexts = checkKubeconfigContexts(cfg, results)
+		for i := range out.Contexts {
+			c := &out.Contexts[i]
+			if len(args.Contexts) > 0 && !slices.Contains(args.Contexts, c.Context) {
+				continue
+			}
+			switch {
+			case args.Prune && c.Status == contextStale:
+				pruneKubeconfigContext(cfg, c.Context)
+				c.Action = "pruned"
+			case args.Refresh && (c.Status == contextEndpointMismatch || c.Status == contextCAMismatch || c.Status == contextBroken):
+				ep, err := clusterEndpoint(c.cluster, c.endpointType)
+				if err != nil {
+					c.Detail = fmt.Sprintf("%s; can't refresh it: %v", c.Detail, err)
+					continue
+				}
+				setKubeconfigEntries(cfg, c.Context, ep)
+				c.Server = ep.server
+				c.Action = "refreshed"
+			}
+		}
+		out.CurrentContext = cfg.CurrentContext
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+func loadExistingKubeconfig(path string) (*k8sClientApi.Config, error) {
+	cfg, err := clientcmd.LoadFromFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, fmt.Errorf("kubeconfig %s doesn't exist", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+// listContextClusters lists the clusters of every project with a GKE context
+// in cfg.
+func (h *handlers) listContextClusters(ctx context.Context, cfg *k8sClientApi.Config) []projectClusters {
+	var projects []string
+	for name := range cfg.Contexts {
+		if projectID, _, _, ok := parseGKEContextName(name); ok && !slices.Contains(projects, projectID) {
+			projects = append(projects, projectID)
+		}
+	}
+	sort.Strings(projects)
+	return listProjectsClusters(ctx, projects, defaultFleetParallelism, func(ctx context.Context, projectID string) (*containerpb.ListClustersResponse, error) {
+		return h.cmClient.ListClusters(ctx, &containerpb.ListClustersRequest{
+			Parent: fmt.Sprintf("projects/%s/locations/-", projectID),
+		})
+	})
+}
+
+// parseGKEContextName splits a context name created by get_kubeconfig or
+// gcloud, gke_PROJECT_LOCATION_CLUSTER. None of the parts can contain
+// underscores.
+func parseGKEContextName(name string) (projectID, location, cluster string, ok bool) {
+	parts := strings.Split(name, "_")
+	if len(parts) != 4 || parts[0] != "gke" || parts[1] == "" || parts[2] == "" || parts[3] == "" {
+		return "", "", "", false
+	}
+	return parts[1], parts[2], parts[3], true
+}
+
+// checkKubeconfigContexts checks the GKE contexts in cfg against the clusters
+// listed in their projects.
+func checkKubeconfigContexts(cfg *k8sClientApi.Config, results []projectClusters) []kubeconfigContext {
+	byProject := map[string]projectClusters{}
+	for _, r := range results {
+		byProject[r.projectID] = r
+	}
+
+	var out []kubeconfigContext
+	for name, kctx := range cfg.Contexts {
+		projectID, location, clusterName, ok := parseGKEContextName(name)
+		if !ok {
+			continue
+		}
+		c := kubeconfigContext{Context: name, ProjectID: projectID, Location: location, Cluster: clusterName}
+		kc := cfg.Clusters[kctx.Cluster]
+		if kc != nil {
+			c.Server = kc.Server
+		}
+
+		r, listed := byProject[projectID]
+		switch {
+		case !listed:
+			c.Status, c.Detail = contextUnknown, "the project's clusters weren't listed"
+		case r.err != nil:
+			c.Status, c.Detail = contextUnknown, fmt.Sprintf("failed to list the project's clusters: %v", r.err)
+		case slices.Contains(r.missing, location):
+			c.Status, c.Detail = contextUnknown, fmt.Sprintf("GKE couldn't list clusters in %s", location)
+		default:
+			c.cluster = findCluster(r.clusters, location, clusterName)
+			checkKubeconfigContext(&c, kc)
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Context < out[j].Context })
+	return out
+}
+
+func findCluster(clusters []*containerpb.Cluster, location, name string) *containerpb.Cluster {
+	for _, c := range clusters {
+		if c.GetLocation() == location && c.GetName() == name {
+			return c
+		}
+	}
+	return nil
+}
+
+// checkKubeconfigContext sets the status of c, whose cluster entry is kc, by
+// comparing kc with c.cluster.
+func checkKubeconfigContext(c *kubeconfigContext, kc *k8sClientApi.Cluster) {
+	if c.cluster == nil {
+		c.Status, c.Detail = contextStale, "the cluster doesn't exist any more"
+		return
+	}
+	if kc == nil {
+		c.Status, c.Detail = contextBroken, "the context's cluster entry is missing"
+		c.endpointType = endpointDefault
+		return
+	}
+
+	var want []string
+	for _, t := range []string{endpointDefault, endpointPublic, endpointPrivate, endpointDNS, endpointConnectGateway} {
+		ep, err := clusterEndpoint(c.cluster, t)
+		if err != nil {
+			continue
+		}
+		if ep.server != kc.Server {
+			if !slices.Contains(want, ep.server) {
+				want = append(want, ep.server)
+			}
+			continue
+		}
+		c.endpointType = t
+		if ep.caData != nil && !bytes.Equal(kubeconfigCAData(kc), ep.caData) {
+			c.Status, c.Detail = contextCAMismatch, "the CA certificate doesn't match the cluster's, which happens after a credential rotation"
+			return
+		}
+		c.Status = contextOK
+		return
+	}
+	c.Status = contextEndpointMismatch
+	c.Detail = fmt.Sprintf("the server isn't one of the cluster's endpoints: %s", strings.Join(want, ", "))
+	c.endpointType = guessEndpointType(kc.Server)
+}
+
+// kubeconfigCAData returns the CA certificate of a cluster entry, read from
+// its file if it isn't inline.
+func kubeconfigCAData(kc *k8sClientApi.Cluster) []byte {
+	if len(kc.CertificateAuthorityData) > 0 || kc.CertificateAuthority == "" {
+		return kc.CertificateAuthorityData
+	}
+	b, err := os.ReadFile(kc.CertificateAuthority)
+	if err != nil {
+		return nil
+	}
+	return b
+}
+
+// guessEndpointType returns the endpoint type a server URL looks like, to
+// refresh a context through the same kind of endpoint.
+func guessEndpointType(server string) string {
+	switch {
+	case strings.Contains(server, "connectgateway.googleapis.com"):
+		return endpointConnectGateway
+	case strings.HasSuffix(strings.TrimSuffix(server, "/"), ".gke.goog"):
+		return endpointDNS
+	}
+	return endpointDefault
+}
+
+// pruneKubeconfigContext removes a context, and its cluster and user entries
+// unless other contexts use them.
+func pruneKubeconfigContext(cfg *k8sClientApi.Config, name string) {
+	kctx := cfg.Contexts[name]
+	delete(cfg.Contexts, name)
+	if cfg.CurrentContext == name {
+		cfg.CurrentContext = ""
+	}
+	if kctx == nil {
+		return
+	}
+	clusterUsed, userUsed := false, false
+	for _, other := range cfg.Contexts {
+		clusterUsed = clusterUsed || other.Cluster == kctx.Cluster
+		userUsed = userUsed || other.AuthInfo == kctx.AuthInfo
+	}
+	if !clusterUsed {
+		delete(cfg.Clusters, kctx.Cluster)
+	}
+	if !userUsed {
+		delete(cfg.AuthInfos, kctx.AuthInfo)
+	}
+}
+
+func (o *kubeconfigContextsOutput) summary() string {
+	counts := map[string]int{}
+	for _, c := range o.Contexts {
+		counts[c.Status]++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Found %d GKE contexts in %s", len(o.Contexts), o.Path)
+	var parts []string
+	for _, status := range []string{contextOK, contextStale, contextEndpointMismatch, contextCAMismatch, contextBroken, contextUnknown} {
+		if counts[status] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[status], status))
+		}
+	}
+	if len(parts) > 0 {
+		fmt.Fprintf(&b, ": %s", strings.Join(parts, ", "))
+	}
+	b.WriteString(".")
+	if len(o.Contexts) == 0 {
+		return b.String()
+	}
+
+	var t strings.Builder
+	w := tabwriter.NewWriter(&t, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "CONTEXT\tSTATUS\tACTION\tDETAIL")
+	for _, c := range o.Contexts {
+		action := c.Action
+		if action == "" {
+			action = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", c.Context, c.Status, action, c.Detail)
+	}
+	_ = w.Flush()
+	b.WriteString("\n\n" + strings.TrimRight(t.String(), "\n"))
+	return b.String()
+}
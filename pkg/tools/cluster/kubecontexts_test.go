@@ -0,0 +1,134 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"errors"
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	k8sClientApi "k8s.io/client-go/tools/clientcmd/api"
+)
+
+func TestParseGKEContextName(t *testing.T) {
+	tests := []struct {
+		name                       string
+		project, location, cluster string
+		ok                         bool
+	}{
+		{name: "gke_p_us-central1_c", project: "p", location: "us-central1", cluster: "c", ok: true},
+		{name: "gke_p_us-central1"},
+		{name: "gke_p__c"},
+		{name: "gke_p_l_c_d"},
+		{name: "kind_p_l_c"},
+		{name: "minikube"},
+	}
+	for _, tt := range tests {
+		project, location, cluster, ok := parseGKEContextName(tt.name)
+		if project != tt.project || location != tt.location || cluster != tt.cluster || ok != tt.ok {
+			t.Errorf("parseGKEContextName(%q) = %q, %q, %q, %v, want %q, %q, %q, %v", tt.name, project, location, cluster, ok, tt.project, tt.location, tt.cluster, tt.ok)
+		}
+	}
+}
+
+func TestCheckKubeconfigContexts(t *testing.T) {
+	cfg := k8sClientApi.NewConfig()
+	setKubeconfigEntries(cfg, "gke_p_us-central1_c", endpoint{server: "https://34.1.2.3", caData: testCA})
+	setKubeconfigEntries(cfg, "gke_p_us-central1_dns", endpoint{server: "https://gke-123.us-central1.gke.goog"})
+	setKubeconfigEntries(cfg, "gke_p_us-central1_rotated", endpoint{server: "https://10.0.0.2", caData: []byte("old")})
+	setKubeconfigEntries(cfg, "gke_p_us-central1_moved", endpoint{server: "https://1.1.1.1", caData: testCA})
+	setKubeconfigEntries(cfg, "gke_p_us-central1_deleted", endpoint{server: "https://1.1.1.1", caData: testCA})
+	setKubeconfigEntries(cfg, "gke_p_us-east1_c", endpoint{server: "https://1.1.1.1", caData: testCA})
+	setKubeconfigEntries(cfg, "gke_denied_us-central1_c", endpoint{server: "https://1.1.1.1", caData: testCA})
+	setKubeconfigEntries(cfg, "gke_p_us-central1_broken", endpoint{server: "https://10.0.0.2", caData: testCA})
+	setKubeconfigEntries(cfg, "kind-kind", endpoint{server: "https://127.0.0.1:6443"})
+	delete(cfg.Clusters, "gke_p_us-central1_broken")
+
+	cluster := func(name string) *containerpb.Cluster {
+		c := testEndpointsCluster()
+		c.Name, c.Location = name, "us-central1"
+		return c
+	}
+	results := []projectClusters{
+		{
+			projectID: "p",
+			clusters:  []*containerpb.Cluster{cluster("c"), cluster("dns"), cluster("rotated"), cluster("moved"), cluster("broken")},
+			missing:   []string{"us-east1"},
+		},
+		{projectID: "denied", err: errors.New("permission denied")},
+	}
+
+	var got []string
+	for _, c := range checkKubeconfigContexts(cfg, results) {
+		got = append(got, c.Context+" "+c.Status+" "+c.endpointType)
+	}
+	want := []string{
+		"gke_denied_us-central1_c unknown ",
+		"gke_p_us-central1_broken broken default",
+		"gke_p_us-central1_c ok public",
+		"gke_p_us-central1_deleted stale ",
+		"gke_p_us-central1_dns ok dns",
+		"gke_p_us-central1_moved endpoint_mismatch default",
+		"gke_p_us-central1_rotated ca_mismatch default",
+		"gke_p_us-east1_c unknown ",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("checkKubeconfigContexts() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestGuessEndpointType(t *testing.T) {
+	for server, want := range map[string]string{
+		"https://connectgateway.googleapis.com/v1/projects/1/locations/global/gkeMemberships/c": endpointConnectGateway,
+		"https://gke-123.us-central1.gke.goog":                                                  endpointDNS,
+		"https://34.1.2.3":                                                                      endpointDefault,
+	} {
+		if got := guessEndpointType(server); got != want {
+			t.Errorf("guessEndpointType(%q) = %q, want %q", server, got, want)
+		}
+	}
+}
+
+func TestPruneKubeconfigContext(t *testing.T) {
+	ep := endpoint{server: "https://1.2.3.4", caData: testCA}
+	cfg := k8sClientApi.NewConfig()
+	setKubeconfigEntries(cfg, "gke_p_l_a", ep)
+	setKubeconfigEntries(cfg, "gke_p_l_b", ep)
+	// Another context sharing the first context's user.
+	cfg.Contexts["gke_p_l_c"] = &k8sClientApi.Context{Cluster: "gke_p_l_b", AuthInfo: "gke_p_l_a"}
+	cfg.CurrentContext = "gke_p_l_a"
+
+	pruneKubeconfigContext(cfg, "gke_p_l_a")
+	pruneKubeconfigContext(cfg, "gke_p_l_b")
+
+	if cfg.CurrentContext != "" {
+		t.Errorf("current context = %q, want it cleared", cfg.CurrentContext)
+	}
+	var got []string
+	for name := range cfg.Contexts {
+		got = append(got, "context "+name)
+	}
+	for name := range cfg.Clusters {
+		got = append(got, "cluster "+name)
+	}
+	for name := range cfg.AuthInfos {
+		got = append(got, "user "+name)
+	}
+	want := []string{"context gke_p_l_c", "cluster gke_p_l_b", "user gke_p_l_a"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("entries after pruning mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,735 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+const (
+	defaultUpcomingWindows = 5
+	maxUpcomingWindows     = 50
+
+	// dailyWindowDuration is the length GKE gives daily maintenance windows.
+	dailyWindowDuration = 4 * time.Hour
+	// GKE requires maintenance windows to allow minAvailability of
+	// maintenance in any availabilityPeriod, in blocks of at least
+	// minWindowDuration.
+	minWindowDuration  = 4 * time.Hour
+	minAvailability    = 48 * time.Hour
+	availabilityPeriod = 32 * 24 * time.Hour
+	// maxNoUpgradesExclusion is the longest GKE allows a NO_UPGRADES
+	// exclusion to be.
+	maxNoUpgradesExclusion = 30 * 24 * time.Hour
+)
+
+type getMaintenancePolicyArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Count     int    `json:"count,omitempty" jsonschema:"Number of upcoming maintenance windows to list. Defaults to 5, at most 50."`
+}
+
+type setMaintenancePolicyArgs struct {
+	ProjectID       string                 `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location        string                 `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster         string                 `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	DailyStartTime  string                 `json:"daily_start_time,omitempty" jsonschema:"Replace the maintenance window with a daily 4 hour window starting at this UTC time, as HH:MM."`
+	RecurringWindow *recurringWindow       `json:"recurring_window,omitempty" jsonschema:"Replace the maintenance window with a recurring window."`
+	RemoveWindow    bool                   `json:"remove_window,omitempty" jsonschema:"Remove the maintenance window, so GKE may upgrade the cluster at any time outside exclusions."`
+	Exclusions      []maintenanceExclusion `json:"exclusions,omitempty" jsonschema:"Maintenance exclusions, replacing all existing ones. Leave this out to keep the current exclusions, or pass an empty list to remove them all."`
+	Count           int                    `json:"count,omitempty" jsonschema:"Number of upcoming maintenance windows to list. Defaults to 5, at most 50."`
+	DryRun          *bool                  `json:"dry_run,omitempty" jsonschema:"Only validate the policy and preview its upcoming windows. Defaults to true; set to false only after the user has approved the previewed policy."`
+}
+
+type recurringWindow struct {
+	StartTime  string `json:"start_time" jsonschema:"Start of the first window in RFC 3339 format, e.g. 2025-01-04T02:00:00Z."`
+	EndTime    string `json:"end_time" jsonschema:"End of the first window in RFC 3339 format. Every window lasts as long as the first one."`
+	Recurrence string `json:"recurrence" jsonschema:"How the window repeats, as an RFC 5545 RRULE, e.g. FREQ=WEEKLY;BYDAY=SA,SU or FREQ=DAILY."`
+}
+
+type maintenanceExclusion struct {
+	Name              string `json:"name"`
+	StartTime         string `json:"start_time" jsonschema:"Start of the exclusion in RFC 3339 format."`
+	EndTime           string `json:"end_time,omitempty" jsonschema:"End of the exclusion in RFC 3339 format. Can be left out when until_end_of_support is set."`
+	Scope             string `json:"scope,omitempty" jsonschema:"Upgrades the exclusion blocks: NO_UPGRADES (default, at most 30 days), NO_MINOR_UPGRADES or NO_MINOR_OR_NODE_UPGRADES."`
+	UntilEndOfSupport bool   `json:"until_end_of_support,omitempty" jsonschema:"Keep the exclusion until the cluster's current minor version reaches its end of support. Not allowed for NO_UPGRADES."`
+}
+
+type maintenanceWindowInfo struct {
+	Type       string `json:"type" jsonschema:"daily or recurring."`
+	StartTime  string `json:"start_time" jsonschema:"UTC start time of daily windows as HH:MM, or start of the first recurring window."`
+	EndTime    string `json:"end_time,omitempty" jsonschema:"End of the first recurring window."`
+	Duration   string `json:"duration"`
+	Recurrence string `json:"recurrence,omitempty"`
+}
+
+type upcomingWindow struct {
+	Start      string   `json:"start"`
+	End        string   `json:"end"`
+	Allows     string   `json:"allows" jsonschema:"Automatic upgrades allowed during the whole window by the exclusions covering it."`
+	Exclusions []string `json:"exclusions,omitempty" jsonschema:"Exclusions overlapping the window."`
+}
+
+type nextUpgrade struct {
+	Version       string `json:"version" jsonschema:"Auto-upgrade target version of the cluster's release channel."`
+	Minor         bool   `json:"minor" jsonschema:"Whether this is a minor version upgrade, which patch-only exclusions block."`
+	EarliestStart string `json:"earliest_start,omitempty" jsonschema:"Earliest time the maintenance policy lets the upgrade start. Empty if no time within the listed windows allows it."`
+	BlockedReason string `json:"blocked_reason,omitempty"`
+}
+
+type maintenancePolicyOutput struct {
+	Cluster        string                 `json:"cluster" jsonschema:"Full resource name of the cluster."`
+	Window         *maintenanceWindowInfo `json:"window,omitempty" jsonschema:"The maintenance window. Without one, GKE may upgrade the cluster at any time outside exclusions."`
+	Exclusions     []maintenanceExclusion `json:"exclusions,omitempty"`
+	Upcoming       []upcomingWindow       `json:"upcoming,omitempty" jsonschema:"The next maintenance windows, including one in progress."`
+	ReleaseChannel string                 `json:"release_channel,omitempty"`
+	CurrentVersion string                 `json:"current_version,omitempty" jsonschema:"Current control plane version."`
+	NextUpgrade    *nextUpgrade           `json:"next_upgrade,omitempty" jsonschema:"The pending automatic control plane upgrade, if the release channel targets a newer version."`
+	DryRun         bool                   `json:"dry_run,omitempty"`
+	Operation      *operationSummary      `json:"operation,omitempty" jsonschema:"The operation setting the policy, if it was started."`
+
+	// upgradeChecked is whether NextUpgrade was looked up.
+	upgradeChecked bool
+}
+
+// upgradeScope is the set of automatic upgrades GKE may start at some time.
+// Higher values are more restrictive.
+type upgradeScope int
+
+const (
+	allowAllUpgrades upgradeScope = iota
+	allowPatchUpgrades
+	allowControlPlanePatchUpgrades
+	allowNoUpgrades
+)
+
+func (s upgradeScope) String() string {
+	switch s {
+	case allowPatchUpgrades:
+		return "patch upgrades only"
+	case allowControlPlanePatchUpgrades:
+		return "control plane patch upgrades only"
+	case allowNoUpgrades:
+		return "no upgrades"
+	}
+	return "all upgrades"
+}
+
+var exclusionScopes = map[containerpb.MaintenanceExclusionOptions_Scope]upgradeScope{
+	containerpb.MaintenanceExclusionOptions_NO_UPGRADES:               allowNoUpgrades,
+	containerpb.MaintenanceExclusionOptions_NO_MINOR_UPGRADES:         allowPatchUpgrades,
+	containerpb.MaintenanceExclusionOptions_NO_MINOR_OR_NODE_UPGRADES: allowControlPlanePatchUpgrades,
+}
+
+// maintenanceSchedule is a parsed maintenance window.
+type maintenanceSchedule struct {
+	start    time.Time
+	duration time.Duration
+	rule     *rrule
+}
+
+// exclusionWindow is a parsed maintenance exclusion. A zero end means it
+// lasts until the end of support of the cluster's version.
+type exclusionWindow struct {
+	name       string
+	start, end time.Time
+	scope      upgradeScope
+}
+
+func (e exclusionWindow) overlaps(start, end time.Time) bool {
+	return e.start.Before(end) && (e.end.IsZero() || e.end.After(start))
+}
+
+func (e exclusionWindow) covers(start, end time.Time) bool {
+	return !e.start.After(start) && (e.end.IsZero() || !e.end.Before(end))
+}
+
+// installMaintenance registers the maintenance policy tools.
+func (h *handlers) installMaintenance(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_maintenance_policy",
+		Description: "Get the maintenance window and exclusions of a GKE cluster, its next maintenance windows, and when its next automatic upgrade can start. Use this to answer when a cluster will next be upgraded.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getMaintenancePolicy)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "set_maintenance_policy",
+		Description: "Set the maintenance window or exclusions of a GKE cluster. The policy is validated and its upcoming windows are previewed first; it is only applied when dry_run is false. " +
+			"Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(false),
+		},
+	}, h.setMaintenancePolicy)
+}
+
+func (h *handlers) getMaintenancePolicy(ctx context.Context, _ *mcp.CallToolRequest, args *getMaintenancePolicyArgs) (*mcp.CallToolResult, *maintenancePolicyOutput, error) {
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	count, err := upcomingCount(args.Count)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	sc := h.maintenanceServerConfig(ctx, name)
+
+	out, err := newMaintenancePolicyOutput(cluster, cluster.GetMaintenancePolicy(), sc, time.Now(), count)
+	if err != nil {
+		return nil, nil, err
+	}
+	out.Cluster = name
+	res, err := toolresult.New(out.summary(args.Cluster), out)
+	return res, out, err
+}
+
+func (h *handlers) setMaintenancePolicy(ctx context.Context, _ *mcp.CallToolRequest, args *setMaintenancePolicyArgs) (*mcp.CallToolResult, *maintenancePolicyOutput, error) {
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	count, err := upcomingCount(args.Count)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+	now := time.Now()
+	policy, err := newMaintenancePolicy(cluster.GetMaintenancePolicy(), args, now)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	sc := h.maintenanceServerConfig(ctx, name)
+
+	out, err := newMaintenancePolicyOutput(cluster, policy, sc, now, count)
+	if err != nil {
+		return nil, nil, err
+	}
+	out.Cluster = name
+	out.DryRun = args.DryRun == nil || *args.DryRun
+	if out.DryRun {
+		summary := fmt.Sprintf("The new maintenance policy is valid. Nothing was changed: show it to the user and call again with dry_run set to false once they approve it.\n\n%s", out.summary(args.Cluster))
+		res, err := toolresult.New(summary, out)
+		return res, out, err
+	}
+
+	op, err := h.cmClient.SetMaintenancePolicy(ctx, &containerpb.SetMaintenancePolicyRequest{
+		Name:              name,
+		MaintenancePolicy: policy,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	s := newOperationSummary(op)
+	out.Operation = &s
+	summary := fmt.Sprintf("Started setting the maintenance policy of cluster %s, operation %s is %s. Use wait_operation to wait for it.\n\n%s", args.Cluster, s.Name, s.Status, out.summary(args.Cluster))
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// maintenanceServerConfig returns the server config of the cluster's
+// location, used to find its next automatic upgrade, or nil if it can't be
+// fetched.
+func (h *handlers) maintenanceServerConfig(ctx context.Context, name string) *containerpb.ServerConfig {
+	sc, err := h.cmClient.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{Name: clusterParent(name)})
+	if err != nil {
+		log.Printf("Failed to get server config for %s, not reporting its next upgrade: %v", name, err)
+		return nil
+	}
+	return sc
+}
+
+func upcomingCount(n int) (int, error) {
+	switch {
+	case n == 0:
+		return defaultUpcomingWindows, nil
+	case n < 0 || n > maxUpcomingWindows:
+		return 0, fmt.Errorf("count must be between 1 and %d", maxUpcomingWindows)
+	}
+	return n, nil
+}
+
+// clusterParent returns the location a cluster resource name is in.
+func clusterParent(name string) string {
+	parent, _, _ := strings.Cut(name, "/clusters/")
+	return parent
+}
+
+// newMaintenancePolicy applies the changes in args to the current policy,
+// validating them as of now.
+func newMaintenancePolicy(current *containerpb.MaintenancePolicy, args *setMaintenancePolicyArgs, now time.Time) (*containerpb.MaintenancePolicy, error) {
+	windows := 0
+	for _, set := range []bool{args.DailyStartTime != "", args.RecurringWindow != nil, args.RemoveWindow} {
+		if set {
+			windows++
+		}
+	}
+	if windows > 1 {
+		return nil, fmt.Errorf("only one of daily_start_time, recurring_window and remove_window can be set")
+	}
+	if windows == 0 && args.Exclusions == nil {
+		return nil, fmt.Errorf("no changes requested: set daily_start_time, recurring_window, remove_window or exclusions")
+	}
+
+	policy := &containerpb.MaintenancePolicy{}
+	if current != nil {
+		policy = proto.Clone(current).(*containerpb.MaintenancePolicy)
+	}
+	if policy.Window == nil {
+		policy.Window = &containerpb.MaintenanceWindow{}
+	}
+	w := policy.Window
+
+	switch {
+	case args.DailyStartTime != "":
+		if _, err := time.Parse("15:04", args.DailyStartTime); err != nil {
+			return nil, fmt.Errorf("invalid daily_start_time %q, want a UTC time like 03:00", args.DailyStartTime)
+		}
+		w.Policy = &containerpb.MaintenanceWindow_DailyMaintenanceWindow{
+			DailyMaintenanceWindow: &containerpb.DailyMaintenanceWindow{StartTime: args.DailyStartTime},
+		}
+	case args.RecurringWindow != nil:
+		rw, err := newRecurringTimeWindow(args.RecurringWindow, now)
+		if err != nil {
+			return nil, err
+		}
+		w.Policy = &containerpb.MaintenanceWindow_RecurringWindow{RecurringWindow: rw}
+	case args.RemoveWindow:
+		w.Policy = nil
+	}
+
+	if args.Exclusions != nil {
+		w.MaintenanceExclusions = map[string]*containerpb.TimeWindow{}
+		for _, e := range args.Exclusions {
+			if e.Name == "" {
+				return nil, fmt.Errorf("exclusion name cannot be empty")
+			}
+			if _, ok := w.MaintenanceExclusions[e.Name]; ok {
+				return nil, fmt.Errorf("exclusion %q is listed more than once", e.Name)
+			}
+			tw, err := newExclusionTimeWindow(e)
+			if err != nil {
+				return nil, fmt.Errorf("exclusion %q: %w", e.Name, err)
+			}
+			w.MaintenanceExclusions[e.Name] = tw
+		}
+	}
+	return policy, nil
+}
+
+func newRecurringTimeWindow(rw *recurringWindow, now time.Time) (*containerpb.RecurringTimeWindow, error) {
+	start, err := time.Parse(time.RFC3339, rw.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring_window.start_time %q, want RFC 3339 like 2025-01-04T02:00:00Z", rw.StartTime)
+	}
+	end, err := time.Parse(time.RFC3339, rw.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring_window.end_time %q, want RFC 3339 like 2025-01-04T06:00:00Z", rw.EndTime)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("recurring_window.end_time must be after start_time")
+	}
+	rule, err := parseRRule(rw.Recurrence)
+	if err != nil {
+		return nil, fmt.Errorf("invalid recurring_window.recurrence: %w", err)
+	}
+	sched := &maintenanceSchedule{start: start, duration: end.Sub(start), rule: rule}
+	if err := sched.checkAvailability(now); err != nil {
+		return nil, err
+	}
+	return &containerpb.RecurringTimeWindow{
+		Window: &containerpb.TimeWindow{
+			StartTime: timestamppb.New(start),
+			EndTime:   timestamppb.New(end),
+		},
+		Recurrence: rw.Recurrence,
+	}, nil
+}
+
+// checkAvailability checks the schedule against GKE's minimum maintenance
+// availability, over the first availabilityPeriod starting at now or the
+// first window, whichever is later.
+func (s *maintenanceSchedule) checkAvailability(now time.Time) error {
+	if s.duration < minWindowDuration {
+		return fmt.Errorf("maintenance windows must be at least %v long, this one is %v", minWindowDuration, s.duration)
+	}
+	from := now
+	if s.start.After(from) {
+		from = s.start
+	}
+	until := from.Add(availabilityPeriod)
+	var total time.Duration
+	for _, start := range s.rule.occurrences(s.start, from.Add(-s.duration), maxOccurrencesPerPeriod) {
+		if !start.Before(until) || total >= minAvailability {
+			break
+		}
+		end := start.Add(s.duration)
+		if start.Before(from) {
+			start = from
+		}
+		if end.After(until) {
+			end = until
+		}
+		total += end.Sub(start)
+	}
+	if total < minAvailability {
+		return fmt.Errorf("GKE requires maintenance windows to allow at least %v of maintenance in any %d days, this recurrence only allows %v in the %d days from %s", minAvailability, availabilityPeriod/(24*time.Hour), total, availabilityPeriod/(24*time.Hour), from.UTC().Format(time.RFC3339))
+	}
+	return nil
+}
+
+// maxOccurrencesPerPeriod bounds the occurrences of a recurrence overlapping
+// an availabilityPeriod; rules repeat at most daily.
+const maxOccurrencesPerPeriod = int(availabilityPeriod/(24*time.Hour)) + 2
+
+func newExclusionTimeWindow(e maintenanceExclusion) (*containerpb.TimeWindow, error) {
+	scope := containerpb.MaintenanceExclusionOptions_NO_UPGRADES
+	if e.Scope != "" {
+		v, ok := containerpb.MaintenanceExclusionOptions_Scope_value[strings.ToUpper(e.Scope)]
+		if !ok {
+			return nil, fmt.Errorf("invalid scope %q, must be NO_UPGRADES, NO_MINOR_UPGRADES or NO_MINOR_OR_NODE_UPGRADES", e.Scope)
+		}
+		scope = containerpb.MaintenanceExclusionOptions_Scope(v)
+	}
+	start, err := time.Parse(time.RFC3339, e.StartTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid start_time %q, want RFC 3339 like 2025-12-20T00:00:00Z", e.StartTime)
+	}
+	opts := &containerpb.MaintenanceExclusionOptions{Scope: scope}
+	tw := &containerpb.TimeWindow{
+		StartTime: timestamppb.New(start),
+		Options:   &containerpb.TimeWindow_MaintenanceExclusionOptions{MaintenanceExclusionOptions: opts},
+	}
+
+	if e.UntilEndOfSupport {
+		if scope == containerpb.MaintenanceExclusionOptions_NO_UPGRADES {
+			return nil, fmt.Errorf("until_end_of_support can't be used with the NO_UPGRADES scope")
+		}
+		if e.EndTime != "" {
+			return nil, fmt.Errorf("end_time can't be set with until_end_of_support")
+		}
+		opts.EndTimeBehavior = containerpb.MaintenanceExclusionOptions_UNTIL_END_OF_SUPPORT
+		return tw, nil
+	}
+	end, err := time.Parse(time.RFC3339, e.EndTime)
+	if err != nil {
+		return nil, fmt.Errorf("invalid end_time %q, want RFC 3339 like 2026-01-05T00:00:00Z", e.EndTime)
+	}
+	if !end.After(start) {
+		return nil, fmt.Errorf("end_time must be after start_time")
+	}
+	if scope == containerpb.MaintenanceExclusionOptions_NO_UPGRADES && end.Sub(start) > maxNoUpgradesExclusion {
+		return nil, fmt.Errorf("NO_UPGRADES exclusions can last at most 30 days, use the NO_MINOR_UPGRADES or NO_MINOR_OR_NODE_UPGRADES scope for longer ones")
+	}
+	tw.EndTime = timestamppb.New(end)
+	return tw, nil
+}
+
+// newMaintenancePolicyOutput describes policy, lists its next count windows
+// after now, and, if sc is set, when the channel's pending auto-upgrade of
+// cluster can start.
+func newMaintenancePolicyOutput(cluster *containerpb.Cluster, policy *containerpb.MaintenancePolicy, sc *containerpb.ServerConfig, now time.Time, count int) (*maintenancePolicyOutput, error) {
+	out := &maintenancePolicyOutput{
+		ReleaseChannel: newClusterInfo(cluster).ReleaseChannel,
+		CurrentVersion: cluster.GetCurrentMasterVersion(),
+		upgradeChecked: sc != nil,
+	}
+	sched, window, err := parseMaintenanceWindow(policy.GetWindow(), now)
+	if err != nil {
+		return nil, err
+	}
+	out.Window = window
+	exclusions := parseExclusions(policy.GetWindow().GetMaintenanceExclusions())
+	for _, e := range exclusions {
+		out.Exclusions = append(out.Exclusions, newMaintenanceExclusion(e, policy.GetWindow().GetMaintenanceExclusions()[e.name]))
+	}
+
+	var windows [][2]time.Time
+	if sched != nil {
+		for _, start := range sched.rule.occurrences(sched.start, now.Add(-sched.duration), count+1) {
+			end := start.Add(sched.duration)
+			if !end.After(now) || len(windows) == count {
+				continue
+			}
+			windows = append(windows, [2]time.Time{start, end})
+		}
+	}
+	for _, w := range windows {
+		out.Upcoming = append(out.Upcoming, newUpcomingWindow(w[0], w[1], exclusions))
+	}
+
+	if sc != nil {
+		out.NextUpgrade = newNextUpgrade(cluster, sc, sched != nil, windows, exclusions, now)
+	}
+	return out, nil
+}
+
+// parseMaintenanceWindow returns the schedule of w and its description, or
+// nils if w has no window.
+func parseMaintenanceWindow(w *containerpb.MaintenanceWindow, now time.Time) (*maintenanceSchedule, *maintenanceWindowInfo, error) {
+	if d := w.GetDailyMaintenanceWindow(); d != nil {
+		t, err := time.Parse("15:04", d.GetStartTime())
+		if err != nil {
+			return nil, nil, fmt.Errorf("invalid daily maintenance window start time %q", d.GetStartTime())
+		}
+		duration := parseWindowDuration(d.GetDuration())
+		// Start the daily rule the day before now, so a window in progress
+		// is included.
+		y, m, day := now.UTC().AddDate(0, 0, -1).Date()
+		sched := &maintenanceSchedule{
+			start:    time.Date(y, m, day, t.Hour(), t.Minute(), 0, 0, time.UTC),
+			duration: duration,
+			rule:     &rrule{freq: "DAILY", interval: 1, weekStart: time.Monday},
+		}
+		return sched, &maintenanceWindowInfo{Type: "daily", StartTime: d.GetStartTime(), Duration: duration.String()}, nil
+	}
+	if r := w.GetRecurringWindow(); r != nil {
+		start := r.GetWindow().GetStartTime().AsTime()
+		end := r.GetWindow().GetEndTime().AsTime()
+		rule, err := parseRRule(r.GetRecurrence())
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to parse maintenance window recurrence %q: %w", r.GetRecurrence(), err)
+		}
+		info := &maintenanceWindowInfo{
+			Type:       "recurring",
+			StartTime:  start.UTC().Format(time.RFC3339),
+			EndTime:    end.UTC().Format(time.RFC3339),
+			Duration:   end.Sub(start).String(),
+			Recurrence: r.GetRecurrence(),
+		}
+		return &maintenanceSchedule{start: start, duration: end.Sub(start), rule: rule}, info, nil
+	}
+	return nil, nil, nil
+}
+
+var isoDurationRE = regexp.MustCompile(`^PT(?:(\d+)H)?(?:(\d+)M)?(?:(\d+)S)?$`)
+
+// parseWindowDuration parses a daily window duration like PT4H0M0S,
+// returning the GKE default if it's empty or can't be parsed.
+func parseWindowDuration(s string) time.Duration {
+	m := isoDurationRE.FindStringSubmatch(s)
+	if m == nil {
+		return dailyWindowDuration
+	}
+	var d time.Duration
+	for i, unit := range []time.Duration{time.Hour, time.Minute, time.Second} {
+		n, _ := strconv.Atoi(m[i+1])
+		d += time.Duration(n) * unit
+	}
+	if d == 0 {
+		return dailyWindowDuration
+	}
+	return d
+}
+
+// parseExclusions returns the exclusions sorted by start time.
+func parseExclusions(m map[string]*containerpb.TimeWindow) []exclusionWindow {
+	var out []exclusionWindow
+	for name, tw := range m {
+		e := exclusionWindow{
+			name:  name,
+			start: tw.GetStartTime().AsTime(),
+			scope: exclusionScopes[tw.GetMaintenanceExclusionOptions().GetScope()],
+		}
+		if tw.GetEndTime() != nil {
+			e.end = tw.GetEndTime().AsTime()
+		}
+		out = append(out, e)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		if !out[i].start.Equal(out[j].start) {
+			return out[i].start.Before(out[j].start)
+		}
+		return out[i].name < out[j].name
+	})
+	return out
+}
+
+func newMaintenanceExclusion(e exclusionWindow, tw *containerpb.TimeWindow) maintenanceExclusion {
+	opts := tw.GetMaintenanceExclusionOptions()
+	out := maintenanceExclusion{
+		Name:              e.name,
+		StartTime:         e.start.UTC().Format(time.RFC3339),
+		Scope:             opts.GetScope().String(),
+		UntilEndOfSupport: opts.GetEndTimeBehavior() == containerpb.MaintenanceExclusionOptions_UNTIL_END_OF_SUPPORT,
+	}
+	if !e.end.IsZero() {
+		out.EndTime = e.end.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func newUpcomingWindow(start, end time.Time, exclusions []exclusionWindow) upcomingWindow {
+	w := upcomingWindow{
+		Start: start.UTC().Format(time.RFC3339),
+		End:   end.UTC().Format(time.RFC3339),
+	}
+	allows := allowAllUpgrades
+	for _, e := range exclusions {
+		if !e.overlaps(start, end) {
+			continue
+		}
+		w.Exclusions = append(w.Exclusions, e.name)
+		if e.covers(start, end) && e.scope > allows {
+			allows = e.scope
+		}
+	}
+	w.Allows = allows.String()
+	return w
+}
+
+// blocks reports whether the exclusion blocks a control plane upgrade.
+func (e exclusionWindow) blocks(minor bool) bool {
+	if minor {
+		return e.scope != allowAllUpgrades
+	}
+	return e.scope == allowNoUpgrades
+}
+
+// earliestAllowed returns the earliest time at or after t that no exclusion
+// blocks the upgrade, or false if one blocks it indefinitely.
+func earliestAllowed(t time.Time, exclusions []exclusionWindow, minor bool) (time.Time, bool) {
+	for moved := true; moved; {
+		moved = false
+		for _, e := range exclusions {
+			if !e.blocks(minor) || !e.overlaps(t, t.Add(time.Nanosecond)) {
+				continue
+			}
+			if e.end.IsZero() {
+				return time.Time{}, false
+			}
+			t, moved = e.end, true
+		}
+	}
+	return t, true
+}
+
+// newNextUpgrade returns the channel's pending auto-upgrade of the control
+// plane and the earliest time it can start, or nil if there is none.
+func newNextUpgrade(cluster *containerpb.Cluster, sc *containerpb.ServerConfig, hasWindow bool, windows [][2]time.Time, exclusions []exclusionWindow, now time.Time) *nextUpgrade {
+	channel := cluster.GetReleaseChannel().GetChannel()
+	if channel == containerpb.ReleaseChannel_UNSPECIFIED {
+		return nil
+	}
+	var target string
+	for _, c := range sc.GetChannels() {
+		if c.GetChannel() == channel {
+			target = c.GetUpgradeTargetVersion()
+		}
+	}
+	current, err := parseGKEVersion(cluster.GetCurrentMasterVersion())
+	if err != nil {
+		return nil
+	}
+	tv, err := parseGKEVersion(target)
+	if err != nil || tv.compare(current) <= 0 {
+		return nil
+	}
+	next := &nextUpgrade{Version: target, Minor: tv.minorsBehind(current) < 0}
+
+	if !hasWindow {
+		if t, ok := earliestAllowed(now, exclusions, next.Minor); ok {
+			next.EarliestStart = t.UTC().Format(time.RFC3339)
+		} else {
+			next.BlockedReason = "an exclusion blocks it until the end of support of the current version"
+		}
+		return next
+	}
+	for _, w := range windows {
+		start := w[0]
+		if start.Before(now) {
+			start = now
+		}
+		if t, ok := earliestAllowed(start, exclusions, next.Minor); ok && t.Before(w[1]) {
+			next.EarliestStart = t.UTC().Format(time.RFC3339)
+			return next
+		}
+	}
+	next.BlockedReason = "exclusions block it in all the listed maintenance windows"
+	return next
+}
+
+func (o *maintenancePolicyOutput) summary(cluster string) string {
+	var b strings.Builder
+	switch {
+	case o.Window == nil:
+		fmt.Fprintf(&b, "Cluster %s has no maintenance window, so GKE may upgrade it at any time outside maintenance exclusions.", cluster)
+	case o.Window.Type == "daily":
+		fmt.Fprintf(&b, "Cluster %s has a daily %s maintenance window starting at %s UTC.", cluster, o.Window.Duration, o.Window.StartTime)
+	default:
+		fmt.Fprintf(&b, "Cluster %s has a %s maintenance window repeating %s, first from %s to %s.", cluster, o.Window.Duration, o.Window.Recurrence, o.Window.StartTime, o.Window.EndTime)
+	}
+	for _, e := range o.Exclusions {
+		end := e.EndTime
+		if e.UntilEndOfSupport {
+			end = "end of support"
+		}
+		fmt.Fprintf(&b, "\nExclusion %s: %s from %s to %s.", e.Name, e.Scope, e.StartTime, end)
+	}
+
+	if len(o.Upcoming) > 0 {
+		var t strings.Builder
+		w := tabwriter.NewWriter(&t, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "START\tEND\tALLOWS\tEXCLUSIONS")
+		for _, u := range o.Upcoming {
+			exclusions := strings.Join(u.Exclusions, ",")
+			if exclusions == "" {
+				exclusions = "-"
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Start, u.End, u.Allows, exclusions)
+		}
+		_ = w.Flush()
+		fmt.Fprintf(&b, "\n\nNext maintenance windows:\n%s", strings.TrimRight(t.String(), "\n"))
+	}
+
+	if n := o.NextUpgrade; n != nil {
+		kind := "patch"
+		if n.Minor {
+			kind = "minor"
+		}
+		fmt.Fprintf(&b, "\n\nThe %s channel's auto-upgrade target %s is a %s upgrade from the current %s.", o.ReleaseChannel, n.Version, kind, o.CurrentVersion)
+		if n.EarliestStart != "" {
+			fmt.Fprintf(&b, " The maintenance policy lets it start at %s at the earliest; GKE rolls out upgrades gradually, so it may start later.", n.EarliestStart)
+		} else {
+			fmt.Fprintf(&b, " It can't start yet: %s.", n.BlockedReason)
+		}
+	} else if o.upgradeChecked && o.ReleaseChannel != "" {
+		fmt.Fprintf(&b, "\n\nNo automatic upgrade is pending: the cluster runs %s, not older than its %s channel's auto-upgrade target. The next one can start in the first window that allows it once the target moves.", o.CurrentVersion, o.ReleaseChannel)
+	}
+	return b.String()
+}
@@ -0,0 +1,342 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+var maintenanceNow = time.Date(2025, 6, 10, 12, 0, 0, 0, time.UTC) // A Tuesday.
+
+func testExclusion(start, end string, scope containerpb.MaintenanceExclusionOptions_Scope) *containerpb.TimeWindow {
+	tw := &containerpb.TimeWindow{
+		StartTime: timestamppb.New(mustParseTime(start)),
+		Options: &containerpb.TimeWindow_MaintenanceExclusionOptions{
+			MaintenanceExclusionOptions: &containerpb.MaintenanceExclusionOptions{Scope: scope},
+		},
+	}
+	if end != "" {
+		tw.EndTime = timestamppb.New(mustParseTime(end))
+	}
+	return tw
+}
+
+func mustParseTime(s string) time.Time {
+	t, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+func weekendPolicy(exclusions map[string]*containerpb.TimeWindow) *containerpb.MaintenancePolicy {
+	return &containerpb.MaintenancePolicy{
+		ResourceVersion: "abc",
+		Window: &containerpb.MaintenanceWindow{
+			Policy: &containerpb.MaintenanceWindow_RecurringWindow{RecurringWindow: &containerpb.RecurringTimeWindow{
+				Window: &containerpb.TimeWindow{
+					StartTime: timestamppb.New(mustParseTime("2025-01-04T02:00:00Z")),
+					EndTime:   timestamppb.New(mustParseTime("2025-01-04T08:00:00Z")),
+				},
+				Recurrence: "FREQ=WEEKLY;BYDAY=SA,SU",
+			}},
+			MaintenanceExclusions: exclusions,
+		},
+	}
+}
+
+func TestNewMaintenancePolicy(t *testing.T) {
+	current := weekendPolicy(map[string]*containerpb.TimeWindow{
+		"holidays": testExclusion("2025-12-20T00:00:00Z", "2026-01-05T00:00:00Z", containerpb.MaintenanceExclusionOptions_NO_UPGRADES),
+	})
+	tests := []struct {
+		name    string
+		args    setMaintenancePolicyArgs
+		want    *containerpb.MaintenancePolicy
+		wantErr bool
+	}{
+		{name: "no changes", wantErr: true},
+		{
+			name:    "two windows",
+			args:    setMaintenancePolicyArgs{DailyStartTime: "03:00", RemoveWindow: true},
+			wantErr: true,
+		},
+		{
+			name: "daily window keeps exclusions",
+			args: setMaintenancePolicyArgs{DailyStartTime: "03:00"},
+			want: &containerpb.MaintenancePolicy{
+				ResourceVersion: "abc",
+				Window: &containerpb.MaintenanceWindow{
+					Policy: &containerpb.MaintenanceWindow_DailyMaintenanceWindow{DailyMaintenanceWindow: &containerpb.DailyMaintenanceWindow{StartTime: "03:00"}},
+					MaintenanceExclusions: map[string]*containerpb.TimeWindow{
+						"holidays": testExclusion("2025-12-20T00:00:00Z", "2026-01-05T00:00:00Z", containerpb.MaintenanceExclusionOptions_NO_UPGRADES),
+					},
+				},
+			},
+		},
+		{
+			name:    "invalid daily start",
+			args:    setMaintenancePolicyArgs{DailyStartTime: "3am"},
+			wantErr: true,
+		},
+		{
+			name: "recurring window",
+			args: setMaintenancePolicyArgs{RecurringWindow: &recurringWindow{
+				StartTime:  "2025-01-04T02:00:00Z",
+				EndTime:    "2025-01-04T08:00:00Z",
+				Recurrence: "FREQ=WEEKLY;BYDAY=SA,SU",
+			}},
+			want: current,
+		},
+		{
+			name: "invalid recurrence",
+			args: setMaintenancePolicyArgs{RecurringWindow: &recurringWindow{
+				StartTime:  "2025-01-04T02:00:00Z",
+				EndTime:    "2025-01-04T08:00:00Z",
+				Recurrence: "FREQ=YEARLY",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "window too short",
+			args: setMaintenancePolicyArgs{RecurringWindow: &recurringWindow{
+				StartTime:  "2025-01-04T02:00:00Z",
+				EndTime:    "2025-01-04T04:00:00Z",
+				Recurrence: "FREQ=DAILY",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "not enough availability",
+			args: setMaintenancePolicyArgs{RecurringWindow: &recurringWindow{
+				StartTime:  "2025-01-04T02:00:00Z",
+				EndTime:    "2025-01-04T08:00:00Z",
+				Recurrence: "FREQ=WEEKLY;BYDAY=SA",
+			}},
+			wantErr: true,
+		},
+		{
+			name: "remove window and exclusions",
+			args: setMaintenancePolicyArgs{RemoveWindow: true, Exclusions: []maintenanceExclusion{}},
+			want: &containerpb.MaintenancePolicy{
+				ResourceVersion: "abc",
+				Window:          &containerpb.MaintenanceWindow{MaintenanceExclusions: map[string]*containerpb.TimeWindow{}},
+			},
+		},
+		{
+			name: "replace exclusions",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-01T00:00:00Z", EndTime: "2025-11-20T00:00:00Z"},
+				{Name: "stay-on-minor", StartTime: "2025-06-01T00:00:00Z", Scope: "no_minor_upgrades", UntilEndOfSupport: true},
+			}},
+			want: weekendPolicy(map[string]*containerpb.TimeWindow{
+				"freeze": testExclusion("2025-11-01T00:00:00Z", "2025-11-20T00:00:00Z", containerpb.MaintenanceExclusionOptions_NO_UPGRADES),
+				"stay-on-minor": {
+					StartTime: timestamppb.New(mustParseTime("2025-06-01T00:00:00Z")),
+					Options: &containerpb.TimeWindow_MaintenanceExclusionOptions{MaintenanceExclusionOptions: &containerpb.MaintenanceExclusionOptions{
+						Scope:           containerpb.MaintenanceExclusionOptions_NO_MINOR_UPGRADES,
+						EndTimeBehavior: containerpb.MaintenanceExclusionOptions_UNTIL_END_OF_SUPPORT,
+					}},
+				},
+			}),
+		},
+		{
+			name: "no upgrades exclusion too long",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-01T00:00:00Z", EndTime: "2025-12-20T00:00:00Z"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "no upgrades until end of support",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-01T00:00:00Z", UntilEndOfSupport: true},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "duplicate exclusion",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-01T00:00:00Z", EndTime: "2025-11-02T00:00:00Z"},
+				{Name: "freeze", StartTime: "2025-12-01T00:00:00Z", EndTime: "2025-12-02T00:00:00Z"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "exclusion ends before it starts",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-02T00:00:00Z", EndTime: "2025-11-01T00:00:00Z"},
+			}},
+			wantErr: true,
+		},
+		{
+			name: "invalid scope",
+			args: setMaintenancePolicyArgs{Exclusions: []maintenanceExclusion{
+				{Name: "freeze", StartTime: "2025-11-01T00:00:00Z", EndTime: "2025-11-02T00:00:00Z", Scope: "NO_NODE_UPGRADES"},
+			}},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMaintenancePolicy(current, &tt.args, maintenanceNow)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newMaintenancePolicy() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("newMaintenancePolicy() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewMaintenancePolicyOutput(t *testing.T) {
+	sc := &containerpb.ServerConfig{Channels: []*containerpb.ServerConfig_ReleaseChannelConfig{
+		{Channel: containerpb.ReleaseChannel_REGULAR, UpgradeTargetVersion: "1.33.5-gke.1000"},
+	}}
+	regular := &containerpb.ReleaseChannel{Channel: containerpb.ReleaseChannel_REGULAR}
+	tests := []struct {
+		name    string
+		cluster *containerpb.Cluster
+		want    *maintenancePolicyOutput
+	}{
+		{
+			name: "no window",
+			cluster: &containerpb.Cluster{
+				CurrentMasterVersion: "1.33.5-gke.1000",
+				ReleaseChannel:       regular,
+			},
+			want: &maintenancePolicyOutput{ReleaseChannel: "REGULAR", CurrentVersion: "1.33.5-gke.1000", upgradeChecked: true},
+		},
+		{
+			name: "no window with active exclusion",
+			cluster: &containerpb.Cluster{
+				CurrentMasterVersion: "1.33.4-gke.100",
+				ReleaseChannel:       regular,
+				MaintenancePolicy: &containerpb.MaintenancePolicy{Window: &containerpb.MaintenanceWindow{
+					MaintenanceExclusions: map[string]*containerpb.TimeWindow{
+						"freeze": testExclusion("2025-06-01T00:00:00Z", "2025-06-20T00:00:00Z", containerpb.MaintenanceExclusionOptions_NO_UPGRADES),
+					},
+				}},
+			},
+			want: &maintenancePolicyOutput{
+				Exclusions: []maintenanceExclusion{
+					{Name: "freeze", StartTime: "2025-06-01T00:00:00Z", EndTime: "2025-06-20T00:00:00Z", Scope: "NO_UPGRADES"},
+				},
+				ReleaseChannel: "REGULAR",
+				CurrentVersion: "1.33.4-gke.100",
+				NextUpgrade:    &nextUpgrade{Version: "1.33.5-gke.1000", EarliestStart: "2025-06-20T00:00:00Z"},
+				upgradeChecked: true,
+			},
+		},
+		{
+			name: "daily window in progress",
+			cluster: &containerpb.Cluster{
+				CurrentMasterVersion: "1.33.5-gke.1000",
+				MaintenancePolicy: &containerpb.MaintenancePolicy{Window: &containerpb.MaintenanceWindow{
+					Policy: &containerpb.MaintenanceWindow_DailyMaintenanceWindow{DailyMaintenanceWindow: &containerpb.DailyMaintenanceWindow{StartTime: "10:30", Duration: "PT4H0M0S"}},
+				}},
+			},
+			want: &maintenancePolicyOutput{
+				Window: &maintenanceWindowInfo{Type: "daily", StartTime: "10:30", Duration: "4h0m0s"},
+				Upcoming: []upcomingWindow{
+					{Start: "2025-06-10T10:30:00Z", End: "2025-06-10T14:30:00Z", Allows: "all upgrades"},
+					{Start: "2025-06-11T10:30:00Z", End: "2025-06-11T14:30:00Z", Allows: "all upgrades"},
+				},
+				CurrentVersion: "1.33.5-gke.1000",
+				upgradeChecked: true,
+			},
+		},
+		{
+			name: "minor upgrade blocked by scoped exclusion",
+			cluster: &containerpb.Cluster{
+				CurrentMasterVersion: "1.32.9-gke.100",
+				ReleaseChannel:       regular,
+				MaintenancePolicy: weekendPolicy(map[string]*containerpb.TimeWindow{
+					"no-minor": testExclusion("2025-06-01T00:00:00Z", "2025-06-15T00:00:00Z", containerpb.MaintenanceExclusionOptions_NO_MINOR_UPGRADES),
+					"partial":  testExclusion("2025-06-14T04:00:00Z", "2025-06-14T05:00:00Z", containerpb.MaintenanceExclusionOptions_NO_UPGRADES),
+				}),
+			},
+			want: &maintenancePolicyOutput{
+				Window: &maintenanceWindowInfo{
+					Type:       "recurring",
+					StartTime:  "2025-01-04T02:00:00Z",
+					EndTime:    "2025-01-04T08:00:00Z",
+					Duration:   "6h0m0s",
+					Recurrence: "FREQ=WEEKLY;BYDAY=SA,SU",
+				},
+				Exclusions: []maintenanceExclusion{
+					{Name: "no-minor", StartTime: "2025-06-01T00:00:00Z", EndTime: "2025-06-15T00:00:00Z", Scope: "NO_MINOR_UPGRADES"},
+					{Name: "partial", StartTime: "2025-06-14T04:00:00Z", EndTime: "2025-06-14T05:00:00Z", Scope: "NO_UPGRADES"},
+				},
+				Upcoming: []upcomingWindow{
+					{Start: "2025-06-14T02:00:00Z", End: "2025-06-14T08:00:00Z", Allows: "patch upgrades only", Exclusions: []string{"no-minor", "partial"}},
+					{Start: "2025-06-15T02:00:00Z", End: "2025-06-15T08:00:00Z", Allows: "all upgrades"},
+				},
+				ReleaseChannel: "REGULAR",
+				CurrentVersion: "1.32.9-gke.100",
+				NextUpgrade:    &nextUpgrade{Version: "1.33.5-gke.1000", Minor: true, EarliestStart: "2025-06-15T02:00:00Z"},
+				upgradeChecked: true,
+			},
+		},
+		{
+			name: "blocked until end of support",
+			cluster: &containerpb.Cluster{
+				CurrentMasterVersion: "1.32.9-gke.100",
+				ReleaseChannel:       regular,
+				MaintenancePolicy: weekendPolicy(map[string]*containerpb.TimeWindow{
+					"stay": testExclusion("2025-06-01T00:00:00Z", "", containerpb.MaintenanceExclusionOptions_NO_MINOR_UPGRADES),
+				}),
+			},
+			want: &maintenancePolicyOutput{
+				Window: &maintenanceWindowInfo{
+					Type:       "recurring",
+					StartTime:  "2025-01-04T02:00:00Z",
+					EndTime:    "2025-01-04T08:00:00Z",
+					Duration:   "6h0m0s",
+					Recurrence: "FREQ=WEEKLY;BYDAY=SA,SU",
+				},
+				Exclusions: []maintenanceExclusion{
+					{Name: "stay", StartTime: "2025-06-01T00:00:00Z", Scope: "NO_MINOR_UPGRADES"},
+				},
+				Upcoming: []upcomingWindow{
+					{Start: "2025-06-14T02:00:00Z", End: "2025-06-14T08:00:00Z", Allows: "patch upgrades only", Exclusions: []string{"stay"}},
+					{Start: "2025-06-15T02:00:00Z", End: "2025-06-15T08:00:00Z", Allows: "patch upgrades only", Exclusions: []string{"stay"}},
+				},
+				ReleaseChannel: "REGULAR",
+				CurrentVersion: "1.32.9-gke.100",
+				NextUpgrade:    &nextUpgrade{Version: "1.33.5-gke.1000", Minor: true, BlockedReason: "exclusions block it in all the listed maintenance windows"},
+				upgradeChecked: true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newMaintenancePolicyOutput(tt.cluster, tt.cluster.GetMaintenancePolicy(), sc, maintenanceNow, 2)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.want, got, cmp.AllowUnexported(maintenancePolicyOutput{})); diff != "" {
+				t.Errorf("newMaintenancePolicyOutput() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
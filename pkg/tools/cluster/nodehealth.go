@@ -0,0 +1,743 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	container "cloud.google.com/go/container/apiv1"
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/sync/errgroup"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+const (
+	checkCondition = "condition"
+	checkTaint     = "taint"
+	checkResources = "resources"
+	checkPods      = "pods"
+	checkEvents    = "events"
+	checkInstance  = "instance"
+	checkNodePool  = "node_pool"
+
+	defaultNodeEventWindow = time.Hour
+	// nodePoolOperationWindow is how far back finished node pool operations
+	// are reported.
+	nodePoolOperationWindow = 24 * time.Hour
+	// podPendingGrace is how long a scheduled pod may stay pending before
+	// it's reported.
+	podPendingGrace = 5 * time.Minute
+	// podTerminatingGrace is how long a pod may outlive its termination
+	// grace period before it's reported as stuck.
+	podTerminatingGrace = 5 * time.Minute
+
+	maxDiagnosedNodes    = 200
+	nodeDiagnosisWorkers = 8
+)
+
+// badContainerReasons are the waiting reasons of containers that won't
+// start without help.
+var badContainerReasons = []string{
+	"CrashLoopBackOff",
+	"ImagePullBackOff",
+	"ErrImagePull",
+	"InvalidImageName",
+	"CreateContainerConfigError",
+	"CreateContainerError",
+	"RunContainerError",
+	"ContainerCannotRun",
+}
+
+// criticalNodeConditions are the node conditions, besides Ready, that make a
+// node unusable when they are true.
+var criticalNodeConditions = []corev1.NodeConditionType{
+	corev1.NodeMemoryPressure,
+	corev1.NodeDiskPressure,
+	corev1.NodePIDPressure,
+	corev1.NodeNetworkUnavailable,
+	// Reported by node-problem-detector.
+	"KernelDeadlock",
+	"ReadonlyFilesystem",
+}
+
+// nodeTaintProblems describe the taints that aren't explained by a node
+// condition.
+var nodeTaintProblems = map[string]struct {
+	severity string
+	summary  string
+}{
+	"node.kubernetes.io/out-of-service":              {severityCritical, "Node is marked out of service"},
+	"node.cloudprovider.kubernetes.io/uninitialized": {severityWarning, "Cloud provider hasn't initialized the node"},
+	"ToBeDeletedByClusterAutoscaler":                 {severityInfo, "Cluster autoscaler is removing the node"},
+	"DeletionCandidateOfClusterAutoscaler":           {severityInfo, "Cluster autoscaler considers the node unneeded"},
+}
+
+// nodeCloudAPI reads the GKE node pools and Compute Engine instances behind
+// Kubernetes nodes.
+type nodeCloudAPI interface {
+	getNodePool(ctx context.Context, name string) (*containerpb.NodePool, error)
+	listOperations(ctx context.Context, parent string) ([]*containerpb.Operation, error)
+	getInstance(ctx context.Context, project, zone, name string) (*compute.Instance, error)
+}
+
+type gcpNodeClient struct {
+	cm        *container.ClusterManagerClient
+	instances *compute.InstancesService
+}
+
+func (c *gcpNodeClient) getNodePool(ctx context.Context, name string) (*containerpb.NodePool, error) {
+	return c.cm.GetNodePool(ctx, &containerpb.GetNodePoolRequest{Name: name})
+}
+
+func (c *gcpNodeClient) listOperations(ctx context.Context, parent string) ([]*containerpb.Operation, error) {
+	resp, err := c.cm.ListOperations(ctx, &containerpb.ListOperationsRequest{Parent: parent})
+	return resp.GetOperations(), err
+}
+
+func (c *gcpNodeClient) getInstance(ctx context.Context, project, zone, name string) (*compute.Instance, error) {
+	return c.instances.Get(project, zone, name).Context(ctx).Do()
+}
+
+type diagnoseNodesArgs struct {
+	ProjectID   string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location    string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster     string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Node        string `json:"node,omitempty" jsonschema:"Name of the node to diagnose."`
+	NodePool    string `json:"node_pool,omitempty" jsonschema:"Diagnose every node of this node pool instead of a single node."`
+	EventWindow string `json:"event_window,omitempty" jsonschema:"How far back to look at node events and container OOM kills, like '30m' or '6h'. Defaults to 1h."`
+}
+
+type nodeProblem struct {
+	Severity string   `json:"severity" jsonschema:"critical, warning or info."`
+	Check    string   `json:"check" jsonschema:"What found it: condition, taint, resources, pods, events, instance or node_pool."`
+	Summary  string   `json:"summary"`
+	Detail   string   `json:"detail,omitempty" jsonschema:"More about the problem, from the first affected node."`
+	Nodes    []string `json:"nodes,omitempty" jsonschema:"The affected nodes."`
+	NodePool string   `json:"node_pool,omitempty"`
+}
+
+type nodeCondition struct {
+	Type           string `json:"type"`
+	Status         string `json:"status"`
+	Reason         string `json:"reason,omitempty"`
+	Message        string `json:"message,omitempty"`
+	LastTransition string `json:"last_transition,omitempty"`
+}
+
+type nodeResource struct {
+	Name        string `json:"name"`
+	Allocatable string `json:"allocatable"`
+	Requested   string `json:"requested" jsonschema:"Sum of the requests of the node's running and pending pods."`
+	Percent     int64  `json:"percent" jsonschema:"Requested as a percentage of allocatable."`
+}
+
+type badPod struct {
+	Namespace string `json:"namespace"`
+	Name      string `json:"name"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message,omitempty"`
+}
+
+type gceInstance struct {
+	Name          string `json:"name"`
+	Zone          string `json:"zone"`
+	Status        string `json:"status,omitempty" jsonschema:"Compute Engine instance status, like RUNNING, STOPPING, TERMINATED or REPAIRING."`
+	StatusMessage string `json:"status_message,omitempty"`
+	MachineType   string `json:"machine_type,omitempty"`
+	Error         string `json:"error,omitempty" jsonschema:"Why the instance couldn't be read."`
+}
+
+type nodeDiagnosis struct {
+	Name           string          `json:"name"`
+	NodePool       string          `json:"node_pool,omitempty"`
+	KubeletVersion string          `json:"kubelet_version,omitempty"`
+	Unschedulable  bool            `json:"unschedulable,omitempty" jsonschema:"Whether the node is cordoned."`
+	Conditions     []nodeCondition `json:"conditions,omitempty"`
+	Taints         []string        `json:"taints,omitempty" jsonschema:"Taints as key=value:effect."`
+	Resources      []nodeResource  `json:"resources,omitempty"`
+	BadPods        []badPod        `json:"bad_pods,omitempty" jsonschema:"Pods on the node that are failing, stuck or crash looping."`
+	Events         []k8sEvent      `json:"events,omitempty" jsonschema:"Recent events about the node, oldest first."`
+	Instance       *gceInstance    `json:"instance,omitempty"`
+	Errors         []string        `json:"errors,omitempty" jsonschema:"Checks that couldn't be run."`
+}
+
+type nodePoolDiagnosis struct {
+	Name          string             `json:"name"`
+	Status        string             `json:"status,omitempty" jsonschema:"GKE node pool status, like RUNNING, RECONCILING or ERROR."`
+	StatusMessage string             `json:"status_message,omitempty"`
+	Version       string             `json:"version,omitempty"`
+	AutoUpgrade   bool               `json:"auto_upgrade,omitempty"`
+	AutoRepair    bool               `json:"auto_repair,omitempty"`
+	Conditions    []string           `json:"conditions,omitempty"`
+	Operations    []operationSummary `json:"operations,omitempty" jsonschema:"Running operations on the node pool, and those that finished in the last day."`
+	Error         string             `json:"error,omitempty" jsonschema:"Why the node pool couldn't be read."`
+}
+
+type diagnoseNodesOutput struct {
+	Cluster   string              `json:"cluster"`
+	Problems  []nodeProblem       `json:"problems,omitempty" jsonschema:"Suspected problems, the most severe and widespread first."`
+	Nodes     []nodeDiagnosis     `json:"nodes"`
+	NodePools []nodePoolDiagnosis `json:"node_pools,omitempty"`
+}
+
+func (h *handlers) installNodeHealth(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "diagnose_nodes",
+		Description: "Diagnose the health of one GKE node, or every node of a node pool, and return a ranked list of suspected problems. " +
+			"Checks every node condition and taint, allocatable versus requested resources, pods in bad states, recent node events, the Compute Engine instance behind each node, " +
+			"and the node pool's status and upgrade and repair operations from the GKE API. Use it before collecting SOS reports or node logs.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.diagnoseNodes)
+}
+
+func (h *handlers) diagnoseNodes(ctx context.Context, _ *mcp.CallToolRequest, args *diagnoseNodesArgs) (*mcp.CallToolResult, *diagnoseNodesOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if (args.Node == "") == (args.NodePool == "") {
+		return nil, nil, fmt.Errorf("exactly one of node and node_pool must be set")
+	}
+	window := defaultNodeEventWindow
+	if args.EventWindow != "" {
+		d, err := time.ParseDuration(args.EventWindow)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid event_window %q, must be a positive duration like '30m' or '6h'", args.EventWindow)
+		}
+		window = d
+	}
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	cloud, err := h.nodeCloudAPI(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nodes []corev1.Node
+	if args.Node != "" {
+		node, err := k.clientset.CoreV1().Nodes().Get(ctx, args.Node, metav1.GetOptions{})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get node %s: %w", args.Node, err)
+		}
+		nodes = append(nodes, *node)
+	} else {
+		list, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: gkeNodePoolLabel + "=" + args.NodePool})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to list nodes: %w", err)
+		}
+		if len(list.Items) > maxDiagnosedNodes {
+			return nil, nil, fmt.Errorf("node pool %s has %d nodes, at most %d can be diagnosed at once: diagnose single nodes instead", args.NodePool, len(list.Items), maxDiagnosedNodes)
+		}
+		nodes = list.Items
+		sort.Slice(nodes, func(i, j int) bool { return nodes[i].Name < nodes[j].Name })
+	}
+
+	now := time.Now()
+	out := &diagnoseNodesOutput{Cluster: args.Cluster, Nodes: make([]nodeDiagnosis, len(nodes))}
+	var mu sync.Mutex
+	var problems []nodeProblem
+	g, gctx := errgroup.WithContext(ctx)
+	g.SetLimit(nodeDiagnosisWorkers)
+	for i := range nodes {
+		g.Go(func() error {
+			d, p := diagnoseNode(gctx, k, cloud, &nodes[i], now, window)
+			out.Nodes[i] = d
+			mu.Lock()
+			problems = append(problems, p...)
+			mu.Unlock()
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	pools := []string{args.NodePool}
+	if args.NodePool == "" {
+		pools = []string{out.Nodes[0].NodePool}
+	}
+	if pools[0] != "" {
+		clusterName := fmt.Sprintf("projects/%s/locations/%s/clusters/%s", args.ProjectID, args.Location, args.Cluster)
+		ops, opsErr := cloud.listOperations(ctx, fmt.Sprintf("projects/%s/locations/%s", args.ProjectID, args.Location))
+		for _, pool := range pools {
+			d, p := diagnoseNodePool(ctx, cloud, clusterName, pool, ops, opsErr, out.Nodes, now)
+			out.NodePools = append(out.NodePools, d)
+			problems = append(problems, p...)
+		}
+	}
+	out.Problems = rankNodeProblems(problems)
+
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+// nodeCloudAPI returns the clients of the GKE and Compute Engine APIs.
+func (h *handlers) nodeCloudAPI(ctx context.Context) (nodeCloudAPI, error) {
+	if h.nodeCloud != nil {
+		return h.nodeCloud, nil
+	}
+	svc, err := compute.NewService(ctx, h.c.ClientOptions(config.ServiceCompute)...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create compute client: %w", err)
+	}
+	return &gcpNodeClient{cm: h.cmClient, instances: compute.NewInstancesService(svc)}, nil
+}
+
+// diagnoseNode checks one node. Checks that fail are recorded in the
+// diagnosis rather than failing the others.
+func diagnoseNode(ctx context.Context, k *kubeClients, cloud nodeCloudAPI, node *corev1.Node, now time.Time, window time.Duration) (nodeDiagnosis, []nodeProblem) {
+	d := nodeDiagnosis{
+		Name:           node.Name,
+		NodePool:       node.Labels[gkeNodePoolLabel],
+		KubeletVersion: node.Status.NodeInfo.KubeletVersion,
+		Unschedulable:  node.Spec.Unschedulable,
+	}
+	var problems []nodeProblem
+	add := func(severity, check, summary, detail string) {
+		problems = append(problems, nodeProblem{Severity: severity, Check: check, Summary: summary, Detail: detail, Nodes: []string{node.Name}})
+	}
+
+	for _, c := range node.Status.Conditions {
+		d.Conditions = append(d.Conditions, nodeCondition{
+			Type:           string(c.Type),
+			Status:         string(c.Status),
+			Reason:         c.Reason,
+			Message:        c.Message,
+			LastTransition: formatTime(c.LastTransitionTime.Time),
+		})
+		switch {
+		case c.Type == corev1.NodeReady && c.Status == corev1.ConditionUnknown:
+			add(severityCritical, checkCondition, "Kubelet stopped posting node status", c.Message)
+		case c.Type == corev1.NodeReady && c.Status != corev1.ConditionTrue:
+			add(severityCritical, checkCondition, "Node is not ready: "+valueOrDash(c.Reason), c.Message)
+		case c.Type != corev1.NodeReady && c.Status == corev1.ConditionTrue:
+			severity := severityWarning
+			if slices.Contains(criticalNodeConditions, c.Type) {
+				severity = severityCritical
+			}
+			add(severity, checkCondition, fmt.Sprintf("Node has %s: %s", c.Type, valueOrDash(c.Reason)), c.Message)
+		}
+	}
+
+	if node.Spec.Unschedulable {
+		add(severityWarning, checkTaint, "Node is cordoned", "")
+	}
+	for _, t := range node.Spec.Taints {
+		taint := t.Key
+		if t.Value != "" {
+			taint += "=" + t.Value
+		}
+		d.Taints = append(d.Taints, taint+":"+string(t.Effect))
+		if p, ok := nodeTaintProblems[t.Key]; ok {
+			add(p.severity, checkTaint, p.summary, "")
+		}
+	}
+
+	pods, err := nodePods(ctx, k, node.Name)
+	if err != nil {
+		d.Errors = append(d.Errors, err.Error())
+	} else {
+		var p []nodeProblem
+		d.Resources, p = nodeResources(node, pods)
+		problems = append(problems, p...)
+		byReason := map[string][]badPod{}
+		for i := range pods {
+			if bad, ok := podProblem(&pods[i], now, window); ok {
+				d.BadPods = append(d.BadPods, bad)
+				byReason[bad.Reason] = append(byReason[bad.Reason], bad)
+			}
+		}
+		for reason, bad := range byReason {
+			severity := severityWarning
+			if reason == string(corev1.PodUnknown) {
+				severity = severityCritical
+			}
+			add(severity, checkPods, "Pods are "+reason, fmt.Sprintf("%d pods, like %s/%s", len(bad), bad[0].Namespace, bad[0].Name))
+		}
+	}
+
+	events, err := nodeEvents(ctx, k, node.Name, now.Add(-window))
+	if err != nil {
+		d.Errors = append(d.Errors, err.Error())
+	}
+	d.Events = events
+	// One problem per reason, so repeated events don't count as several
+	// problems. Events are oldest first; describe the latest.
+	warnings := map[string][]k8sEvent{}
+	var reasons []string
+	for _, e := range events {
+		if e.Type != corev1.EventTypeWarning {
+			continue
+		}
+		if _, ok := warnings[e.Reason]; !ok {
+			reasons = append(reasons, e.Reason)
+		}
+		warnings[e.Reason] = append(warnings[e.Reason], e)
+	}
+	for _, reason := range reasons {
+		w := warnings[reason]
+		detail := w[len(w)-1].Message
+		if len(w) > 1 {
+			detail = fmt.Sprintf("%d events, the latest: %s", len(w), detail)
+		}
+		add(severityWarning, checkEvents, "Warning event "+reason, detail)
+	}
+
+	if project, zone, name, ok := parseGCEProviderID(node.Spec.ProviderID); ok {
+		d.Instance = &gceInstance{Name: name, Zone: zone}
+		inst, err := cloud.getInstance(ctx, project, zone, name)
+		var gerr *googleapi.Error
+		switch {
+		case errors.As(err, &gerr) && gerr.Code == http.StatusNotFound:
+			d.Instance.Error = "instance not found"
+			add(severityCritical, checkInstance, "Compute Engine instance no longer exists", "")
+		case err != nil:
+			d.Instance.Error = err.Error()
+		default:
+			d.Instance.Status, d.Instance.StatusMessage = inst.Status, inst.StatusMessage
+			d.Instance.MachineType = inst.MachineType[strings.LastIndex(inst.MachineType, "/")+1:]
+			if inst.Status != "RUNNING" {
+				add(severityCritical, checkInstance, "Compute Engine instance is "+inst.Status, inst.StatusMessage)
+			}
+		}
+	}
+	return d, problems
+}
+
+// parseGCEProviderID splits a provider ID like gce://project/zone/instance.
+func parseGCEProviderID(providerID string) (project, zone, name string, ok bool) {
+	rest, found := strings.CutPrefix(providerID, "gce://")
+	if !found {
+		return "", "", "", false
+	}
+	parts := strings.Split(rest, "/")
+	if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+		return "", "", "", false
+	}
+	return parts[0], parts[1], parts[2], true
+}
+
+func nodePods(ctx context.Context, k *kubeClients, node string) ([]corev1.Pod, error) {
+	list, err := k.clientset.CoreV1().Pods(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("spec.nodeName", node).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list pods: %w", err)
+	}
+	return slices.DeleteFunc(list.Items, func(p corev1.Pod) bool { return p.Spec.NodeName != node }), nil
+}
+
+// nodeEvents returns the events about a node last seen after since.
+func nodeEvents(ctx context.Context, k *kubeClients, node string, since time.Time) ([]k8sEvent, error) {
+	list, err := k.clientset.CoreV1().Events(metav1.NamespaceAll).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.AndSelectors(
+			fields.OneTermEqualSelector("involvedObject.kind", "Node"),
+			fields.OneTermEqualSelector("involvedObject.name", node),
+		).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	var events []k8sEvent
+	for _, e := range list.Items {
+		if e.InvolvedObject.Kind != "Node" || e.InvolvedObject.Name != node {
+			continue
+		}
+		if ev := newK8sEvent(&e); ev.lastSeen.After(since) {
+			events = append(events, ev)
+		}
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].lastSeen.Before(events[j].lastSeen) })
+	return events, nil
+}
+
+// podProblem reports whether a pod is failing, stuck or crash looping.
+func podProblem(pod *corev1.Pod, now time.Time, window time.Duration) (badPod, bool) {
+	bad := badPod{Namespace: pod.Namespace, Name: pod.Name}
+	if ts := pod.DeletionTimestamp; ts != nil {
+		grace := time.Duration(0)
+		if pod.DeletionGracePeriodSeconds != nil {
+			grace = time.Duration(*pod.DeletionGracePeriodSeconds) * time.Second
+		}
+		if now.Sub(ts.Time) > grace+podTerminatingGrace {
+			bad.Reason = "stuck terminating"
+			return bad, true
+		}
+		return bad, false
+	}
+	switch pod.Status.Phase {
+	case corev1.PodSucceeded:
+		return bad, false
+	case corev1.PodFailed:
+		bad.Reason, bad.Message = firstNonEmpty(pod.Status.Reason, string(corev1.PodFailed)), pod.Status.Message
+		return bad, true
+	case corev1.PodUnknown:
+		bad.Reason, bad.Message = string(corev1.PodUnknown), pod.Status.Message
+		return bad, true
+	}
+	for _, cs := range slices.Concat(pod.Status.InitContainerStatuses, pod.Status.ContainerStatuses) {
+		if w := cs.State.Waiting; w != nil && slices.Contains(badContainerReasons, w.Reason) {
+			bad.Reason, bad.Message = w.Reason, w.Message
+			return bad, true
+		}
+		if t := cs.LastTerminationState.Terminated; t != nil && t.Reason == "OOMKilled" && now.Sub(t.FinishedAt.Time) < window {
+			bad.Reason = "OOMKilled"
+			bad.Message = fmt.Sprintf("container %s was OOM killed at %s", cs.Name, formatTime(t.FinishedAt.Time))
+			return bad, true
+		}
+	}
+	if pod.Status.Phase == corev1.PodPending && now.Sub(pod.CreationTimestamp.Time) > podPendingGrace {
+		bad.Reason = "stuck pending"
+		return bad, true
+	}
+	return bad, false
+}
+
+// nodeResources compares the requests of the pods on a node with its
+// allocatable resources.
+func nodeResources(node *corev1.Node, pods []corev1.Pod) ([]nodeResource, []nodeProblem) {
+	requested := corev1.ResourceList{}
+	var running int64
+	for i := range pods {
+		pod := &pods[i]
+		if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		running++
+		for name, q := range podRequests(pod) {
+			sum := requested[name]
+			sum.Add(q)
+			requested[name] = sum
+		}
+	}
+	requested[corev1.ResourcePods] = *resource.NewQuantity(running, resource.DecimalSI)
+
+	var out []nodeResource
+	var problems []nodeProblem
+	names := make([]string, 0, len(node.Status.Allocatable))
+	for name := range node.Status.Allocatable {
+		names = append(names, string(name))
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		name := corev1.ResourceName(n)
+		alloc, req := node.Status.Allocatable[name], requested[name]
+		if alloc.IsZero() && req.IsZero() {
+			continue
+		}
+		r := nodeResource{Name: n, Allocatable: alloc.String(), Requested: req.String()}
+		if !alloc.IsZero() {
+			r.Percent = req.MilliValue() * 100 / alloc.MilliValue()
+		}
+		out = append(out, r)
+		switch {
+		case name == corev1.ResourcePods && req.Cmp(alloc) >= 0:
+			problems = append(problems, nodeProblem{Severity: severityWarning, Check: checkResources, Summary: "Node is at its pod capacity", Detail: fmt.Sprintf("%s of %s pods", req.String(), alloc.String()), Nodes: []string{node.Name}})
+		case name != corev1.ResourcePods && req.Cmp(alloc) > 0:
+			problems = append(problems, nodeProblem{Severity: severityWarning, Check: checkResources, Summary: fmt.Sprintf("Pods request more %s than is allocatable", n), Detail: fmt.Sprintf("%s requested, %s allocatable", req.String(), alloc.String()), Nodes: []string{node.Name}})
+		}
+	}
+	return out, problems
+}
+
+// podRequests returns the resources a pod reserves on its node: the larger
+// of its containers' requests and of any init container's, plus overhead.
+func podRequests(pod *corev1.Pod) corev1.ResourceList {
+	reqs := corev1.ResourceList{}
+	for _, c := range pod.Spec.Containers {
+		for name, q := range c.Resources.Requests {
+			sum := reqs[name]
+			sum.Add(q)
+			reqs[name] = sum
+		}
+	}
+	for _, c := range pod.Spec.InitContainers {
+		for name, q := range c.Resources.Requests {
+			if cur, ok := reqs[name]; !ok || q.Cmp(cur) > 0 {
+				reqs[name] = q
+			}
+		}
+	}
+	for name, q := range pod.Spec.Overhead {
+		sum := reqs[name]
+		sum.Add(q)
+		reqs[name] = sum
+	}
+	return reqs
+}
+
+// diagnoseNodePool checks a node pool's status and its operations, among
+// ops, in the GKE API.
+func diagnoseNodePool(ctx context.Context, cloud nodeCloudAPI, clusterName, pool string, ops []*containerpb.Operation, opsErr error, nodes []nodeDiagnosis, now time.Time) (nodePoolDiagnosis, []nodeProblem) {
+	d := nodePoolDiagnosis{Name: pool}
+	var problems []nodeProblem
+	add := func(severity, summary, detail string) {
+		problems = append(problems, nodeProblem{Severity: severity, Check: checkNodePool, Summary: summary, Detail: detail, NodePool: pool})
+	}
+
+	np, err := cloud.getNodePool(ctx, clusterName+"/nodePools/"+pool)
+	if err != nil {
+		d.Error = err.Error()
+		return d, nil
+	}
+	d.Status, d.StatusMessage, d.Version = np.GetStatus().String(), np.GetStatusMessage(), np.GetVersion()
+	d.AutoUpgrade, d.AutoRepair = np.GetManagement().GetAutoUpgrade(), np.GetManagement().GetAutoRepair()
+	switch np.GetStatus() {
+	case containerpb.NodePool_RUNNING:
+	case containerpb.NodePool_ERROR, containerpb.NodePool_RUNNING_WITH_ERROR:
+		add(severityCritical, "Node pool status is "+d.Status, d.StatusMessage)
+	default:
+		add(severityInfo, "Node pool is "+d.Status, d.StatusMessage)
+	}
+	for _, c := range np.GetConditions() {
+		d.Conditions = append(d.Conditions, c.GetMessage())
+		add(severityWarning, "Node pool condition "+c.GetCanonicalCode().String(), c.GetMessage())
+	}
+	if !d.AutoRepair {
+		add(severityInfo, "Node auto-repair is disabled", "")
+	}
+	for _, n := range nodes {
+		if n.KubeletVersion != "" && d.Version != "" && strings.TrimPrefix(n.KubeletVersion, "v") != d.Version {
+			problems = append(problems, nodeProblem{Severity: severityInfo, Check: checkNodePool, Summary: "Node version differs from its node pool's", Detail: fmt.Sprintf("%s, node pool %s", n.KubeletVersion, d.Version), Nodes: []string{n.Name}, NodePool: pool})
+		}
+	}
+
+	if opsErr != nil {
+		d.Error = fmt.Sprintf("failed to list operations: %v", opsErr)
+		return d, problems
+	}
+	target := clusterName[strings.LastIndex(clusterName, "/clusters/"):] + "/nodePools/" + pool
+	for _, op := range ops {
+		if !strings.HasSuffix(op.GetTargetLink(), target) {
+			continue
+		}
+		s := newOperationSummary(op)
+		if op.GetStatus() == containerpb.Operation_DONE {
+			end, err := time.Parse(time.RFC3339, op.GetEndTime())
+			if err != nil || now.Sub(end) > nodePoolOperationWindow {
+				continue
+			}
+			switch {
+			case s.Error != "":
+				add(severityWarning, s.OperationType+" operation failed", s.Error)
+			case op.GetOperationType() == containerpb.Operation_AUTO_REPAIR_NODES:
+				add(severityInfo, "Nodes were auto-repaired recently", "at "+s.EndTime)
+			}
+		} else {
+			add(severityInfo, s.OperationType+" operation in progress", firstNonEmpty(s.Progress, s.Status))
+		}
+		d.Operations = append(d.Operations, s)
+	}
+	return d, problems
+}
+
+// rankNodeProblems merges the same problem on several nodes and sorts them,
+// the most severe and widespread first.
+func rankNodeProblems(problems []nodeProblem) []nodeProblem {
+	// Nodes are diagnosed concurrently; take details from the first node by
+	// name.
+	sort.SliceStable(problems, func(i, j int) bool {
+		return slices.Compare(problems[i].Nodes, problems[j].Nodes) < 0
+	})
+	var out []nodeProblem
+	index := map[string]int{}
+	for _, p := range problems {
+		key := strings.Join([]string{p.Severity, p.Check, p.Summary, p.NodePool}, "\x00")
+		if i, ok := index[key]; ok {
+			for _, n := range p.Nodes {
+				if !slices.Contains(out[i].Nodes, n) {
+					out[i].Nodes = append(out[i].Nodes, n)
+				}
+			}
+			continue
+		}
+		index[key] = len(out)
+		out = append(out, p)
+	}
+	for i := range out {
+		sort.Strings(out[i].Nodes)
+	}
+	sort.SliceStable(out, func(i, j int) bool {
+		pi, pj := out[i], out[j]
+		if ri, rj := severityRank(pi.Severity), severityRank(pj.Severity); ri != rj {
+			return ri < rj
+		}
+		if len(pi.Nodes) != len(pj.Nodes) {
+			return len(pi.Nodes) > len(pj.Nodes)
+		}
+		return pi.Summary < pj.Summary
+	})
+	return out
+}
+
+func (o *diagnoseNodesOutput) summary() string {
+	counts := map[string]int{}
+	for _, p := range o.Problems {
+		counts[p.Severity]++
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "Diagnosed %d nodes of cluster %s: %d critical, %d warning and %d info problems.", len(o.Nodes), o.Cluster, counts[severityCritical], counts[severityWarning], counts[severityInfo])
+	if len(o.Problems) > 0 {
+		b.WriteString("\n\n")
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEVERITY\tCHECK\tAFFECTS\tSUMMARY\tDETAIL")
+		for _, p := range o.Problems {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", p.Severity, p.Check, p.affects(), p.Summary, valueOrDash(strings.ReplaceAll(p.Detail, "\n", " ")))
+		}
+		_ = w.Flush()
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// affects names the nodes or node pool a problem affects, for text
+// summaries.
+func (p nodeProblem) affects() string {
+	switch {
+	case len(p.Nodes) == 0:
+		return "node pool " + p.NodePool
+	case len(p.Nodes) <= 3:
+		return strings.Join(p.Nodes, ", ")
+	default:
+		return fmt.Sprintf("%s and %d more", strings.Join(p.Nodes[:2], ", "), len(p.Nodes)-2)
+	}
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
@@ -0,0 +1,306 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	compute "google.golang.org/api/compute/v1"
+	"google.golang.org/api/googleapi"
+	"google.golang.org/genproto/googleapis/rpc/status"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+)
+
+type fakeNodeCloud struct {
+	pools     map[string]*containerpb.NodePool
+	ops       []*containerpb.Operation
+	instances map[string]*compute.Instance
+}
+
+func (f *fakeNodeCloud) getNodePool(_ context.Context, name string) (*containerpb.NodePool, error) {
+	if np, ok := f.pools[name]; ok {
+		return np, nil
+	}
+	return nil, fmt.Errorf("node pool %s not found", name)
+}
+
+func (f *fakeNodeCloud) listOperations(context.Context, string) ([]*containerpb.Operation, error) {
+	return f.ops, nil
+}
+
+func (f *fakeNodeCloud) getInstance(_ context.Context, project, zone, name string) (*compute.Instance, error) {
+	if inst, ok := f.instances[project+"/"+zone+"/"+name]; ok {
+		return inst, nil
+	}
+	return nil, &googleapi.Error{Code: http.StatusNotFound}
+}
+
+func testPod(name, node string, cpu string, mutate func(*corev1.Pod)) *corev1.Pod {
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(time.Now().Add(-time.Hour))},
+		Spec: corev1.PodSpec{
+			NodeName: node,
+			Containers: []corev1.Container{{
+				Name:      "app",
+				Resources: corev1.ResourceRequirements{Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpu)}},
+			}},
+		},
+		Status: corev1.PodStatus{Phase: corev1.PodRunning},
+	}
+	if mutate != nil {
+		mutate(pod)
+	}
+	return pod
+}
+
+func TestDiagnoseNodes(t *testing.T) {
+	now := time.Now()
+	node1 := testNode("node-1", corev1.ConditionFalse)
+	node1.Status.Conditions[0].Reason = "KubeletNotReady"
+	node1.Status.Conditions[0].Message = "container runtime is down"
+	node1.Status.Conditions = append(node1.Status.Conditions, corev1.NodeCondition{Type: corev1.NodeDiskPressure, Status: corev1.ConditionTrue, Reason: "KubeletHasDiskPressure"})
+	node1.Spec.Unschedulable = true
+	node1.Spec.Taints = []corev1.Taint{{Key: "node.kubernetes.io/unschedulable", Effect: corev1.TaintEffectNoSchedule}, {Key: "ToBeDeletedByClusterAutoscaler", Value: "1700000000", Effect: corev1.TaintEffectNoSchedule}}
+	node2 := testNode("node-2", corev1.ConditionTrue)
+	node3 := testNode("node-3", corev1.ConditionTrue)
+	for _, n := range []*corev1.Node{node1, node2, node3} {
+		n.Labels = map[string]string{gkeNodePoolLabel: "pool-a"}
+		n.Spec.ProviderID = "gce://p/us-central1-a/" + n.Name
+		n.Status.NodeInfo.KubeletVersion = "v1.33.1-gke.100"
+		n.Status.Allocatable = corev1.ResourceList{
+			corev1.ResourceCPU:    resource.MustParse("2"),
+			corev1.ResourceMemory: resource.MustParse("4Gi"),
+			corev1.ResourcePods:   resource.MustParse("110"),
+		}
+	}
+	node3.Status.NodeInfo.KubeletVersion = "v1.32.4-gke.200"
+	other := testNode("other-1", corev1.ConditionFalse)
+	other.Labels = map[string]string{gkeNodePoolLabel: "pool-b"}
+
+	event := func(name, node, reason string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "default", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Node", Name: node},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " happened",
+			LastTimestamp:  metav1.NewTime(last),
+		}
+	}
+	h := testKubeHandlers([]runtime.Object{
+		node1, node2, node3, other,
+		testPod("web", "node-1", "1500m", nil),
+		testPod("crash", "node-1", "1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}}
+		}),
+		testPod("evicted", "node-1", "1", func(p *corev1.Pod) {
+			p.Status.Phase, p.Status.Reason = corev1.PodFailed, "Evicted"
+		}),
+		testPod("db", "node-2", "500m", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: metav1.NewTime(now.Add(-10 * time.Minute))}}}}
+		}),
+		testPod("crash-other", "other-1", "1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{Name: "app", State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}}}
+		}),
+		event("e1", "node-1", "ContainerdUnhealthy", now.Add(-time.Minute)),
+		event("e4", "node-1", "ContainerdUnhealthy", now.Add(-2*time.Minute)),
+		event("e2", "node-1", "Rebooted", now.Add(-3*time.Hour)),
+		event("e3", "other-1", "Rebooted", now.Add(-time.Minute)),
+	})
+	target := "https://container.googleapis.com/v1/projects/p/zones/us-central1/clusters/c/nodePools/"
+	h.nodeCloud = &fakeNodeCloud{
+		pools: map[string]*containerpb.NodePool{
+			"projects/p/locations/us-central1/clusters/c/nodePools/pool-a": {
+				Name:       "pool-a",
+				Status:     containerpb.NodePool_RECONCILING,
+				Version:    "1.33.1-gke.100",
+				Management: &containerpb.NodeManagement{AutoUpgrade: true, AutoRepair: true},
+			},
+		},
+		ops: []*containerpb.Operation{
+			{Name: "op-1", OperationType: containerpb.Operation_UPGRADE_NODES, Status: containerpb.Operation_RUNNING, TargetLink: target + "pool-a"},
+			{Name: "op-2", OperationType: containerpb.Operation_AUTO_REPAIR_NODES, Status: containerpb.Operation_DONE, TargetLink: target + "pool-a", EndTime: now.Add(-time.Hour).Format(time.RFC3339), Error: &status.Status{Message: "instance not found"}},
+			{Name: "op-3", OperationType: containerpb.Operation_AUTO_REPAIR_NODES, Status: containerpb.Operation_DONE, TargetLink: target + "pool-a", EndTime: now.Add(-48 * time.Hour).Format(time.RFC3339)},
+			{Name: "op-4", OperationType: containerpb.Operation_UPGRADE_NODES, Status: containerpb.Operation_RUNNING, TargetLink: target + "pool-b"},
+		},
+		instances: map[string]*compute.Instance{
+			"p/us-central1-a/node-1": {Status: "RUNNING", MachineType: "zones/us-central1-a/machineTypes/e2-standard-2"},
+			"p/us-central1-a/node-2": {Status: "TERMINATED", StatusMessage: "preempted"},
+		},
+	}
+
+	_, out, err := h.diagnoseNodes(context.Background(), nil, &diagnoseNodesArgs{ProjectID: "p", Location: "us-central1", Cluster: "c", NodePool: "pool-a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, p := range out.Problems {
+		got = append(got, strings.Join([]string{p.Severity, p.Check, p.affects(), p.Summary}, " | "))
+	}
+	want := []string{
+		"critical | instance | node-2 | Compute Engine instance is TERMINATED",
+		"critical | instance | node-3 | Compute Engine instance no longer exists",
+		"critical | condition | node-1 | Node has DiskPressure: KubeletHasDiskPressure",
+		"critical | condition | node-1 | Node is not ready: KubeletNotReady",
+		"warning | taint | node-1 | Node is cordoned",
+		"warning | pods | node-1 | Pods are CrashLoopBackOff",
+		"warning | pods | node-1 | Pods are Evicted",
+		"warning | pods | node-2 | Pods are OOMKilled",
+		"warning | resources | node-1 | Pods request more cpu than is allocatable",
+		"warning | events | node-1 | Warning event ContainerdUnhealthy",
+		"warning | node_pool | node pool pool-a | AUTO_REPAIR_NODES operation failed",
+		"info | taint | node-1 | Cluster autoscaler is removing the node",
+		"info | node_pool | node-3 | Node version differs from its node pool's",
+		"info | node_pool | node pool pool-a | Node pool is RECONCILING",
+		"info | node_pool | node pool pool-a | UPGRADE_NODES operation in progress",
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("problems mismatch (-want +got):\n%s", diff)
+	}
+
+	if len(out.Nodes) != 3 || len(out.NodePools) != 1 {
+		t.Fatalf("diagnosed %d nodes and %d node pools, want 3 and 1", len(out.Nodes), len(out.NodePools))
+	}
+	n1 := out.Nodes[0]
+	wantResources := []nodeResource{
+		{Name: "cpu", Allocatable: "2", Requested: "2500m", Percent: 125},
+		{Name: "memory", Allocatable: "4Gi", Requested: "0", Percent: 0},
+		{Name: "pods", Allocatable: "110", Requested: "2", Percent: 1},
+	}
+	if diff := cmp.Diff(wantResources, n1.Resources); diff != "" {
+		t.Errorf("resources mismatch (-want +got):\n%s", diff)
+	}
+	if len(n1.Events) != 2 || n1.Instance.MachineType != "e2-standard-2" || len(n1.Taints) != 2 {
+		t.Errorf("node-1 diagnosis = %+v, want two recent events, the machine type and two taints", n1)
+	}
+	for _, p := range out.Problems {
+		if p.Check == checkEvents && p.Detail != "2 events, the latest: ContainerdUnhealthy happened" {
+			t.Errorf("events problem detail = %q, want both events counted", p.Detail)
+		}
+	}
+	var ops []string
+	for _, op := range out.NodePools[0].Operations {
+		ops = append(ops, op.Name)
+	}
+	if diff := cmp.Diff([]string{"op-1", "op-2"}, ops); diff != "" {
+		t.Errorf("node pool operations mismatch (-want +got):\n%s", diff)
+	}
+
+	_, out, err = h.diagnoseNodes(context.Background(), nil, &diagnoseNodesArgs{ProjectID: "p", Location: "us-central1", Cluster: "c", Node: "node-2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Nodes) != 1 || len(out.NodePools) != 1 || out.NodePools[0].Name != "pool-a" {
+		t.Errorf("single node diagnosis = %+v, want node-2 and its node pool", out)
+	}
+
+	for _, args := range []*diagnoseNodesArgs{
+		{Cluster: "c"},
+		{Cluster: "c", Node: "node-1", NodePool: "pool-a"},
+		{Cluster: "c", Node: "node-9"},
+		{Cluster: "c", Node: "node-1", EventWindow: "a while"},
+		{Node: "node-1"},
+	} {
+		if _, _, err := h.diagnoseNodes(context.Background(), nil, args); err == nil {
+			t.Errorf("diagnoseNodes(%+v) succeeded, want error", args)
+		}
+	}
+}
+
+func TestRankNodeProblems(t *testing.T) {
+	problem := func(severity, summary string, nodes ...string) nodeProblem {
+		return nodeProblem{Severity: severity, Check: checkEvents, Summary: summary, Nodes: nodes}
+	}
+	got := rankNodeProblems([]nodeProblem{
+		problem(severityWarning, "Warning event Rebooted", "n1"),
+		problem(severityWarning, "Warning event Rebooted", "n1"),
+		problem(severityWarning, "Warning event OOM", "n2"),
+		problem(severityWarning, "Warning event OOM", "n1"),
+		problem(severityCritical, "Warning event Down", "n3"),
+	})
+	want := []nodeProblem{
+		problem(severityCritical, "Warning event Down", "n3"),
+		problem(severityWarning, "Warning event OOM", "n1", "n2"),
+		problem(severityWarning, "Warning event Rebooted", "n1"),
+	}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("rankNodeProblems() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestPodProblem(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name       string
+		pod        *corev1.Pod
+		wantReason string
+	}{
+		{name: "running", pod: testPod("p", "n", "1", nil)},
+		{name: "succeeded", pod: testPod("p", "n", "1", func(p *corev1.Pod) { p.Status.Phase = corev1.PodSucceeded })},
+		{name: "unknown", pod: testPod("p", "n", "1", func(p *corev1.Pod) { p.Status.Phase = corev1.PodUnknown }), wantReason: "Unknown"},
+		{name: "failed", pod: testPod("p", "n", "1", func(p *corev1.Pod) { p.Status.Phase = corev1.PodFailed }), wantReason: "Failed"},
+		{name: "image pull", pod: testPod("p", "n", "1", func(p *corev1.Pod) {
+			p.Status.Phase = corev1.PodPending
+			p.Status.InitContainerStatuses = []corev1.ContainerStatus{{State: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}}}
+		}), wantReason: "ImagePullBackOff"},
+		{name: "stuck pending", pod: testPod("p", "n", "1", func(p *corev1.Pod) { p.Status.Phase = corev1.PodPending }), wantReason: "stuck pending"},
+		{name: "just created", pod: testPod("p", "n", "1", func(p *corev1.Pod) {
+			p.Status.Phase = corev1.PodPending
+			p.CreationTimestamp = metav1.NewTime(now)
+		})},
+		{name: "old OOM kill", pod: testPod("p", "n", "1", func(p *corev1.Pod) {
+			p.Status.ContainerStatuses = []corev1.ContainerStatus{{LastTerminationState: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", FinishedAt: metav1.NewTime(now.Add(-2 * time.Hour))}}}}
+		})},
+		{name: "stuck terminating", pod: testPod("p", "n", "1", func(p *corev1.Pod) {
+			p.DeletionTimestamp = ptr.To(metav1.NewTime(now.Add(-10 * time.Minute)))
+			p.DeletionGracePeriodSeconds = ptr.To[int64](30)
+		}), wantReason: "stuck terminating"},
+		{name: "terminating", pod: testPod("p", "n", "1", func(p *corev1.Pod) {
+			p.DeletionTimestamp = ptr.To(metav1.NewTime(now.Add(-10 * time.Second)))
+			p.DeletionGracePeriodSeconds = ptr.To[int64](30)
+		})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := podProblem(tt.pod, now, time.Hour)
+			if ok != (tt.wantReason != "") || got.Reason != tt.wantReason {
+				t.Errorf("podProblem() = %q, %v, want %q", got.Reason, ok, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestParseGCEProviderID(t *testing.T) {
+	project, zone, name, ok := parseGCEProviderID("gce://p/us-central1-a/gke-c-pool-a-1234")
+	if !ok || project != "p" || zone != "us-central1-a" || name != "gke-c-pool-a-1234" {
+		t.Errorf("parseGCEProviderID() = %q, %q, %q, %v", project, zone, name, ok)
+	}
+	for _, id := range []string{"", "aws:///us-east-1a/i-123", "gce://p/zone"} {
+		if _, _, _, ok := parseGCEProviderID(id); ok {
+			t.Errorf("parseGCEProviderID(%q) succeeded, want failure", id)
+		}
+	}
+}
@@ -0,0 +1,291 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/logging"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	nodeLogSourceProxy        = "node_proxy"
+	nodeLogSourceCloudLogging = "cloud_logging"
+
+	defaultNodeLogService = "kubelet"
+	// maxNodeLogEntries is the most entries query_logs returns at once.
+	maxNodeLogEntries = 100
+	// nodeLogFormat renders the journal entries of k8s_node logs, whose
+	// jsonPayload holds the journald fields, and their text entries.
+	nodeLogFormat = `{{.timestamp}} {{with .jsonPayload}}{{with .SYSLOG_IDENTIFIER}}{{.}}: {{end}}{{with .MESSAGE}}{{.}}{{end}}{{end}}{{with .textPayload}}{{.}}{{end}}`
+)
+
+var (
+	nodeServiceRE = regexp.MustCompile(`^[A-Za-z0-9@._:-]+$`)
+
+	// nodeLogNames maps the journal units of GKE nodes to the logs Cloud
+	// Logging stores them in, when they differ.
+	nodeLogNames = map[string]string{
+		"containerd": "container-runtime",
+	}
+)
+
+type getNodeLogsArgs struct {
+	ProjectID  string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location   string   `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster    string   `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Node       string   `json:"node" jsonschema:"Name of the node."`
+	Services   []string `json:"services,omitempty" jsonschema:"Journal units to read, like 'kubelet', 'containerd' or 'kube-node-configuration'. Defaults to kubelet."`
+	Since      string   `json:"since,omitempty" jsonschema:"Only return logs newer than this duration, like '10m' or '2h'. Can't be combined with start_time."`
+	StartTime  string   `json:"start_time,omitempty" jsonschema:"Only return logs at or after this time, in RFC3339 format."`
+	EndTime    string   `json:"end_time,omitempty" jsonschema:"Only return logs at or before this time, in RFC3339 format."`
+	Pattern    string   `json:"pattern,omitempty" jsonschema:"Only return lines matching this regular expression."`
+	TailLines  int64    `json:"tail_lines,omitempty" jsonschema:"Number of lines to return from the end of the logs. Defaults to 500. At most 100 are returned from Cloud Logging."`
+	LimitBytes int64    `json:"limit_bytes,omitempty" jsonschema:"Maximum size of the logs in bytes. Defaults to 256 KiB, at most 4 MiB."`
+}
+
+type getNodeLogsOutput struct {
+	Node       string   `json:"node"`
+	Services   []string `json:"services"`
+	Source     string   `json:"source" jsonschema:"Where the logs came from: 'node_proxy' for the kubelet's node log query, or 'cloud_logging' when the proxy was unavailable."`
+	Logs       string   `json:"logs"`
+	Truncated  bool     `json:"truncated,omitempty" jsonschema:"Whether the logs were cut at limit_bytes, or more Cloud Logging entries matched than were returned."`
+	Filter     string   `json:"filter,omitempty" jsonschema:"The LQL filter run in Cloud Logging. query_logs accepts it too, to page through more entries."`
+	ProxyError string   `json:"proxy_error,omitempty" jsonschema:"Why the node log proxy couldn't be used."`
+}
+
+// installNodeLogs registers the tools reading node logs.
+func (h *handlers) installNodeLogs(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "get_node_logs",
+		Description: "Get the journal logs of services on a GKE node, like kubelet and containerd, without a debug pod or SSH. " +
+			"Reads them through the API server's node proxy with the kubelet's node log query, and falls back to Cloud Logging k8s_node logs when the node doesn't serve it. " +
+			"Prefer this to get_node_sos_report for quick node investigations.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getNodeLogs)
+}
+
+func (h *handlers) getNodeLogs(ctx context.Context, _ *mcp.CallToolRequest, args *getNodeLogsArgs) (*mcp.CallToolResult, *getNodeLogsOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if args.Node == "" {
+		return nil, nil, fmt.Errorf("node argument cannot be empty")
+	}
+	if !nodeNameRE.MatchString(args.Node) {
+		return nil, nil, fmt.Errorf("invalid node name: %s", args.Node)
+	}
+	if len(args.Services) == 0 {
+		args.Services = []string{defaultNodeLogService}
+	}
+	for _, svc := range args.Services {
+		if !nodeServiceRE.MatchString(svc) {
+			return nil, nil, fmt.Errorf("invalid service name: %q", svc)
+		}
+	}
+	if args.Pattern != "" {
+		if _, err := regexp.Compile(args.Pattern); err != nil {
+			return nil, nil, fmt.Errorf("invalid pattern: %w", err)
+		}
+	}
+	if args.TailLines <= 0 {
+		args.TailLines = defaultLogTailLines
+	}
+	switch {
+	case args.LimitBytes <= 0:
+		args.LimitBytes = defaultLogLimitBytes
+	case args.LimitBytes > maxLogLimitBytes:
+		args.LimitBytes = maxLogLimitBytes
+	}
+	start, end, err := nodeLogWindow(args, time.Now())
+	if err != nil {
+		return nil, nil, err
+	}
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &getNodeLogsOutput{Node: args.Node, Services: args.Services, Source: nodeLogSourceProxy}
+	logs, truncated, proxyErr := nodeLogsWithProxy(ctx, k, args, start, end)
+	if proxyErr == nil {
+		out.Logs, out.Truncated = logs, truncated
+	} else {
+		if ctx.Err() != nil {
+			return nil, nil, proxyErr
+		}
+		out.Source, out.ProxyError = nodeLogSourceCloudLogging, proxyErr.Error()
+		if err := h.nodeLogsWithCloudLogging(ctx, args, start, end, out); err != nil {
+			return nil, nil, fmt.Errorf("failed to get logs of node %s through the node proxy: %v, and from Cloud Logging: %w", args.Node, proxyErr, err)
+		}
+	}
+
+	summary := fmt.Sprintf("Got %d bytes of logs of %s on node %s", len(out.Logs), strings.Join(args.Services, ", "), args.Node)
+	if out.Source == nodeLogSourceCloudLogging {
+		summary += fmt.Sprintf(" from Cloud Logging, because the node log proxy is unavailable (%s)", out.ProxyError)
+	}
+	if out.Truncated {
+		summary += ", truncated"
+	}
+	if out.Filter != "" {
+		summary += fmt.Sprintf(" with the LQL filter %q", out.Filter)
+	}
+	summary += ". The logs follow."
+	return toolresult.NewText(summary, out.Logs), out, nil
+}
+
+// nodeLogWindow returns the times the logs must be newer and older than,
+// either of which may be zero.
+func nodeLogWindow(args *getNodeLogsArgs, now time.Time) (time.Time, time.Time, error) {
+	var start, end time.Time
+	if args.Since != "" && args.StartTime != "" {
+		return start, end, fmt.Errorf("since and start_time cannot be combined")
+	}
+	if args.Since != "" {
+		d, err := time.ParseDuration(args.Since)
+		if err != nil || d <= 0 {
+			return start, end, fmt.Errorf("invalid since %q, must be a positive duration like '10m' or '2h'", args.Since)
+		}
+		start = now.Add(-d)
+	}
+	if args.StartTime != "" {
+		t, err := time.Parse(time.RFC3339, args.StartTime)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid start_time %q, must be in RFC3339 format: %w", args.StartTime, err)
+		}
+		start = t
+	}
+	if args.EndTime != "" {
+		t, err := time.Parse(time.RFC3339, args.EndTime)
+		if err != nil {
+			return start, end, fmt.Errorf("invalid end_time %q, must be in RFC3339 format: %w", args.EndTime, err)
+		}
+		end = t
+	}
+	if !start.IsZero() && !end.IsZero() && end.Before(start) {
+		return start, end, fmt.Errorf("end_time must not be before the start of the window")
+	}
+	return start, end, nil
+}
+
+// nodeLogsWithProxy reads the logs through the kubelet's node log query and
+// whether they were cut at the byte limit.
+func nodeLogsWithProxy(ctx context.Context, k *kubeClients, args *getNodeLogsArgs, start, end time.Time) (string, bool, error) {
+	params := url.Values{
+		"query":     args.Services,
+		"tailLines": {strconv.FormatInt(args.TailLines, 10)},
+	}
+	if !start.IsZero() {
+		params.Set("sinceTime", start.UTC().Format(time.RFC3339))
+	}
+	if !end.IsZero() {
+		params.Set("untilTime", end.UTC().Format(time.RFC3339))
+	}
+	if args.Pattern != "" {
+		params.Set("pattern", args.Pattern)
+	}
+	stream, err := k.nodeLogs(ctx, args.Node, params)
+	if err != nil {
+		return "", false, err
+	}
+	defer stream.Close()
+	// Read one byte past the limit to tell whether the logs were cut.
+	b, err := io.ReadAll(io.LimitReader(stream, args.LimitBytes+1))
+	if err != nil {
+		return "", false, err
+	}
+	// Without the NodeLogQuery feature, the kubelet ignores the query and
+	// lists /var/log instead.
+	if head := bytes.ToLower(bytes.TrimSpace(b)); bytes.HasPrefix(head, []byte("<!doctype html")) || bytes.HasPrefix(head, []byte("<pre>")) {
+		return "", false, fmt.Errorf("node log query isn't enabled on node %s", args.Node)
+	}
+	if int64(len(b)) > args.LimitBytes {
+		return string(b[:args.LimitBytes]), true, nil
+	}
+	return string(b), false, nil
+}
+
+// nodeLogsWithCloudLogging reads the most recent logs from the k8s_node
+// logs in Cloud Logging, like query_logs with nodeLogFilter.
+func (h *handlers) nodeLogsWithCloudLogging(ctx context.Context, args *getNodeLogsArgs, start, end time.Time, out *getNodeLogsOutput) error {
+	req := &logging.LogQueryRequest{
+		Query:     nodeLogFilter(args.ProjectID, args.Location, args.Cluster, args.Node, args.Services, args.Pattern),
+		ProjectID: args.ProjectID,
+		TimeRange: logging.TimeRange{StartTime: start, EndTime: end},
+		Limit:     int(min(args.TailLines, maxNodeLogEntries)),
+		// Newest first, to get the end of the logs.
+		Order:  "desc",
+		Format: nodeLogFormat,
+	}
+	query := h.queryLogs
+	if query == nil {
+		query = func(ctx context.Context, req *logging.LogQueryRequest) (*logging.QueryLogsResult, error) {
+			return logging.QueryLogs(ctx, h.c, req)
+		}
+	}
+	res, err := query(ctx, req)
+	if err != nil {
+		return err
+	}
+	lines := slices.Clone(res.Lines)
+	slices.Reverse(lines)
+	out.Logs = strings.Join(lines, "\n")
+	out.Truncated = res.Truncated
+	if int64(len(out.Logs)) > args.LimitBytes {
+		out.Logs, out.Truncated = out.Logs[int64(len(out.Logs))-args.LimitBytes:], true
+	}
+	out.Filter = res.Filter
+	return nil
+}
+
+// nodeLogFilter returns the LQL filter of the logs of services on a node.
+func nodeLogFilter(projectID, location, cluster, node string, services []string, pattern string) string {
+	var logNames []string
+	for _, svc := range services {
+		name := strings.TrimSuffix(svc, ".service")
+		if n, ok := nodeLogNames[name]; ok {
+			name = n
+		}
+		logNames = append(logNames, strconv.Quote(fmt.Sprintf("projects/%s/logs/%s", projectID, name)))
+	}
+	filter := []string{
+		`resource.type="k8s_node"`,
+		"resource.labels.project_id=" + strconv.Quote(projectID),
+		"resource.labels.location=" + strconv.Quote(location),
+		"resource.labels.cluster_name=" + strconv.Quote(cluster),
+		"resource.labels.node_name=" + strconv.Quote(node),
+		"logName=(" + strings.Join(logNames, " OR ") + ")",
+	}
+	if pattern != "" {
+		filter = append(filter, fmt.Sprintf("(jsonPayload.MESSAGE=~%s OR textPayload=~%[1]s)", strconv.Quote(pattern)))
+	}
+	return strings.Join(filter, "\n")
+}
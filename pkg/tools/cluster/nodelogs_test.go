@@ -0,0 +1,160 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/logging"
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// testNodeLogsHandlers returns handlers whose node log proxy answers with
+// proxy and whose Cloud Logging queries return lines, newest first. The
+// returned function gives the last proxy parameters and log query.
+func testNodeLogsHandlers(proxy func() (string, error), lines []string) (*handlers, func() (url.Values, *logging.LogQueryRequest)) {
+	var params url.Values
+	var query *logging.LogQueryRequest
+	k := &kubeClients{
+		nodeLogsFn: func(_ context.Context, _ string, p url.Values) (io.ReadCloser, error) {
+			params = p
+			logs, err := proxy()
+			if err != nil {
+				return nil, err
+			}
+			return io.NopCloser(strings.NewReader(logs)), nil
+		},
+	}
+	h := &handlers{
+		c: &config.Config{},
+		kube: func(context.Context, string, string, string) (*kubeClients, error) {
+			return k, nil
+		},
+		queryLogs: func(_ context.Context, req *logging.LogQueryRequest) (*logging.QueryLogsResult, error) {
+			query = req
+			return &logging.QueryLogsResult{Filter: req.Query, Lines: lines, Truncated: true}, nil
+		},
+	}
+	return h, func() (url.Values, *logging.LogQueryRequest) { return params, query }
+}
+
+func TestGetNodeLogsWithProxy(t *testing.T) {
+	h, last := testNodeLogsHandlers(func() (string, error) { return "kubelet started\n", nil }, nil)
+	res, out, err := h.getNodeLogs(context.Background(), nil, &getNodeLogsArgs{
+		Cluster:   "c",
+		Node:      "node-1",
+		Services:  []string{"kubelet", "containerd"},
+		StartTime: "2025-01-01T10:00:00Z",
+		EndTime:   "2025-01-01T11:00:00Z",
+		Pattern:   "error|fail",
+		TailLines: 50,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Source != nodeLogSourceProxy || out.Logs != "kubelet started\n" || out.Filter != "" {
+		t.Errorf("getNodeLogs() = %+v, want the proxy's logs", out)
+	}
+	if got := res.Content[len(res.Content)-1].(*mcp.TextContent).Text; got != out.Logs {
+		t.Errorf("getNodeLogs() text content = %q, want %q", got, out.Logs)
+	}
+	params, query := last()
+	want := url.Values{
+		"query":     {"kubelet", "containerd"},
+		"sinceTime": {"2025-01-01T10:00:00Z"},
+		"untilTime": {"2025-01-01T11:00:00Z"},
+		"pattern":   {"error|fail"},
+		"tailLines": {"50"},
+	}
+	if diff := cmp.Diff(want, params); diff != "" {
+		t.Errorf("proxy parameters mismatch (-want +got):\n%s", diff)
+	}
+	if query != nil {
+		t.Errorf("Cloud Logging was queried although the proxy worked")
+	}
+}
+
+func TestGetNodeLogsFallback(t *testing.T) {
+	tests := []struct {
+		name  string
+		proxy func() (string, error)
+	}{
+		{name: "proxy error", proxy: func() (string, error) { return "", errors.New("the server could not find the requested resource") }},
+		{name: "log directory listing", proxy: func() (string, error) { return "<!doctype html>\n<pre>\n<a href=\"syslog\">syslog</a>\n</pre>\n", nil }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, last := testNodeLogsHandlers(tt.proxy, []string{"second", "first"})
+			_, out, err := h.getNodeLogs(context.Background(), nil, &getNodeLogsArgs{
+				ProjectID: "p",
+				Location:  "us-central1",
+				Cluster:   "c",
+				Node:      "node-1",
+				Services:  []string{"kubelet", "containerd.service"},
+				Since:     "1h",
+				Pattern:   `pod "web"`,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Source != nodeLogSourceCloudLogging || out.ProxyError == "" || out.Logs != "first\nsecond" || !out.Truncated {
+				t.Errorf("getNodeLogs() = %+v, want the oldest-first Cloud Logging lines", out)
+			}
+			wantFilter := strings.Join([]string{
+				`resource.type="k8s_node"`,
+				`resource.labels.project_id="p"`,
+				`resource.labels.location="us-central1"`,
+				`resource.labels.cluster_name="c"`,
+				`resource.labels.node_name="node-1"`,
+				`logName=("projects/p/logs/kubelet" OR "projects/p/logs/container-runtime")`,
+				`(jsonPayload.MESSAGE=~"pod \"web\"" OR textPayload=~"pod \"web\"")`,
+			}, "\n")
+			if diff := cmp.Diff(wantFilter, out.Filter); diff != "" {
+				t.Errorf("filter mismatch (-want +got):\n%s", diff)
+			}
+			_, query := last()
+			if query.Order != "desc" || query.Limit != maxNodeLogEntries || time.Since(query.TimeRange.StartTime) < time.Hour {
+				t.Errorf("query = %+v, want the newest %d entries of the last hour", query, maxNodeLogEntries)
+			}
+		})
+	}
+}
+
+func TestGetNodeLogsInvalidArgs(t *testing.T) {
+	h, _ := testNodeLogsHandlers(func() (string, error) { return "", nil }, nil)
+	for _, args := range []*getNodeLogsArgs{
+		{Cluster: "c"},
+		{Cluster: "c", Node: "node-1/../../pods"},
+		{Cluster: "c", Node: "node-1", Services: []string{"kubelet; rm"}},
+		{Cluster: "c", Node: "node-1", Pattern: "("},
+		{Cluster: "c", Node: "node-1", Since: "1h", StartTime: "2025-01-01T10:00:00Z"},
+		{Cluster: "c", Node: "node-1", Since: "-1h"},
+		{Cluster: "c", Node: "node-1", StartTime: "yesterday"},
+		{Cluster: "c", Node: "node-1", StartTime: "2025-01-01T10:00:00Z", EndTime: "2025-01-01T09:00:00Z"},
+		{Node: "node-1"},
+	} {
+		if _, _, err := h.getNodeLogs(context.Background(), nil, args); err == nil {
+			t.Errorf("getNodeLogs(%+v) succeeded, want error", args)
+		}
+	}
+}
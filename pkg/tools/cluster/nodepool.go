@@ -0,0 +1,460 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type listNodePoolsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	View      string `json:"view,omitempty" jsonschema:"How much of each node pool to return: 'summary' (default) or 'full' for the complete node pool resource."`
+	Fields    string `json:"fields,omitempty" jsonschema:"Only return these parts of each node pool resource, as a comma-separated protobuf field mask like 'config.machineType,autoscaling' or a JSONPath expression like '$.config.labels'."`
+}
+
+type getNodePoolArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Name      string `json:"name" jsonschema:"Node pool name."`
+	View      string `json:"view,omitempty" jsonschema:"How much of the node pool to return: 'full' (default) or 'summary'."`
+	Fields    string `json:"fields,omitempty" jsonschema:"Only return these parts of the node pool resource, as a comma-separated protobuf field mask or a JSONPath expression."`
+}
+
+type createNodePoolArgs struct {
+	ProjectID string               `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string               `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string               `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	NodePool  containerpb.NodePool `json:"node_pool" jsonschema:"GKE node pool configuration."`
+}
+
+type updateNodePoolArgs struct {
+	ProjectID       string                                `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location        string                                `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster         string                                `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Name            string                                `json:"name" jsonschema:"Node pool name."`
+	MachineType     string                                `json:"machine_type,omitempty" jsonschema:"New machine type, e.g. e2-standard-4. Nodes are recreated to apply it."`
+	Labels          map[string]string                     `json:"labels,omitempty" jsonschema:"Kubernetes labels for the nodes, replacing all existing ones. Pass an empty object to remove them all."`
+	Taints          []nodeTaint                           `json:"taints,omitempty" jsonschema:"Kubernetes taints for the nodes, replacing all existing ones. Pass an empty list to remove them all."`
+	UpgradeSettings *containerpb.NodePool_UpgradeSettings `json:"upgrade_settings,omitempty" jsonschema:"Surge or blue-green upgrade settings."`
+	Autoscaling     *containerpb.NodePoolAutoscaling      `json:"autoscaling,omitempty" jsonschema:"Cluster autoscaler settings. Can't be combined with other changes in one call."`
+}
+
+type deleteNodePoolArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Name      string `json:"name" jsonschema:"Node pool name. Make sure the user provides or confirms it; its nodes and their workloads are removed."`
+}
+
+type nodeTaint struct {
+	Key    string `json:"key"`
+	Value  string `json:"value,omitempty"`
+	Effect string `json:"effect" jsonschema:"NO_SCHEDULE, PREFER_NO_SCHEDULE or NO_EXECUTE."`
+}
+
+type nodePoolAutoscaling struct {
+	Enabled           bool   `json:"enabled"`
+	MinNodeCount      int32  `json:"min_node_count,omitempty" jsonschema:"Minimum number of nodes per zone."`
+	MaxNodeCount      int32  `json:"max_node_count,omitempty" jsonschema:"Maximum number of nodes per zone."`
+	TotalMinNodeCount int32  `json:"total_min_node_count,omitempty"`
+	TotalMaxNodeCount int32  `json:"total_max_node_count,omitempty"`
+	LocationPolicy    string `json:"location_policy,omitempty"`
+}
+
+// nodePoolInfo describes a node pool. Resource and Matches are only set when
+// the full view or fields are requested.
+type nodePoolInfo struct {
+	Name             string               `json:"name"`
+	Status           string               `json:"status" jsonschema:"Node pool status, e.g. RUNNING or RECONCILING."`
+	StatusMessage    string               `json:"status_message,omitempty"`
+	Version          string               `json:"version,omitempty"`
+	MachineType      string               `json:"machine_type,omitempty"`
+	ImageType        string               `json:"image_type,omitempty"`
+	DiskSizeGb       int32                `json:"disk_size_gb,omitempty"`
+	Spot             bool                 `json:"spot,omitempty"`
+	InitialNodeCount int32                `json:"initial_node_count,omitempty" jsonschema:"Number of nodes per zone the node pool was created with."`
+	Locations        []string             `json:"locations,omitempty"`
+	Autoscaling      *nodePoolAutoscaling `json:"autoscaling,omitempty"`
+	AutoUpgrade      bool                 `json:"auto_upgrade"`
+	AutoRepair       bool                 `json:"auto_repair"`
+	Labels           map[string]string    `json:"labels,omitempty"`
+	Taints           []nodeTaint          `json:"taints,omitempty"`
+
+	Resource map[string]any `json:"resource,omitempty" jsonschema:"The node pool resource from the GKE API: all of it in the full view, or the fields selected by a field mask."`
+	Matches  []any          `json:"matches,omitempty" jsonschema:"Values selected by a JSONPath fields expression."`
+}
+
+type listNodePoolsOutput struct {
+	ProjectID string         `json:"project_id"`
+	Location  string         `json:"location"`
+	Cluster   string         `json:"cluster"`
+	NodePools []nodePoolInfo `json:"node_pools,omitempty"`
+}
+
+type getNodePoolOutput struct {
+	NodePool nodePoolInfo `json:"node_pool"`
+}
+
+func newNodePoolInfo(np *containerpb.NodePool) nodePoolInfo {
+	cfg := np.GetConfig()
+	info := nodePoolInfo{
+		Name:             np.GetName(),
+		Status:           np.GetStatus().String(),
+		StatusMessage:    np.GetStatusMessage(),
+		Version:          np.GetVersion(),
+		MachineType:      cfg.GetMachineType(),
+		ImageType:        cfg.GetImageType(),
+		DiskSizeGb:       cfg.GetDiskSizeGb(),
+		Spot:             cfg.GetSpot(),
+		InitialNodeCount: np.GetInitialNodeCount(),
+		Locations:        np.GetLocations(),
+		AutoUpgrade:      np.GetManagement().GetAutoUpgrade(),
+		AutoRepair:       np.GetManagement().GetAutoRepair(),
+		Labels:           cfg.GetLabels(),
+	}
+	if a := np.GetAutoscaling(); a != nil {
+		info.Autoscaling = &nodePoolAutoscaling{
+			Enabled:           a.GetEnabled(),
+			MinNodeCount:      a.GetMinNodeCount(),
+			MaxNodeCount:      a.GetMaxNodeCount(),
+			TotalMinNodeCount: a.GetTotalMinNodeCount(),
+			TotalMaxNodeCount: a.GetTotalMaxNodeCount(),
+		}
+		if a.GetLocationPolicy() != containerpb.NodePoolAutoscaling_LOCATION_POLICY_UNSPECIFIED {
+			info.Autoscaling.LocationPolicy = a.GetLocationPolicy().String()
+		}
+	}
+	for _, t := range cfg.GetTaints() {
+		info.Taints = append(info.Taints, nodeTaint{Key: t.GetKey(), Value: t.GetValue(), Effect: t.GetEffect().String()})
+	}
+	return info
+}
+
+func newNodePoolInfoForView(np *containerpb.NodePool, view string, proj *projection) (nodePoolInfo, error) {
+	info := newNodePoolInfo(np)
+	var err error
+	info.Resource, info.Matches, err = resourceForView(np, view, proj)
+	return info, err
+}
+
+// nodePoolTable renders node pools as a text table.
+func nodePoolTable(pools []nodePoolInfo) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tMACHINE_TYPE\tVERSION\tSTATUS\tAUTOSCALING")
+	for _, np := range pools {
+		autoscaling := "-"
+		if a := np.Autoscaling; a != nil && a.Enabled {
+			if a.TotalMaxNodeCount > 0 {
+				autoscaling = fmt.Sprintf("%d-%d total", a.TotalMinNodeCount, a.TotalMaxNodeCount)
+			} else {
+				autoscaling = fmt.Sprintf("%d-%d per zone", a.MinNodeCount, a.MaxNodeCount)
+			}
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", np.Name, np.MachineType, np.Version, np.Status, autoscaling)
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// installNodePools registers the node pool tools.
+func (h *handlers) installNodePools(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_node_pools",
+		Description: "List the node pools of a GKE cluster. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listNodePools)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_node_pool",
+		Description: "Get / describe a GKE node pool. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getNodePool)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "create_node_pool",
+		Description: "Create a node pool in a GKE cluster. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(false),
+		},
+	}, h.createNodePool)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "update_node_pool",
+		Description: "Update the machine type, autoscaling, node labels, taints or upgrade settings of a GKE node pool. Changing the machine type recreates the nodes. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.updateNodePool)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "delete_node_pool",
+		Description: "Delete a node pool from a GKE cluster, removing its nodes and the workloads running on them. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.deleteNodePool)
+}
+
+// clusterName returns the resource name of a cluster, filling in the default
+// project and location.
+func (h *handlers) clusterName(projectID, location, cluster string) (string, error) {
+	if projectID == "" {
+		projectID = h.c.DefaultProjectID()
+	}
+	if location == "" {
+		location = h.c.DefaultLocation()
+	}
+	if cluster == "" {
+		return "", fmt.Errorf("cluster argument cannot be empty")
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/clusters/%s", projectID, location, cluster), nil
+}
+
+func (h *handlers) listNodePools(ctx context.Context, _ *mcp.CallToolRequest, args *listNodePoolsArgs) (*mcp.CallToolResult, *listNodePoolsOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	parent, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	view, err := parseView(args.View, viewSummary)
+	if err != nil {
+		return nil, nil, err
+	}
+	proj, err := newProjection((&containerpb.NodePool{}).ProtoReflect().Descriptor(), args.Fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := h.cmClient.ListNodePools(ctx, &containerpb.ListNodePoolsRequest{Parent: parent})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &listNodePoolsOutput{
+		ProjectID: args.ProjectID,
+		Location:  args.Location,
+		Cluster:   args.Cluster,
+	}
+	for _, np := range resp.GetNodePools() {
+		info, err := newNodePoolInfoForView(np, view, proj)
+		if err != nil {
+			return nil, nil, err
+		}
+		out.NodePools = append(out.NodePools, info)
+	}
+	summary := fmt.Sprintf("Found %d node pools in cluster %s.", len(out.NodePools), args.Cluster)
+	if len(out.NodePools) > 0 {
+		summary += "\n\n" + nodePoolTable(out.NodePools)
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func (h *handlers) getNodePool(ctx context.Context, _ *mcp.CallToolRequest, args *getNodePoolArgs) (*mcp.CallToolResult, *getNodePoolOutput, error) {
+	parent, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	view, err := parseView(args.View, viewFull)
+	if err != nil {
+		return nil, nil, err
+	}
+	proj, err := newProjection((&containerpb.NodePool{}).ProtoReflect().Descriptor(), args.Fields)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp, err := h.cmClient.GetNodePool(ctx, &containerpb.GetNodePoolRequest{Name: parent + "/nodePools/" + args.Name})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info, err := newNodePoolInfoForView(resp, view, proj)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &getNodePoolOutput{NodePool: info}
+	summary := fmt.Sprintf("Node pool %s of cluster %s is %s, version %s, machine type %s.", info.Name, args.Cluster, info.Status, info.Version, info.MachineType)
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func (h *handlers) createNodePool(ctx context.Context, _ *mcp.CallToolRequest, args *createNodePoolArgs) (*mcp.CallToolResult, *operationOutput, error) {
+	parent, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.NodePool.GetName() == "" {
+		return nil, nil, fmt.Errorf("node_pool.name cannot be empty")
+	}
+
+	resp, err := h.cmClient.CreateNodePool(ctx, &containerpb.CreateNodePoolRequest{
+		Parent:   parent,
+		NodePool: &args.NodePool,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &operationOutput{Operation: newOperationSummary(resp)}
+	summary := fmt.Sprintf("Started creating node pool %s in cluster %s, operation %s is %s. Use wait_operation to wait for it.", args.NodePool.GetName(), args.Cluster, out.Operation.Name, out.Operation.Status)
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func (h *handlers) updateNodePool(ctx context.Context, _ *mcp.CallToolRequest, args *updateNodePoolArgs) (*mcp.CallToolResult, *operationOutput, error) {
+	parent, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+	name := parent + "/nodePools/" + args.Name
+
+	var op *containerpb.Operation
+	if args.Autoscaling != nil {
+		if hasNodePoolUpdate(args) {
+			return nil, nil, fmt.Errorf("autoscaling can't be changed together with other settings, update it in a separate call")
+		}
+		op, err = h.cmClient.SetNodePoolAutoscaling(ctx, &containerpb.SetNodePoolAutoscalingRequest{
+			Name:        name,
+			Autoscaling: args.Autoscaling,
+		})
+	} else {
+		var pool *containerpb.NodePool
+		pool, err = h.cmClient.GetNodePool(ctx, &containerpb.GetNodePoolRequest{Name: name})
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get node pool: %w", err)
+		}
+		var req *containerpb.UpdateNodePoolRequest
+		req, err = newUpdateNodePoolRequest(name, pool, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		op, err = h.cmClient.UpdateNodePool(ctx, req)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &operationOutput{Operation: newOperationSummary(op)}
+	summary := fmt.Sprintf("Started updating node pool %s in cluster %s, operation %s is %s. Use wait_operation to wait for it.", args.Name, args.Cluster, out.Operation.Name, out.Operation.Status)
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// hasNodePoolUpdate reports whether args change anything UpdateNodePool covers.
+func hasNodePoolUpdate(args *updateNodePoolArgs) bool {
+	return args.MachineType != "" || args.Labels != nil || args.Taints != nil || args.UpgradeSettings != nil
+}
+
+// newUpdateNodePoolRequest builds the UpdateNodePool request for the changes
+// in args. Labels and taints replace the existing ones when they are set,
+// even if empty.
+func newUpdateNodePoolRequest(name string, pool *containerpb.NodePool, args *updateNodePoolArgs) (*containerpb.UpdateNodePoolRequest, error) {
+	if !hasNodePoolUpdate(args) {
+		return nil, fmt.Errorf("no changes requested: set machine_type, labels, taints, upgrade_settings or autoscaling")
+	}
+	req := &containerpb.UpdateNodePoolRequest{
+		Name: name,
+		// The API requires both. Keep the current ones; "-" would upgrade the
+		// nodes to the control plane's version.
+		NodeVersion:     pool.GetVersion(),
+		ImageType:       pool.GetConfig().GetImageType(),
+		MachineType:     args.MachineType,
+		UpgradeSettings: args.UpgradeSettings,
+	}
+	if args.Labels != nil {
+		req.Labels = &containerpb.NodeLabels{Labels: args.Labels}
+	}
+	if args.Taints != nil {
+		req.Taints = &containerpb.NodeTaints{Taints: []*containerpb.NodeTaint{}}
+		for _, t := range args.Taints {
+			effect, ok := containerpb.NodeTaint_Effect_value[strings.ToUpper(t.Effect)]
+			if !ok || effect == int32(containerpb.NodeTaint_EFFECT_UNSPECIFIED) {
+				return nil, fmt.Errorf("invalid effect %q for taint %q, must be one of %s", t.Effect, t.Key, strings.Join(taintEffects(), ", "))
+			}
+			if t.Key == "" {
+				return nil, fmt.Errorf("taint key cannot be empty")
+			}
+			req.Taints.Taints = append(req.Taints.Taints, &containerpb.NodeTaint{
+				Key:    t.Key,
+				Value:  t.Value,
+				Effect: containerpb.NodeTaint_Effect(effect),
+			})
+		}
+	}
+	return req, nil
+}
+
+func taintEffects() []string {
+	var effects []string
+	for name, v := range containerpb.NodeTaint_Effect_value {
+		if v != int32(containerpb.NodeTaint_EFFECT_UNSPECIFIED) {
+			effects = append(effects, name)
+		}
+	}
+	sort.Strings(effects)
+	return effects
+}
+
+func (h *handlers) deleteNodePool(ctx context.Context, _ *mcp.CallToolRequest, args *deleteNodePoolArgs) (*mcp.CallToolResult, *operationOutput, error) {
+	parent, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Name == "" {
+		return nil, nil, fmt.Errorf("name argument cannot be empty")
+	}
+
+	resp, err := h.cmClient.DeleteNodePool(ctx, &containerpb.DeleteNodePoolRequest{Name: parent + "/nodePools/" + args.Name})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &operationOutput{Operation: newOperationSummary(resp)}
+	summary := fmt.Sprintf("Started deleting node pool %s from cluster %s, operation %s is %s. Use wait_operation to wait for it.", args.Name, args.Cluster, out.Operation.Name, out.Operation.Status)
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
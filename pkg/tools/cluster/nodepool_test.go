@@ -0,0 +1,113 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestNewNodePoolInfo(t *testing.T) {
+	np := &containerpb.NodePool{
+		Name:    "default-pool",
+		Status:  containerpb.NodePool_RUNNING,
+		Version: "1.33.5-gke.1000",
+		Config: &containerpb.NodeConfig{
+			MachineType: "e2-standard-4",
+			Labels:      map[string]string{"team": "a"},
+			Taints:      []*containerpb.NodeTaint{{Key: "gpu", Value: "true", Effect: containerpb.NodeTaint_NO_SCHEDULE}},
+		},
+		Autoscaling: &containerpb.NodePoolAutoscaling{Enabled: true, MinNodeCount: 1, MaxNodeCount: 5},
+		Management:  &containerpb.NodeManagement{AutoUpgrade: true, AutoRepair: true},
+	}
+	want := nodePoolInfo{
+		Name:        "default-pool",
+		Status:      "RUNNING",
+		Version:     "1.33.5-gke.1000",
+		MachineType: "e2-standard-4",
+		Autoscaling: &nodePoolAutoscaling{Enabled: true, MinNodeCount: 1, MaxNodeCount: 5},
+		AutoUpgrade: true,
+		AutoRepair:  true,
+		Labels:      map[string]string{"team": "a"},
+		Taints:      []nodeTaint{{Key: "gpu", Value: "true", Effect: "NO_SCHEDULE"}},
+	}
+	if diff := cmp.Diff(want, newNodePoolInfo(np)); diff != "" {
+		t.Errorf("newNodePoolInfo() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestNewUpdateNodePoolRequest(t *testing.T) {
+	const name = "projects/p/locations/l/clusters/c/nodePools/np"
+	pool := &containerpb.NodePool{
+		Name:    "np",
+		Version: "1.33.5-gke.1000",
+		Config:  &containerpb.NodeConfig{ImageType: "COS_CONTAINERD"},
+	}
+	tests := []struct {
+		name    string
+		args    updateNodePoolArgs
+		want    *containerpb.UpdateNodePoolRequest
+		wantErr bool
+	}{
+		{
+			name: "machine type",
+			args: updateNodePoolArgs{MachineType: "e2-standard-8"},
+			want: &containerpb.UpdateNodePoolRequest{Name: name, NodeVersion: "1.33.5-gke.1000", ImageType: "COS_CONTAINERD", MachineType: "e2-standard-8"},
+		},
+		{
+			name: "labels and taints",
+			args: updateNodePoolArgs{
+				Labels: map[string]string{"team": "b"},
+				Taints: []nodeTaint{{Key: "dedicated", Value: "batch", Effect: "no_execute"}},
+			},
+			want: &containerpb.UpdateNodePoolRequest{
+				Name:        name,
+				NodeVersion: "1.33.5-gke.1000",
+				ImageType:   "COS_CONTAINERD",
+				Labels:      &containerpb.NodeLabels{Labels: map[string]string{"team": "b"}},
+				Taints:      &containerpb.NodeTaints{Taints: []*containerpb.NodeTaint{{Key: "dedicated", Value: "batch", Effect: containerpb.NodeTaint_NO_EXECUTE}}},
+			},
+		},
+		{
+			name: "clear taints",
+			args: updateNodePoolArgs{Taints: []nodeTaint{}},
+			want: &containerpb.UpdateNodePoolRequest{Name: name, NodeVersion: "1.33.5-gke.1000", ImageType: "COS_CONTAINERD", Taints: &containerpb.NodeTaints{}},
+		},
+		{
+			name:    "invalid effect",
+			args:    updateNodePoolArgs{Taints: []nodeTaint{{Key: "k", Effect: "NEVER"}}},
+			wantErr: true,
+		},
+		{
+			name:    "no changes",
+			args:    updateNodePoolArgs{},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newUpdateNodePoolRequest(name, pool, &tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newUpdateNodePoolRequest() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("newUpdateNodePoolRequest() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,359 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+const (
+	defaultWaitTimeout      = 30 * time.Minute
+	defaultWaitPollInterval = 10 * time.Second
+)
+
+type listOperationsArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE location. Leave this empty to list operations in all locations."`
+	Cluster   string `json:"cluster,omitempty" jsonschema:"Only list operations on this cluster or its node pools."`
+	Status    string `json:"status,omitempty" jsonschema:"Only list operations with this status: PENDING, RUNNING, DONE or ABORTING."`
+}
+
+type operationArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE location of the operation. Leave this empty if the user doesn't provide it."`
+	Operation string `json:"operation" jsonschema:"Operation ID, e.g. operation-1234567890-abcdef, or its full resource name."`
+}
+
+type waitOperationArgs struct {
+	ProjectID           string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location            string `json:"location,omitempty" jsonschema:"GKE location of the operation. Leave this empty if the user doesn't provide it."`
+	Operation           string `json:"operation" jsonschema:"Operation ID, e.g. operation-1234567890-abcdef, or its full resource name."`
+	TimeoutSeconds      int    `json:"timeout_seconds,omitempty" jsonschema:"How long to wait before returning the still running operation. Defaults to 1800 (30 minutes)."`
+	PollIntervalSeconds int    `json:"poll_interval_seconds,omitempty" jsonschema:"How often to check the operation. Defaults to 10."`
+}
+
+type listOperationsOutput struct {
+	ProjectID    string             `json:"project_id"`
+	Location     string             `json:"location"`
+	Operations   []operationSummary `json:"operations,omitempty"`
+	MissingZones []string           `json:"missing_zones,omitempty" jsonschema:"Zones that couldn't be reached, so their operations may be missing."`
+}
+
+type waitOperationOutput struct {
+	Operation operationSummary `json:"operation"`
+	Done      bool             `json:"done"`
+	TimedOut  bool             `json:"timed_out" jsonschema:"Whether the wait timed out before the operation finished."`
+}
+
+// installOperations registers the operation tools.
+func (h *handlers) installOperations(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_operations",
+		Description: "List GKE operations such as cluster and node pool creation, updates and upgrades. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listOperations)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_operation",
+		Description: "Get the status and progress of a GKE operation. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getOperation)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "wait_operation",
+		Description: "Wait for a GKE operation to finish, reporting its progress. Use this instead of polling get_operation or gcloud after starting a long-running change such as create_cluster.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.waitOperation)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "cancel_operation",
+		Description: "Cancel a running GKE operation. The affected cluster or node pool may be left partially changed. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.cancelOperation)
+}
+
+// operationName returns the resource name of an operation, accepting a full
+// name or an ID.
+func (h *handlers) operationName(projectID, location, operation string) (string, error) {
+	if operation == "" {
+		return "", fmt.Errorf("operation argument cannot be empty")
+	}
+	if strings.HasPrefix(operation, "projects/") {
+		return operation, nil
+	}
+	if projectID == "" {
+		projectID = h.c.DefaultProjectID()
+	}
+	if location == "" {
+		location = h.c.DefaultLocation()
+	}
+	return fmt.Sprintf("projects/%s/locations/%s/operations/%s", projectID, location, operation), nil
+}
+
+func (h *handlers) listOperations(ctx context.Context, _ *mcp.CallToolRequest, args *listOperationsArgs) (*mcp.CallToolResult, *listOperationsOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = "-"
+	}
+
+	resp, err := h.cmClient.ListOperations(ctx, &containerpb.ListOperationsRequest{
+		Parent: fmt.Sprintf("projects/%s/locations/%s", args.ProjectID, args.Location),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &listOperationsOutput{
+		ProjectID:    args.ProjectID,
+		Location:     args.Location,
+		MissingZones: resp.GetMissingZones(),
+	}
+	for _, op := range resp.GetOperations() {
+		if args.Status != "" && !strings.EqualFold(op.GetStatus().String(), args.Status) {
+			continue
+		}
+		if args.Cluster != "" && !targetsCluster(op.GetTargetLink(), args.Cluster) {
+			continue
+		}
+		out.Operations = append(out.Operations, newOperationSummary(op))
+	}
+
+	summary := fmt.Sprintf("Found %d operations in project %s.", len(out.Operations), args.ProjectID)
+	for _, op := range out.Operations {
+		summary += "\n" + op.String()
+	}
+	if len(out.MissingZones) > 0 {
+		summary += fmt.Sprintf("\nWarning: operations in unreachable zones %s may be missing.", strings.Join(out.MissingZones, ", "))
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// targetsCluster reports whether an operation's target link is the named
+// cluster or one of its node pools.
+func targetsCluster(targetLink, cluster string) bool {
+	return strings.HasSuffix(targetLink, "/clusters/"+cluster) || strings.Contains(targetLink, "/clusters/"+cluster+"/")
+}
+
+func (h *handlers) getOperation(ctx context.Context, _ *mcp.CallToolRequest, args *operationArgs) (*mcp.CallToolResult, *operationOutput, error) {
+	name, err := h.operationName(args.ProjectID, args.Location, args.Operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	op, err := h.cmClient.GetOperation(ctx, &containerpb.GetOperationRequest{Name: name})
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &operationOutput{Operation: newOperationSummary(op)}
+	res, err := toolresult.New("Operation "+out.Operation.String(), out)
+	return res, out, err
+}
+
+func (h *handlers) cancelOperation(ctx context.Context, _ *mcp.CallToolRequest, args *operationArgs) (*mcp.CallToolResult, *operationOutput, error) {
+	name, err := h.operationName(args.ProjectID, args.Location, args.Operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := h.cmClient.CancelOperation(ctx, &containerpb.CancelOperationRequest{Name: name}); err != nil {
+		return nil, nil, err
+	}
+	op, err := h.cmClient.GetOperation(ctx, &containerpb.GetOperationRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("cancelled operation %s but failed to get its status: %w", name, err)
+	}
+	out := &operationOutput{Operation: newOperationSummary(op)}
+	res, err := toolresult.New("Requested cancellation of operation "+out.Operation.String(), out)
+	return res, out, err
+}
+
+func (h *handlers) waitOperation(ctx context.Context, req *mcp.CallToolRequest, args *waitOperationArgs) (*mcp.CallToolResult, *waitOperationOutput, error) {
+	name, err := h.operationName(args.ProjectID, args.Location, args.Operation)
+	if err != nil {
+		return nil, nil, err
+	}
+	timeout := defaultWaitTimeout
+	if args.TimeoutSeconds > 0 {
+		timeout = time.Duration(args.TimeoutSeconds) * time.Second
+	}
+	interval := defaultWaitPollInterval
+	if args.PollIntervalSeconds > 0 {
+		interval = time.Duration(args.PollIntervalSeconds) * time.Second
+	}
+
+	get := func(ctx context.Context) (*containerpb.Operation, error) {
+		return h.cmClient.GetOperation(ctx, &containerpb.GetOperationRequest{Name: name})
+	}
+	op, timedOut, err := pollOperation(ctx, get, timeout, interval, progressNotifier(req))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &waitOperationOutput{
+		Operation: newOperationSummary(op),
+		Done:      op.GetStatus() == containerpb.Operation_DONE,
+		TimedOut:  timedOut,
+	}
+	summary := "Operation " + out.Operation.String()
+	if timedOut {
+		summary = fmt.Sprintf("Operation still not done after %s: %s", timeout, out.Operation.String())
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// progressFunc reports the progress of an operation as a percentage.
+type progressFunc func(ctx context.Context, percent float64, message string)
+
+// progressNotifier returns a progressFunc sending MCP progress notifications
+// for req, or nil if the client didn't ask for them.
+func progressNotifier(req *mcp.CallToolRequest) progressFunc {
+	if req == nil || req.Session == nil || req.Params == nil {
+		return nil
+	}
+	token := req.Params.GetProgressToken()
+	if token == nil {
+		return nil
+	}
+	return func(ctx context.Context, percent float64, message string) {
+		err := req.Session.NotifyProgress(ctx, &mcp.ProgressNotificationParams{
+			ProgressToken: token,
+			Progress:      percent,
+			Total:         100,
+			Message:       message,
+		})
+		if err != nil {
+			log.Printf("Failed to send progress notification: %v", err)
+		}
+	}
+}
+
+// pollOperation calls get every interval until the operation is done, the
+// timeout passes or ctx is cancelled. It reports progress whenever it
+// increases and returns the last state of the operation and whether it timed
+// out.
+func pollOperation(ctx context.Context, get func(context.Context) (*containerpb.Operation, error), timeout, interval time.Duration, progress progressFunc) (*containerpb.Operation, bool, error) {
+	deadline := time.NewTimer(timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	var lastPercent float64 = -1
+	for {
+		op, err := get(ctx)
+		if err != nil {
+			return nil, false, err
+		}
+		// MCP requires progress to increase with every notification, so
+		// changes of the message alone wait for the next increase, and a
+		// stage that restarts doesn't send progress backwards.
+		percent, message := operationProgress(op)
+		if percent > lastPercent {
+			if progress != nil {
+				progress(ctx, percent, message)
+			}
+			lastPercent = percent
+		}
+
+		if op.GetStatus() == containerpb.Operation_DONE {
+			return op, false, nil
+		}
+		select {
+		case <-ctx.Done():
+			return nil, false, ctx.Err()
+		case <-deadline.C:
+			return op, true, nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// operationProgress estimates how far an operation is, in percent, from its
+// progress metrics or else its stages, and describes its current state.
+func operationProgress(op *containerpb.Operation) (float64, string) {
+	if op.GetStatus() == containerpb.Operation_DONE {
+		return 100, "DONE"
+	}
+	p := op.GetProgress()
+	parts := []string{op.GetStatus().String()}
+	percent := 0.0
+
+	var doneStages int
+	var current string
+	for _, stage := range p.GetStages() {
+		switch stage.GetStatus() {
+		case containerpb.Operation_DONE:
+			doneStages++
+		case containerpb.Operation_RUNNING:
+			if current == "" {
+				current = stage.GetName()
+			}
+		}
+	}
+	if current != "" {
+		parts = append(parts, "stage "+current)
+	}
+	if n := len(p.GetStages()); n > 0 {
+		percent = 100 * float64(doneStages) / float64(n)
+		parts = append(parts, fmt.Sprintf("%d/%d stages done", doneStages, n))
+	}
+
+	if done, total, what, ok := metricProgress(p.GetMetrics()); ok {
+		percent = 100 * float64(done) / float64(total)
+		parts = append(parts, fmt.Sprintf("%d/%d %s done", done, total, what))
+	}
+	return percent, strings.Join(parts, ", ")
+}
+
+// metricProgress finds a pair of progress metrics such as NODES_DONE and
+// NODES_TOTAL, preferring nodes over node pools.
+func metricProgress(metrics []*containerpb.OperationProgress_Metric) (done, total int64, what string, ok bool) {
+	values := map[string]int64{}
+	for _, m := range metrics {
+		if v, isInt := m.GetValue().(*containerpb.OperationProgress_Metric_IntValue); isInt {
+			name := strings.ToUpper(strings.ReplaceAll(strings.TrimSpace(m.GetName()), " ", "_"))
+			values[name] = v.IntValue
+		}
+	}
+	for _, prefix := range []string{"NODES", "NODE_POOLS"} {
+		total := values[prefix+"_TOTAL"]
+		if total <= 0 {
+			continue
+		}
+		for _, suffix := range []string{"_DONE", "_COMPLETE"} {
+			if done, ok := values[prefix+suffix]; ok {
+				return min(done, total), total, strings.ToLower(strings.ReplaceAll(prefix, "_", " ")), true
+			}
+		}
+	}
+	return 0, 0, "", false
+}
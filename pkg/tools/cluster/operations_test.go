@@ -0,0 +1,159 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func intMetric(name string, v int64) *containerpb.OperationProgress_Metric {
+	return &containerpb.OperationProgress_Metric{Name: name, Value: &containerpb.OperationProgress_Metric_IntValue{IntValue: v}}
+}
+
+func TestOperationProgress(t *testing.T) {
+	tests := []struct {
+		name        string
+		op          *containerpb.Operation
+		wantPercent float64
+		wantMessage string
+	}{
+		{
+			name:        "no progress",
+			op:          &containerpb.Operation{Status: containerpb.Operation_PENDING},
+			wantMessage: "PENDING",
+		},
+		{
+			name: "stages",
+			op: &containerpb.Operation{
+				Status: containerpb.Operation_RUNNING,
+				Progress: &containerpb.OperationProgress{Stages: []*containerpb.OperationProgress{
+					{Name: "CREATE_MASTER", Status: containerpb.Operation_DONE},
+					{Name: "CREATE_NODES", Status: containerpb.Operation_RUNNING},
+					{Name: "CONFIGURE", Status: containerpb.Operation_PENDING},
+					{Name: "HEALTH_CHECK", Status: containerpb.Operation_PENDING},
+				}},
+			},
+			wantPercent: 25,
+			wantMessage: "RUNNING, stage CREATE_NODES, 1/4 stages done",
+		},
+		{
+			name: "node metrics",
+			op: &containerpb.Operation{
+				Status: containerpb.Operation_RUNNING,
+				Progress: &containerpb.OperationProgress{Metrics: []*containerpb.OperationProgress_Metric{
+					intMetric("NODE_POOLS_TOTAL", 2),
+					intMetric("NODE_POOLS_COMPLETE", 1),
+					intMetric("NODES_TOTAL", 8),
+					intMetric("NODES_DONE", 6),
+				}},
+			},
+			wantPercent: 75,
+			wantMessage: "RUNNING, 6/8 nodes done",
+		},
+		{
+			name:        "done",
+			op:          &containerpb.Operation{Status: containerpb.Operation_DONE},
+			wantPercent: 100,
+			wantMessage: "DONE",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			percent, message := operationProgress(tt.op)
+			if percent != tt.wantPercent || message != tt.wantMessage {
+				t.Errorf("operationProgress() = %v, %q, want %v, %q", percent, message, tt.wantPercent, tt.wantMessage)
+			}
+		})
+	}
+}
+
+func TestPollOperation(t *testing.T) {
+	states := []*containerpb.Operation{
+		{Status: containerpb.Operation_PENDING},
+		{Status: containerpb.Operation_RUNNING, Progress: &containerpb.OperationProgress{Metrics: []*containerpb.OperationProgress_Metric{intMetric("NODES_TOTAL", 4), intMetric("NODES_DONE", 2)}}},
+		{Status: containerpb.Operation_RUNNING, Progress: &containerpb.OperationProgress{Metrics: []*containerpb.OperationProgress_Metric{intMetric("NODES_TOTAL", 4), intMetric("NODES_DONE", 1)}}},
+		// A new stage with unchanged metrics only changes the message.
+		{Status: containerpb.Operation_RUNNING, Progress: &containerpb.OperationProgress{
+			Metrics: []*containerpb.OperationProgress_Metric{intMetric("NODES_TOTAL", 4), intMetric("NODES_DONE", 2)},
+			Stages:  []*containerpb.OperationProgress{{Name: "drain", Status: containerpb.Operation_RUNNING}},
+		}},
+		{Status: containerpb.Operation_DONE},
+	}
+	calls := 0
+	get := func(context.Context) (*containerpb.Operation, error) {
+		op := states[calls]
+		calls++
+		return op, nil
+	}
+	var got []float64
+	var messages []string
+	progress := func(_ context.Context, percent float64, message string) {
+		got = append(got, percent)
+		messages = append(messages, message)
+	}
+
+	op, timedOut, err := pollOperation(context.Background(), get, time.Minute, time.Millisecond, progress)
+	if err != nil || timedOut || op.GetStatus() != containerpb.Operation_DONE {
+		t.Fatalf("pollOperation() = %v, %v, %v, want DONE operation", op, timedOut, err)
+	}
+	// Progress increases with every notification, even when the operation
+	// reports less or only its message changes.
+	if diff := cmp.Diff([]float64{0, 50, 100}, got); diff != "" {
+		t.Errorf("progress mismatch (-want +got):\n%s", diff)
+	}
+	if messages[len(messages)-1] != "DONE" {
+		t.Errorf("last progress message = %q, want DONE", messages[len(messages)-1])
+	}
+}
+
+func TestPollOperationTimeoutAndCancel(t *testing.T) {
+	running := func(context.Context) (*containerpb.Operation, error) {
+		return &containerpb.Operation{Status: containerpb.Operation_RUNNING}, nil
+	}
+
+	op, timedOut, err := pollOperation(context.Background(), running, 20*time.Millisecond, time.Millisecond, nil)
+	if err != nil || !timedOut || op.GetStatus() != containerpb.Operation_RUNNING {
+		t.Errorf("pollOperation() = %v, %v, %v, want timed out RUNNING operation", op, timedOut, err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, _, err := pollOperation(ctx, running, time.Minute, time.Minute, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("pollOperation() error = %v, want context.Canceled", err)
+	}
+}
+
+func TestTargetsCluster(t *testing.T) {
+	const prefix = "https://container.googleapis.com/v1/projects/p/locations/us-central1/clusters/"
+	tests := []struct {
+		target string
+		want   bool
+	}{
+		{prefix + "prod", true},
+		{prefix + "prod/nodePools/default-pool", true},
+		{prefix + "prod-2", false},
+	}
+	for _, tt := range tests {
+		if got := targetsCluster(tt.target, "prod"); got != tt.want {
+			t.Errorf("targetsCluster(%q, prod) = %v, want %v", tt.target, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,230 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"google.golang.org/protobuf/proto"
+)
+
+// clusterInfo describes a cluster. The summary fields cover what most
+// questions need, so callers don't have to page through the full resource;
+// Resource and Matches are only set when the full view or fields are requested.
+type clusterInfo struct {
+	ProjectID            string   `json:"project_id,omitempty" jsonschema:"Project of the cluster, set when listing clusters across projects."`
+	Name                 string   `json:"name"`
+	Location             string   `json:"location"`
+	Status               string   `json:"status" jsonschema:"Cluster status, e.g. RUNNING or RECONCILING."`
+	CurrentMasterVersion string   `json:"current_master_version,omitempty"`
+	CurrentNodeVersion   string   `json:"current_node_version,omitempty"`
+	ReleaseChannel       string   `json:"release_channel,omitempty" jsonschema:"Release channel, e.g. REGULAR. Empty if the cluster isn't enrolled in one."`
+	Autopilot            bool     `json:"autopilot"`
+	Endpoint             string   `json:"endpoint,omitempty" jsonschema:"IP address of the control plane."`
+	Network              string   `json:"network,omitempty"`
+	Subnetwork           string   `json:"subnetwork,omitempty"`
+	NodeCount            int32    `json:"node_count"`
+	NodePools            []string `json:"node_pools,omitempty"`
+	CreateTime           string   `json:"create_time,omitempty"`
+
+	Resource map[string]any `json:"resource,omitempty" jsonschema:"The cluster resource from the GKE API: all of it in the full view, or the fields selected by a field mask."`
+	Matches  []any          `json:"matches,omitempty" jsonschema:"Values selected by a JSONPath fields expression."`
+}
+
+type listClustersOutput struct {
+	ProjectID    string        `json:"project_id"`
+	Location     string        `json:"location" jsonschema:"Location that was listed, '-' for all locations."`
+	Clusters     []clusterInfo `json:"clusters,omitempty"`
+	MissingZones []string      `json:"missing_zones,omitempty" jsonschema:"Zones that couldn't be reached, so their clusters may be missing."`
+}
+
+type getClusterOutput struct {
+	Cluster clusterInfo `json:"cluster"`
+}
+
+// operationSummary describes a long-running GKE operation.
+type operationSummary struct {
+	Name          string `json:"name"`
+	OperationType string `json:"operation_type"`
+	Status        string `json:"status" jsonschema:"Operation status: PENDING, RUNNING, DONE or ABORTING."`
+	Location      string `json:"location,omitempty"`
+	TargetLink    string `json:"target_link,omitempty" jsonschema:"Resource the operation acts on."`
+	StatusMessage string `json:"status_message,omitempty"`
+	Detail        string `json:"detail,omitempty"`
+	Progress      string `json:"progress,omitempty" jsonschema:"Current stage and progress of an unfinished operation."`
+	Error         string `json:"error,omitempty" jsonschema:"Why the operation failed, if it did."`
+	StartTime     string `json:"start_time,omitempty"`
+	EndTime       string `json:"end_time,omitempty"`
+}
+
+type operationOutput struct {
+	Operation operationSummary `json:"operation"`
+}
+
+type getKubeconfigOutput struct {
+	ProjectID      string `json:"project_id"`
+	Location       string `json:"location"`
+	Cluster        string `json:"cluster"`
+	Context        string `json:"context" jsonschema:"Name of the kubeconfig context for the cluster."`
+	KubeconfigPath string `json:"kubeconfig_path"`
+	CurrentContext bool   `json:"current_context" jsonschema:"Whether the context was made the current context."`
+	EndpointType   string `json:"endpoint_type" jsonschema:"Control plane endpoint type the context connects through."`
+	Server         string `json:"server" jsonschema:"URL of the control plane endpoint."`
+}
+
+func newClusterInfo(c *containerpb.Cluster) clusterInfo {
+	s := clusterInfo{
+		Name:                 c.GetName(),
+		Location:             c.GetLocation(),
+		Status:               c.GetStatus().String(),
+		CurrentMasterVersion: c.GetCurrentMasterVersion(),
+		CurrentNodeVersion:   c.GetCurrentNodeVersion(),
+		Autopilot:            c.GetAutopilot().GetEnabled(),
+		Endpoint:             c.GetEndpoint(),
+		Network:              c.GetNetwork(),
+		Subnetwork:           c.GetSubnetwork(),
+		NodeCount:            c.GetCurrentNodeCount(),
+		CreateTime:           c.GetCreateTime(),
+	}
+	if channel := c.GetReleaseChannel().GetChannel(); channel != containerpb.ReleaseChannel_UNSPECIFIED {
+		s.ReleaseChannel = channel.String()
+	}
+	for _, np := range c.GetNodePools() {
+		s.NodePools = append(s.NodePools, np.GetName())
+	}
+	return s
+}
+
+// newClusterInfoForView returns the cluster's summary plus its full resource
+// in the full view, or the parts selected by proj if it isn't nil.
+func newClusterInfoForView(c *containerpb.Cluster, view string, proj *projection) (clusterInfo, error) {
+	info := newClusterInfo(c)
+	var err error
+	info.Resource, info.Matches, err = resourceForView(c, view, proj)
+	return info, err
+}
+
+// resourceForView returns m in its protojson form in the full view, or the
+// parts selected by proj if it isn't nil. It returns nothing in the summary
+// view without a projection.
+func resourceForView(m proto.Message, view string, proj *projection) (map[string]any, []any, error) {
+	if view != viewFull && proj == nil {
+		return nil, nil, nil
+	}
+	resource, err := toolresult.ProtoMap(m)
+	if err != nil {
+		return nil, nil, err
+	}
+	if proj == nil {
+		return resource, nil, nil
+	}
+	return proj.apply(resource)
+}
+
+// parseView validates a view argument, returning def if it's empty.
+func parseView(view, def string) (string, error) {
+	switch view {
+	case "":
+		return def, nil
+	case viewSummary, viewFull:
+		return view, nil
+	}
+	return "", fmt.Errorf("invalid view %q, must be %q or %q", view, viewSummary, viewFull)
+}
+
+// clusterTable renders clusters as a text table.
+func clusterTable(clusters []clusterInfo) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	withProject := len(clusters) > 0 && clusters[0].ProjectID != ""
+	if withProject {
+		fmt.Fprint(w, "PROJECT\t")
+	}
+	fmt.Fprintln(w, "NAME\tLOCATION\tVERSION\tSTATUS\tNODES\tCHANNEL")
+	for _, c := range clusters {
+		if withProject {
+			fmt.Fprintf(w, "%s\t", c.ProjectID)
+		}
+		nodes := fmt.Sprint(c.NodeCount)
+		if c.Autopilot {
+			nodes = "autopilot"
+		}
+		channel := c.ReleaseChannel
+		if channel == "" {
+			channel = "-"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", c.Name, c.Location, c.CurrentMasterVersion, c.Status, nodes, channel)
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func newOperationSummary(op *containerpb.Operation) operationSummary {
+	s := operationSummary{
+		Name:          op.GetName(),
+		OperationType: op.GetOperationType().String(),
+		Status:        op.GetStatus().String(),
+		Location:      op.GetLocation(),
+		TargetLink:    op.GetTargetLink(),
+		StatusMessage: op.GetStatusMessage(),
+		Detail:        op.GetDetail(),
+		Error:         op.GetError().GetMessage(),
+		StartTime:     op.GetStartTime(),
+		EndTime:       op.GetEndTime(),
+	}
+	if _, progress := operationProgress(op); progress != s.Status {
+		s.Progress = progress
+	}
+	return s
+}
+
+// String returns a one-line description of the operation for text summaries.
+func (s operationSummary) String() string {
+	desc := fmt.Sprintf("%s (%s", s.Name, s.OperationType)
+	if target := s.TargetLink; target != "" {
+		if i := strings.Index(target, "/projects/"); i >= 0 {
+			target = target[i+1:]
+		}
+		desc += " on " + target
+	}
+	desc += "): " + s.Status
+	if s.Progress != "" {
+		desc += " (" + s.Progress + ")"
+	}
+	if s.Error != "" {
+		desc += ", error: " + s.Error
+	} else if s.StatusMessage != "" {
+		desc += ", " + s.StatusMessage
+	}
+	return desc
+}
+
+// String returns a one-line description of the cluster for text summaries.
+func (s clusterInfo) String() string {
+	parts := []string{s.Status, "version " + s.CurrentMasterVersion}
+	if s.Autopilot {
+		parts = append(parts, "Autopilot")
+	} else {
+		parts = append(parts, fmt.Sprintf("%d nodes", s.NodeCount))
+	}
+	if s.ReleaseChannel != "" {
+		parts = append(parts, s.ReleaseChannel+" channel")
+	}
+	return fmt.Sprintf("%s (%s): %s", s.Name, s.Location, strings.Join(parts, ", "))
+}
@@ -0,0 +1,99 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+func TestNewClusterInfo(t *testing.T) {
+	c := &containerpb.Cluster{
+		Name:                 "my-cluster",
+		Location:             "us-central1",
+		Status:               containerpb.Cluster_RUNNING,
+		CurrentMasterVersion: "1.33.5-gke.1000",
+		CurrentNodeVersion:   "1.33.4-gke.2000",
+		ReleaseChannel:       &containerpb.ReleaseChannel{Channel: containerpb.ReleaseChannel_REGULAR},
+		Endpoint:             "10.0.0.2",
+		CurrentNodeCount:     3,
+		NodePools:            []*containerpb.NodePool{{Name: "default-pool"}, {Name: "gpu"}},
+	}
+	want := clusterInfo{
+		Name:                 "my-cluster",
+		Location:             "us-central1",
+		Status:               "RUNNING",
+		CurrentMasterVersion: "1.33.5-gke.1000",
+		CurrentNodeVersion:   "1.33.4-gke.2000",
+		ReleaseChannel:       "REGULAR",
+		Endpoint:             "10.0.0.2",
+		NodeCount:            3,
+		NodePools:            []string{"default-pool", "gpu"},
+	}
+	got := newClusterInfo(c)
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("newClusterInfo() mismatch (-want +got):\n%s", diff)
+	}
+	if s, want := got.String(), "my-cluster (us-central1): RUNNING, version 1.33.5-gke.1000, 3 nodes, REGULAR channel"; s != want {
+		t.Errorf("String() = %q, want %q", s, want)
+	}
+}
+
+func TestNewOperationSummary(t *testing.T) {
+	op := &containerpb.Operation{
+		Name:          "operation-123",
+		OperationType: containerpb.Operation_CREATE_CLUSTER,
+		Status:        containerpb.Operation_RUNNING,
+		Location:      "us-central1",
+	}
+	want := operationSummary{
+		Name:          "operation-123",
+		OperationType: "CREATE_CLUSTER",
+		Status:        "RUNNING",
+		Location:      "us-central1",
+	}
+	if diff := cmp.Diff(want, newOperationSummary(op)); diff != "" {
+		t.Errorf("newOperationSummary() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+// TestOutputSchemas checks that output schemas can be derived for every tool,
+// since AddTool panics otherwise.
+func TestOutputSchemas(t *testing.T) {
+	h := &handlers{c: &config.Config{}}
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(s, &mcp.Tool{Name: "list_clusters"}, h.listClusters)
+	mcp.AddTool(s, &mcp.Tool{Name: "get_cluster"}, h.getCluster)
+	mcp.AddTool(s, &mcp.Tool{Name: "create_cluster"}, h.createCluster)
+	mcp.AddTool(s, &mcp.Tool{Name: "get_kubeconfig"}, h.getKubeconfig)
+	mcp.AddTool(s, &mcp.Tool{Name: "list_fleet_clusters"}, h.listFleetClusters)
+	h.installNodePools(s)
+	h.installOperations(s)
+	h.installUpgrades(s)
+	h.installServerConfig(s)
+	h.installUpdate(s)
+	h.installMaintenance(s)
+	h.installDelete(s)
+	h.installKubeconfigContexts(s)
+	h.installResources(s)
+	h.installNodeLogs(s)
+	h.installNodeHealth(s)
+	h.installSosReport(s)
+	h.installSosAnalyzer(s)
+}
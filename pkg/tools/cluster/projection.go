@@ -0,0 +1,180 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"strings"
+
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	viewSummary = "summary"
+	viewFull    = "full"
+)
+
+// projection selects parts of a resource in its protojson form, either with a
+// protobuf field mask or a JSONPath expression.
+type projection struct {
+	// paths are field mask paths as JSON field names, e.g. ["nodePools", "config"].
+	paths    [][]string
+	jsonPath *jsonpath.JSONPath
+}
+
+// newProjection parses fields for resources of the given message type. An
+// expression starting with "$" or "{" is JSONPath; anything else is a
+// comma-separated field mask whose paths may use proto or JSON field names.
+// It returns nil if fields is empty.
+func newProjection(desc protoreflect.MessageDescriptor, fields string) (*projection, error) {
+	fields = strings.TrimSpace(fields)
+	if fields == "" {
+		return nil, nil
+	}
+	if strings.HasPrefix(fields, "$") || strings.HasPrefix(fields, "{") {
+		if !strings.HasPrefix(fields, "{") {
+			fields = "{" + fields + "}"
+		}
+		jp := jsonpath.New("fields").AllowMissingKeys(true)
+		if err := jp.Parse(fields); err != nil {
+			return nil, fmt.Errorf("invalid JSONPath in fields: %w", err)
+		}
+		return &projection{jsonPath: jp}, nil
+	}
+
+	p := &projection{}
+	for _, path := range strings.Split(fields, ",") {
+		path = strings.TrimSpace(path)
+		if path == "" {
+			continue
+		}
+		segments, err := jsonFieldPath(desc, path)
+		if err != nil {
+			return nil, err
+		}
+		p.paths = append(p.paths, segments)
+	}
+	return p, nil
+}
+
+// jsonFieldPath validates a field mask path against desc and returns its
+// segments as JSON field names.
+func jsonFieldPath(desc protoreflect.MessageDescriptor, path string) ([]string, error) {
+	var segments []string
+	for _, name := range strings.Split(path, ".") {
+		if desc == nil {
+			return nil, fmt.Errorf("invalid field mask path %q: %s has no subfields", path, strings.Join(segments, "."))
+		}
+		fd := desc.Fields().ByName(protoreflect.Name(name))
+		if fd == nil {
+			fd = desc.Fields().ByJSONName(name)
+		}
+		if fd == nil {
+			return nil, fmt.Errorf("invalid field mask path %q: %s has no field %q", path, desc.Name(), name)
+		}
+		segments = append(segments, fd.JSONName())
+		desc = nil
+		if fd.Message() != nil && !fd.IsMap() {
+			desc = fd.Message()
+		}
+	}
+	return segments, nil
+}
+
+// apply returns the selected parts of resource. Field masks yield an object
+// with the same shape as resource; JSONPath yields the list of matches.
+func (p *projection) apply(resource map[string]any) (map[string]any, []any, error) {
+	if p.jsonPath != nil {
+		results, err := p.jsonPath.FindResults(resource)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to evaluate JSONPath: %w", err)
+		}
+		var matches []any
+		for _, r := range results {
+			for _, v := range r {
+				matches = append(matches, v.Interface())
+			}
+		}
+		return nil, matches, nil
+	}
+
+	out := map[string]any{}
+	for _, path := range p.paths {
+		if v, ok := selectPath(resource, path); ok {
+			out = mergeValues(out, v).(map[string]any)
+		}
+	}
+	return out, nil, nil
+}
+
+// selectPath returns v with everything but path removed. Lists are descended
+// element by element; elements without the path become empty objects so that
+// results of several paths can be merged by index.
+func selectPath(v any, path []string) (any, bool) {
+	if len(path) == 0 {
+		return v, true
+	}
+	switch t := v.(type) {
+	case map[string]any:
+		child, ok := t[path[0]]
+		if !ok {
+			return nil, false
+		}
+		sel, ok := selectPath(child, path[1:])
+		if !ok {
+			return nil, false
+		}
+		return map[string]any{path[0]: sel}, true
+	case []any:
+		out := make([]any, len(t))
+		found := false
+		for i, e := range t {
+			sel, ok := selectPath(e, path)
+			if !ok {
+				sel = map[string]any{}
+			}
+			found = found || ok
+			out[i] = sel
+		}
+		return out, found
+	}
+	return nil, false
+}
+
+// mergeValues merges the selections b into a.
+func mergeValues(a, b any) any {
+	switch at := a.(type) {
+	case map[string]any:
+		if bt, ok := b.(map[string]any); ok {
+			for k, v := range bt {
+				if existing, ok := at[k]; ok {
+					at[k] = mergeValues(existing, v)
+				} else {
+					at[k] = v
+				}
+			}
+			return at
+		}
+	case []any:
+		if bt, ok := b.([]any); ok && len(at) == len(bt) {
+			for i := range at {
+				at[i] = mergeValues(at[i], bt[i])
+			}
+			return at
+		}
+	}
+	return b
+}
@@ -0,0 +1,162 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+)
+
+func testCluster() *containerpb.Cluster {
+	return &containerpb.Cluster{
+		Name:                 "my-cluster",
+		Location:             "us-central1",
+		Status:               containerpb.Cluster_RUNNING,
+		CurrentMasterVersion: "1.33.5-gke.1000",
+		ReleaseChannel:       &containerpb.ReleaseChannel{Channel: containerpb.ReleaseChannel_REGULAR},
+		NodePools: []*containerpb.NodePool{
+			{Name: "default-pool", Config: &containerpb.NodeConfig{MachineType: "e2-medium"}, InitialNodeCount: 3},
+			{Name: "gpu", Config: &containerpb.NodeConfig{MachineType: "a2-highgpu-1g"}},
+		},
+	}
+}
+
+func TestProjectionFieldMask(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields string
+		want   map[string]any
+	}{
+		{
+			name:   "top-level fields",
+			fields: "name, releaseChannel",
+			want: map[string]any{
+				"name":           "my-cluster",
+				"releaseChannel": map[string]any{"channel": "REGULAR"},
+			},
+		},
+		{
+			name:   "proto names through repeated field",
+			fields: "node_pools.config.machine_type",
+			want: map[string]any{
+				"nodePools": []any{
+					map[string]any{"config": map[string]any{"machineType": "e2-medium"}},
+					map[string]any{"config": map[string]any{"machineType": "a2-highgpu-1g"}},
+				},
+			},
+		},
+		{
+			name:   "paths merged by index",
+			fields: "nodePools.name,nodePools.initialNodeCount",
+			want: map[string]any{
+				"nodePools": []any{
+					map[string]any{"name": "default-pool", "initialNodeCount": 3.0},
+					map[string]any{"name": "gpu"},
+				},
+			},
+		},
+		{
+			name:   "unset field",
+			fields: "endpoint",
+			want:   map[string]any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := newClusterInfoForView(testCluster(), viewSummary, mustProjection(t, tt.fields))
+			if err != nil {
+				t.Fatalf("newClusterInfoForView() error = %v", err)
+			}
+			if diff := cmp.Diff(tt.want, info.Resource); diff != "" {
+				t.Errorf("Resource mismatch (-want +got):\n%s", diff)
+			}
+			if info.Name != "my-cluster" {
+				t.Errorf("Name = %q, want the summary to be kept", info.Name)
+			}
+		})
+	}
+}
+
+func TestProjectionJSONPath(t *testing.T) {
+	for _, fields := range []string{"$.nodePools[*].name", "{.nodePools[*].name}"} {
+		info, err := newClusterInfoForView(testCluster(), viewSummary, mustProjection(t, fields))
+		if err != nil {
+			t.Fatalf("newClusterInfoForView(%q) error = %v", fields, err)
+		}
+		if diff := cmp.Diff([]any{"default-pool", "gpu"}, info.Matches); diff != "" {
+			t.Errorf("Matches for %q mismatch (-want +got):\n%s", fields, diff)
+		}
+		if info.Resource != nil {
+			t.Errorf("Resource = %v, want nil for JSONPath", info.Resource)
+		}
+	}
+}
+
+func TestProjectionErrors(t *testing.T) {
+	for _, fields := range []string{"nope", "name.first", "nodePools.bogus", "$.nodePools[", "resourceLabels.env"} {
+		if _, err := newProjection((&containerpb.Cluster{}).ProtoReflect().Descriptor(), fields); err == nil {
+			t.Errorf("newProjection(%q) error = nil, want error", fields)
+		}
+	}
+}
+
+func TestClusterInfoViews(t *testing.T) {
+	info, err := newClusterInfoForView(testCluster(), viewSummary, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Resource != nil {
+		t.Errorf("summary view Resource = %v, want nil", info.Resource)
+	}
+
+	info, err = newClusterInfoForView(testCluster(), viewFull, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Resource["name"] != "my-cluster" || info.Resource["nodePools"] == nil {
+		t.Errorf("full view Resource = %v, want the whole cluster", info.Resource)
+	}
+
+	if _, err := parseView("detailed", viewSummary); err == nil {
+		t.Errorf("parseView(\"detailed\") error = nil, want error")
+	}
+	if v, _ := parseView("", viewFull); v != viewFull {
+		t.Errorf("parseView(\"\") = %q, want default %q", v, viewFull)
+	}
+}
+
+func TestClusterTable(t *testing.T) {
+	got := clusterTable([]clusterInfo{
+		newClusterInfo(testCluster()),
+		{Name: "ap", Location: "europe-west1", CurrentMasterVersion: "1.34.1-gke.100", Status: "RUNNING", Autopilot: true},
+	})
+	want := "NAME        LOCATION      VERSION          STATUS   NODES      CHANNEL\n" +
+		"my-cluster  us-central1   1.33.5-gke.1000  RUNNING  0          REGULAR\n" +
+		"ap          europe-west1  1.34.1-gke.100   RUNNING  autopilot  -"
+	if got != want {
+		t.Errorf("clusterTable() =\n%s\nwant:\n%s", got, want)
+	}
+}
+
+func mustProjection(t *testing.T, fields string) *projection {
+	t.Helper()
+	p, err := newProjection((&containerpb.Cluster{}).ProtoReflect().Descriptor(), fields)
+	if err != nil {
+		t.Fatalf("newProjection(%q) error = %v", fields, err)
+	}
+	return p
+}
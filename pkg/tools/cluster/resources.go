@@ -0,0 +1,535 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/base64"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+const (
+	defaultResourceLimit = 100
+	maxResourceLimit     = 500
+
+	defaultLogTailLines  = 500
+	defaultLogLimitBytes = 256 * 1024
+	maxLogLimitBytes     = 4 * 1024 * 1024
+)
+
+var secretGroupKind = schema.GroupKind{Kind: "Secret"}
+
+type listK8sResourcesArgs struct {
+	ProjectID     string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location      string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster       string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	APIVersion    string `json:"api_version,omitempty" jsonschema:"API group and version of the resource, like 'v1' or 'apps/v1'. Defaults to the preferred version of the group that has the resource."`
+	Kind          string `json:"kind" jsonschema:"Resource kind, resource name or short name, like 'Deployment', 'deployments', 'deploy' or 'certificates.cert-manager.io'."`
+	Namespace     string `json:"namespace,omitempty" jsonschema:"Namespace to list. Defaults to all namespaces. Ignored for cluster-scoped resources."`
+	LabelSelector string `json:"label_selector,omitempty" jsonschema:"Kubernetes label selector, like 'app=web,tier!=cache'."`
+	FieldSelector string `json:"field_selector,omitempty" jsonschema:"Kubernetes field selector, like 'status.phase=Running' or 'spec.nodeName=NODE'."`
+	Limit         int64  `json:"limit,omitempty" jsonschema:"Maximum number of resources to return. Defaults to 100, at most 500. Pass the returned continue token to get more."`
+	Continue      string `json:"continue,omitempty" jsonschema:"Continue token from a previous call, to get the next page."`
+	View          string `json:"view,omitempty" jsonschema:"How much of each resource to return: 'summary' (default) for namespace, name, creation time, status and labels, or 'full' for the complete resources."`
+}
+
+type getK8sResourceArgs struct {
+	ProjectID  string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location   string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster    string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	APIVersion string `json:"api_version,omitempty" jsonschema:"API group and version of the resource, like 'v1' or 'apps/v1'. Defaults to the preferred version of the group that has the resource."`
+	Kind       string `json:"kind" jsonschema:"Resource kind, resource name or short name, like 'Deployment', 'deployments' or 'deploy'."`
+	Namespace  string `json:"namespace,omitempty" jsonschema:"Namespace of the resource. Defaults to 'default'. Ignored for cluster-scoped resources."`
+	Name       string `json:"name" jsonschema:"Name of the resource."`
+}
+
+type getPodLogsArgs struct {
+	ProjectID  string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location   string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster    string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Namespace  string `json:"namespace,omitempty" jsonschema:"Namespace of the pod. Defaults to 'default'."`
+	Pod        string `json:"pod" jsonschema:"Name of the pod."`
+	Container  string `json:"container,omitempty" jsonschema:"Container to get the logs of. Can be omitted for pods with a single container."`
+	Since      string `json:"since,omitempty" jsonschema:"Only return logs newer than this duration, like '10m' or '2h'."`
+	TailLines  int64  `json:"tail_lines,omitempty" jsonschema:"Number of lines to return from the end of the logs. Defaults to 500."`
+	Previous   bool   `json:"previous,omitempty" jsonschema:"Return the logs of the previous instance of the container, for containers that restarted or crashed."`
+	LimitBytes int64  `json:"limit_bytes,omitempty" jsonschema:"Maximum size of the logs in bytes. Defaults to 256 KiB, at most 4 MiB."`
+	Timestamps bool   `json:"timestamps,omitempty" jsonschema:"Prefix each line with its timestamp."`
+}
+
+type k8sResourceSummary struct {
+	Namespace string            `json:"namespace,omitempty"`
+	Name      string            `json:"name"`
+	Created   string            `json:"created,omitempty"`
+	Status    string            `json:"status,omitempty" jsonschema:"The resource's phase or Ready/Available condition, if it has one."`
+	Labels    map[string]string `json:"labels,omitempty"`
+
+	created time.Time
+}
+
+type listK8sResourcesOutput struct {
+	APIVersion string               `json:"api_version"`
+	Kind       string               `json:"kind"`
+	Namespace  string               `json:"namespace,omitempty"`
+	Items      []k8sResourceSummary `json:"items,omitempty"`
+	Objects    []map[string]any     `json:"objects,omitempty" jsonschema:"The complete resources, in the full view."`
+	Continue   string               `json:"continue,omitempty" jsonschema:"Token to get the next page, if there are more resources."`
+}
+
+type getK8sResourceOutput struct {
+	Object map[string]any `json:"object"`
+}
+
+type k8sEvent struct {
+	Type      string `json:"type"`
+	Reason    string `json:"reason"`
+	Message   string `json:"message"`
+	Count     int32  `json:"count,omitempty"`
+	FirstSeen string `json:"first_seen,omitempty"`
+	LastSeen  string `json:"last_seen,omitempty"`
+	Source    string `json:"source,omitempty"`
+
+	lastSeen time.Time
+}
+
+type describeK8sResourceOutput struct {
+	Object map[string]any `json:"object"`
+	Events []k8sEvent     `json:"events,omitempty" jsonschema:"Events about the resource, oldest first."`
+}
+
+type getPodLogsOutput struct {
+	Namespace string `json:"namespace"`
+	Pod       string `json:"pod"`
+	Container string `json:"container,omitempty"`
+	Previous  bool   `json:"previous,omitempty"`
+	Logs      string `json:"logs"`
+	Truncated bool   `json:"truncated,omitempty" jsonschema:"Whether the logs were cut at limit_bytes."`
+}
+
+// installResources registers the tools reading Kubernetes resources.
+func (h *handlers) installResources(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "list_k8s_resources",
+		Description: "List Kubernetes resources of any type, including custom resources, in a GKE cluster, filtered by namespace, label selector and field selector. The values of Secrets are redacted. Connects to the cluster directly instead of using a kubeconfig context. Prefer this tool to kubectl get.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.listK8sResources)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_k8s_resource",
+		Description: "Get a Kubernetes resource of any type from a GKE cluster. The values of Secrets are redacted. Connects to the cluster directly instead of using a kubeconfig context. Prefer this tool to kubectl get -o yaml.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getK8sResource)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "describe_k8s_resource",
+		Description: "Describe a Kubernetes resource of any type in a GKE cluster: the resource and the events about it. The values of Secrets are redacted. Connects to the cluster directly instead of using a kubeconfig context. Prefer this tool to kubectl describe.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.describeK8sResource)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_pod_logs",
+		Description: "Get the logs of a container in a pod in a GKE cluster, optionally of its previous instance. Connects to the cluster directly instead of using a kubeconfig context. Prefer this tool to kubectl logs; use query_logs for logs older than the container.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getPodLogs)
+}
+
+func (h *handlers) listK8sResources(ctx context.Context, _ *mcp.CallToolRequest, args *listK8sResourcesArgs) (*mcp.CallToolResult, *listK8sResourcesOutput, error) {
+	view, err := parseView(args.View, viewSummary)
+	if err != nil {
+		return nil, nil, err
+	}
+	switch {
+	case args.Limit <= 0:
+		args.Limit = defaultResourceLimit
+	case args.Limit > maxResourceLimit:
+		args.Limit = maxResourceLimit
+	}
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	mapping, err := resolveResource(k.mapper, args.APIVersion, args.Kind)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	list, err := k.resourceInterface(mapping, args.Namespace).List(ctx, metav1.ListOptions{
+		LabelSelector: args.LabelSelector,
+		FieldSelector: args.FieldSelector,
+		Limit:         args.Limit,
+		Continue:      args.Continue,
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list %s: %w", mapping.Resource.Resource, err)
+	}
+
+	out := &listK8sResourcesOutput{
+		APIVersion: mapping.GroupVersionKind.GroupVersion().String(),
+		Kind:       mapping.GroupVersionKind.Kind,
+		Continue:   list.GetContinue(),
+	}
+	if mapping.Scope.Name() == meta.RESTScopeNameNamespace {
+		out.Namespace = args.Namespace
+	}
+	var items []k8sResourceSummary
+	for i := range list.Items {
+		items = append(items, newK8sResourceSummary(&list.Items[i]))
+		if view == viewFull {
+			out.Objects = append(out.Objects, trimObject(&list.Items[i], h.c.ShowSecrets()))
+		}
+	}
+	if view != viewFull {
+		out.Items = items
+	}
+
+	where := "all namespaces"
+	switch {
+	case mapping.Scope.Name() != meta.RESTScopeNameNamespace:
+		where = "the cluster"
+	case args.Namespace != "":
+		where = "namespace " + args.Namespace
+	}
+	summary := fmt.Sprintf("Found %d %s in %s.", len(list.Items), mapping.Resource.Resource, where)
+	if len(items) > 0 {
+		summary += "\n\n" + k8sResourceTable(items)
+	}
+	if out.Continue != "" {
+		summary += "\nThere are more resources; pass the continue token to get them."
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func (h *handlers) getK8sResource(ctx context.Context, _ *mcp.CallToolRequest, args *getK8sResourceArgs) (*mcp.CallToolResult, *getK8sResourceOutput, error) {
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := k8sResource(ctx, k, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &getK8sResourceOutput{Object: trimObject(u, h.c.ShowSecrets())}
+	res, err := toolresult.New(fmt.Sprintf("%s %s.", u.GetKind(), objectName(u)), out)
+	return res, out, err
+}
+
+func (h *handlers) describeK8sResource(ctx context.Context, _ *mcp.CallToolRequest, args *getK8sResourceArgs) (*mcp.CallToolResult, *describeK8sResourceOutput, error) {
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	u, err := k8sResource(ctx, k, args)
+	if err != nil {
+		return nil, nil, err
+	}
+	events, err := objectEvents(ctx, k, u)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := &describeK8sResourceOutput{Object: trimObject(u, h.c.ShowSecrets()), Events: events}
+	summary := fmt.Sprintf("%s %s", u.GetKind(), objectName(u))
+	if s := newK8sResourceSummary(u).Status; s != "" {
+		summary += " is " + s
+	}
+	summary += fmt.Sprintf(", with %d events.", len(events))
+	if len(events) > 0 {
+		summary += "\n\n" + k8sEventTable(events)
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// k8sResource gets the resource args refer to.
+func k8sResource(ctx context.Context, k *kubeClients, args *getK8sResourceArgs) (*unstructured.Unstructured, error) {
+	if args.Name == "" {
+		return nil, fmt.Errorf("name argument cannot be empty")
+	}
+	if args.Namespace == "" {
+		args.Namespace = metav1.NamespaceDefault
+	}
+	mapping, err := resolveResource(k.mapper, args.APIVersion, args.Kind)
+	if err != nil {
+		return nil, err
+	}
+	u, err := k.resourceInterface(mapping, args.Namespace).Get(ctx, args.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s %s: %w", mapping.Resource.Resource, args.Name, err)
+	}
+	return u, nil
+}
+
+func (h *handlers) getPodLogs(ctx context.Context, _ *mcp.CallToolRequest, args *getPodLogsArgs) (*mcp.CallToolResult, *getPodLogsOutput, error) {
+	if args.Pod == "" {
+		return nil, nil, fmt.Errorf("pod argument cannot be empty")
+	}
+	if args.Namespace == "" {
+		args.Namespace = metav1.NamespaceDefault
+	}
+	if args.TailLines <= 0 {
+		args.TailLines = defaultLogTailLines
+	}
+	switch {
+	case args.LimitBytes <= 0:
+		args.LimitBytes = defaultLogLimitBytes
+	case args.LimitBytes > maxLogLimitBytes:
+		args.LimitBytes = maxLogLimitBytes
+	}
+	opts := &corev1.PodLogOptions{
+		Container:  args.Container,
+		Previous:   args.Previous,
+		TailLines:  &args.TailLines,
+		Timestamps: args.Timestamps,
+	}
+	if args.Since != "" {
+		d, err := time.ParseDuration(args.Since)
+		if err != nil || d <= 0 {
+			return nil, nil, fmt.Errorf("invalid since %q, must be a positive duration like '10m' or '2h'", args.Since)
+		}
+		seconds := int64(d.Round(time.Second) / time.Second)
+		opts.SinceSeconds = &seconds
+	}
+	k, err := h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	stream, err := k.clientset.CoreV1().Pods(args.Namespace).GetLogs(args.Pod, opts).Stream(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get logs of pod %s: %w", args.Pod, err)
+	}
+	defer stream.Close()
+	// Read one byte past the limit to tell whether the logs were cut.
+	b, err := io.ReadAll(io.LimitReader(stream, args.LimitBytes+1))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read logs of pod %s: %w", args.Pod, err)
+	}
+
+	out := &getPodLogsOutput{
+		Namespace: args.Namespace,
+		Pod:       args.Pod,
+		Container: args.Container,
+		Previous:  args.Previous,
+		Logs:      string(b),
+	}
+	if int64(len(b)) > args.LimitBytes {
+		out.Logs, out.Truncated = string(b[:args.LimitBytes]), true
+	}
+
+	what := "Logs"
+	if args.Previous {
+		what = "Logs of the previous instance"
+	}
+	summary := fmt.Sprintf("%s of pod %s/%s", what, args.Namespace, args.Pod)
+	if args.Container != "" {
+		summary += ", container " + args.Container
+	}
+	summary += fmt.Sprintf(": %d bytes", len(out.Logs))
+	if out.Truncated {
+		summary += fmt.Sprintf(", cut at %d bytes", args.LimitBytes)
+	}
+	summary += ". The logs follow."
+	return toolresult.NewText(summary, out.Logs), out, nil
+}
+
+// objectEvents returns the events about u, oldest first.
+func objectEvents(ctx context.Context, k *kubeClients, u *unstructured.Unstructured) ([]k8sEvent, error) {
+	list, err := k.clientset.CoreV1().Events(u.GetNamespace()).List(ctx, metav1.ListOptions{
+		FieldSelector: fields.OneTermEqualSelector("involvedObject.uid", string(u.GetUID())).String(),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list events: %w", err)
+	}
+	var events []k8sEvent
+	for _, e := range list.Items {
+		if e.InvolvedObject.UID != u.GetUID() {
+			continue
+		}
+		events = append(events, newK8sEvent(&e))
+	}
+	sort.SliceStable(events, func(i, j int) bool { return events[i].lastSeen.Before(events[j].lastSeen) })
+	return events, nil
+}
+
+func newK8sEvent(e *corev1.Event) k8sEvent {
+	first, last := e.FirstTimestamp.Time, e.LastTimestamp.Time
+	if last.IsZero() {
+		last = e.EventTime.Time
+	}
+	if first.IsZero() {
+		first = last
+	}
+	out := k8sEvent{
+		Type:     e.Type,
+		Reason:   e.Reason,
+		Message:  strings.TrimSpace(e.Message),
+		Count:    e.Count,
+		Source:   firstNonEmpty(e.Source.Component, e.ReportingController),
+		lastSeen: last,
+	}
+	if !first.IsZero() {
+		out.FirstSeen = first.UTC().Format(time.RFC3339)
+	}
+	if !last.IsZero() {
+		out.LastSeen = last.UTC().Format(time.RFC3339)
+	}
+	return out
+}
+
+func newK8sResourceSummary(u *unstructured.Unstructured) k8sResourceSummary {
+	s := k8sResourceSummary{
+		Namespace: u.GetNamespace(),
+		Name:      u.GetName(),
+		Labels:    u.GetLabels(),
+		Status:    objectStatus(u),
+		created:   u.GetCreationTimestamp().Time,
+	}
+	if !s.created.IsZero() {
+		s.Created = s.created.UTC().Format(time.RFC3339)
+	}
+	return s
+}
+
+// objectStatus returns the phase of u, or else its Ready or Available
+// condition.
+func objectStatus(u *unstructured.Unstructured) string {
+	if phase, _, _ := unstructured.NestedString(u.Object, "status", "phase"); phase != "" {
+		return phase
+	}
+	conditions, _, _ := unstructured.NestedSlice(u.Object, "status", "conditions")
+	for _, want := range []string{"Ready", "Available"} {
+		for _, c := range conditions {
+			m, ok := c.(map[string]any)
+			if !ok || m["type"] != want {
+				continue
+			}
+			if status, _ := m["status"].(string); status != "" {
+				return want + "=" + status
+			}
+		}
+	}
+	return ""
+}
+
+// trimObject returns u without its managed fields, which are long and rarely
+// useful. The values of Secrets are redacted unless showSecrets is set.
+func trimObject(u *unstructured.Unstructured, showSecrets bool) map[string]any {
+	u = u.DeepCopy()
+	u.SetManagedFields(nil)
+	if !showSecrets && u.GroupVersionKind().GroupKind() == secretGroupKind {
+		redactSecret(u)
+	}
+	return u.Object
+}
+
+// redactSecret replaces the values of Secret u with their sizes, like kubectl
+// describe, and removes the last applied configuration, which holds them too.
+func redactSecret(u *unstructured.Unstructured) {
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := u.Object[field].(map[string]any)
+		if !ok {
+			continue
+		}
+		for key, v := range values {
+			s, _ := v.(string)
+			size := len(s)
+			if field == "data" {
+				if b, err := base64.StdEncoding.DecodeString(s); err == nil {
+					size = len(b)
+				}
+			}
+			values[key] = fmt.Sprintf("<redacted: %d bytes>", size)
+		}
+	}
+	if annotations := u.GetAnnotations(); annotations != nil {
+		if _, ok := annotations[corev1.LastAppliedConfigAnnotation]; ok {
+			delete(annotations, corev1.LastAppliedConfigAnnotation)
+			u.SetAnnotations(annotations)
+		}
+	}
+}
+
+func objectName(u *unstructured.Unstructured) string {
+	if u.GetNamespace() == "" {
+		return u.GetName()
+	}
+	return u.GetNamespace() + "/" + u.GetName()
+}
+
+// k8sResourceTable renders resources as a text table.
+func k8sResourceTable(items []k8sResourceSummary) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	namespaced := len(items) > 0 && items[0].Namespace != ""
+	if namespaced {
+		fmt.Fprint(w, "NAMESPACE\t")
+	}
+	fmt.Fprintln(w, "NAME\tSTATUS\tAGE")
+	for _, item := range items {
+		if namespaced {
+			fmt.Fprintf(w, "%s\t", item.Namespace)
+		}
+		age := "-"
+		if !item.created.IsZero() {
+			age = duration.HumanDuration(time.Since(item.created))
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\n", item.Name, valueOrDash(item.Status), age)
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// k8sEventTable renders events as a text table.
+func k8sEventTable(events []k8sEvent) string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LAST SEEN\tTYPE\tREASON\tCOUNT\tMESSAGE")
+	for _, e := range events {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%d\t%s\n", valueOrDash(e.LastSeen), e.Type, e.Reason, max(e.Count, 1), e.Message)
+	}
+	_ = w.Flush()
+	return strings.TrimRight(b.String(), "\n")
+}
+
+func valueOrDash(s string) string {
+	if s == "" {
+		return "-"
+	}
+	return s
+}
@@ -0,0 +1,341 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	dynamicfake "k8s.io/client-go/dynamic/fake"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+var (
+	podsGVR  = schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	nodesGVR = schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+)
+
+func testMapper() meta.RESTMapper {
+	m := meta.NewDefaultRESTMapper(nil)
+	m.Add(schema.GroupVersionKind{Version: "v1", Kind: "Pod"}, meta.RESTScopeNamespace)
+	m.Add(schema.GroupVersionKind{Version: "v1", Kind: "Node"}, meta.RESTScopeRoot)
+	m.Add(schema.GroupVersionKind{Group: "apps", Version: "v1", Kind: "Deployment"}, meta.RESTScopeNamespace)
+	return m
+}
+
+func testUnstructured(apiVersion, kind, namespace, name string, labels map[string]string) *unstructured.Unstructured {
+	u := &unstructured.Unstructured{}
+	u.SetAPIVersion(apiVersion)
+	u.SetKind(kind)
+	u.SetNamespace(namespace)
+	u.SetName(name)
+	u.SetUID(types.UID("uid-" + name))
+	u.SetLabels(labels)
+	u.SetCreationTimestamp(metav1.NewTime(time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)))
+	u.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: "kubectl"}})
+	return u
+}
+
+// testKubeHandlers returns handlers whose clusters are served by the given
+// fake objects.
+func testKubeHandlers(objects []runtime.Object, dynObjects ...runtime.Object) *handlers {
+	k := &kubeClients{
+		clientset: fake.NewClientset(objects...),
+		dynamic: dynamicfake.NewSimpleDynamicClientWithCustomListKinds(runtime.NewScheme(), map[schema.GroupVersionResource]string{
+			podsGVR:  "PodList",
+			nodesGVR: "NodeList",
+		}, dynObjects...),
+		mapper: testMapper(),
+	}
+	return &handlers{
+		c: &config.Config{},
+		kube: func(context.Context, string, string, string) (*kubeClients, error) {
+			return k, nil
+		},
+	}
+}
+
+func TestKubeClientsCache(t *testing.T) {
+	var cache kubeClientsCache
+	created := 0
+	create := func(context.Context) (*kubeClients, error) {
+		created++
+		if created == 1 {
+			return nil, errors.New("cluster not found")
+		}
+		return &kubeClients{}, nil
+	}
+
+	if _, err := cache.get(context.Background(), "c", create); err == nil {
+		t.Fatalf("get() succeeded, want the creation error")
+	}
+	first, err := cache.get(context.Background(), "c", create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	second, err := cache.get(context.Background(), "c", create)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first != second || created != 2 {
+		t.Errorf("created clients %d times, want errors not cached and clients reused", created)
+	}
+
+	cache.entries["c"] = kubeClientsEntry{clients: first, created: time.Now().Add(-kubeClientsTTL)}
+	if third, _ := cache.get(context.Background(), "c", create); third == first {
+		t.Errorf("get() reused expired clients")
+	}
+}
+
+func TestResolveResource(t *testing.T) {
+	tests := []struct {
+		apiVersion string
+		kind       string
+		want       schema.GroupVersionResource
+		wantErr    bool
+	}{
+		{kind: "Pod", want: podsGVR},
+		{kind: "pods", want: podsGVR},
+		{apiVersion: "v1", kind: "pod", want: podsGVR},
+		{kind: "deployments.apps", want: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{apiVersion: "apps/v1", kind: "Deployment", want: schema.GroupVersionResource{Group: "apps", Version: "v1", Resource: "deployments"}},
+		{kind: "Widget", wantErr: true},
+		{apiVersion: "apps/v2", kind: "Deployment", wantErr: true},
+		{apiVersion: "a/b/c", kind: "Pod", wantErr: true},
+		{wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := resolveResource(testMapper(), tt.apiVersion, tt.kind)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("resolveResource(%q, %q) error = %v, wantErr %v", tt.apiVersion, tt.kind, err, tt.wantErr)
+			continue
+		}
+		if err == nil && got.Resource != tt.want {
+			t.Errorf("resolveResource(%q, %q) = %v, want %v", tt.apiVersion, tt.kind, got.Resource, tt.want)
+		}
+	}
+}
+
+func TestListK8sResources(t *testing.T) {
+	web := testUnstructured("v1", "Pod", "a", "web", map[string]string{"app": "web"})
+	if err := unstructured.SetNestedField(web.Object, "Running", "status", "phase"); err != nil {
+		t.Fatal(err)
+	}
+	h := testKubeHandlers(nil,
+		web,
+		testUnstructured("v1", "Pod", "b", "web-2", map[string]string{"app": "web"}),
+		testUnstructured("v1", "Pod", "b", "db", map[string]string{"app": "db"}),
+		testUnstructured("v1", "Node", "", "node-1", nil),
+	)
+	tests := []struct {
+		name string
+		args listK8sResourcesArgs
+		want []string
+	}{
+		{name: "all namespaces", args: listK8sResourcesArgs{Kind: "pods"}, want: []string{"a/web Running", "b/db ", "b/web-2 "}},
+		{name: "namespace", args: listK8sResourcesArgs{Kind: "pods", Namespace: "b"}, want: []string{"b/db ", "b/web-2 "}},
+		{name: "label selector", args: listK8sResourcesArgs{Kind: "pods", LabelSelector: "app=web"}, want: []string{"a/web Running", "b/web-2 "}},
+		{name: "cluster-scoped", args: listK8sResourcesArgs{Kind: "Node", Namespace: "b"}, want: []string{"/node-1 "}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.Cluster = "c"
+			_, out, err := h.listK8sResources(context.Background(), nil, &tt.args)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, item := range out.Items {
+				got = append(got, item.Namespace+"/"+item.Name+" "+item.Status)
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("listK8sResources() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+
+	_, out, err := h.listK8sResources(context.Background(), nil, &listK8sResourcesArgs{Cluster: "c", Kind: "pods", Namespace: "a", View: viewFull})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Items) != 0 || len(out.Objects) != 1 {
+		t.Fatalf("full view returned %d items and %d objects, want 0 and 1", len(out.Items), len(out.Objects))
+	}
+	if _, ok := out.Objects[0]["metadata"].(map[string]any)["managedFields"]; ok {
+		t.Errorf("full view kept managed fields")
+	}
+
+	if _, _, err := h.listK8sResources(context.Background(), nil, &listK8sResourcesArgs{Kind: "pods"}); err == nil {
+		t.Errorf("listK8sResources() without a cluster succeeded, want error")
+	}
+}
+
+func TestDescribeK8sResource(t *testing.T) {
+	event := func(name, uid, reason string, last time.Time) *corev1.Event {
+		return &corev1.Event{
+			ObjectMeta:     metav1.ObjectMeta{Namespace: "a", Name: name},
+			InvolvedObject: corev1.ObjectReference{Kind: "Pod", Namespace: "a", Name: "web", UID: types.UID(uid)},
+			Type:           corev1.EventTypeWarning,
+			Reason:         reason,
+			Message:        reason + " happened\n",
+			Count:          2,
+			FirstTimestamp: metav1.NewTime(last.Add(-time.Minute)),
+			LastTimestamp:  metav1.NewTime(last),
+			Source:         corev1.EventSource{Component: "kubelet"},
+		}
+	}
+	t0 := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	h := testKubeHandlers(
+		[]runtime.Object{
+			event("e1", "uid-web", "BackOff", t0.Add(time.Hour)),
+			event("e2", "uid-web", "Pulling", t0),
+			// An event about an older pod with the same name.
+			event("e3", "uid-old", "Killing", t0),
+		},
+		testUnstructured("v1", "Pod", "a", "web", nil),
+	)
+
+	_, out, err := h.describeK8sResource(context.Background(), nil, &getK8sResourceArgs{Cluster: "c", Kind: "pod", Namespace: "a", Name: "web"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []k8sEvent{
+		{Type: "Warning", Reason: "Pulling", Message: "Pulling happened", Count: 2, FirstSeen: "2025-01-01T11:59:00Z", LastSeen: "2025-01-01T12:00:00Z", Source: "kubelet"},
+		{Type: "Warning", Reason: "BackOff", Message: "BackOff happened", Count: 2, FirstSeen: "2025-01-01T12:59:00Z", LastSeen: "2025-01-01T13:00:00Z", Source: "kubelet"},
+	}
+	if diff := cmp.Diff(want, out.Events, cmp.AllowUnexported(k8sEvent{}), cmp.FilterPath(func(p cmp.Path) bool {
+		return p.Last().String() == ".lastSeen"
+	}, cmp.Ignore())); diff != "" {
+		t.Errorf("describeK8sResource() events mismatch (-want +got):\n%s", diff)
+	}
+
+	if _, _, err := h.getK8sResource(context.Background(), nil, &getK8sResourceArgs{Cluster: "c", Kind: "pod", Name: "web"}); err == nil {
+		t.Errorf("getK8sResource() of a pod in the wrong namespace succeeded, want error")
+	}
+}
+
+func TestTrimObject(t *testing.T) {
+	secret := testUnstructured("v1", "Secret", "a", "token", nil)
+	secret.SetAnnotations(map[string]string{
+		corev1.LastAppliedConfigAnnotation: `{"data":{"token":"c2VjcmV0"}}`,
+		"owner":                            "team-a",
+	})
+	secret.Object["data"] = map[string]any{"token": "c2VjcmV0"}
+	secret.Object["stringData"] = map[string]any{"password": "hunter2"}
+	configMap := testUnstructured("v1", "ConfigMap", "a", "settings", nil)
+	configMap.Object["data"] = map[string]any{"mode": "fast"}
+
+	tests := []struct {
+		name            string
+		u               *unstructured.Unstructured
+		showSecrets     bool
+		wantData        map[string]any
+		wantStringData  map[string]any
+		wantAnnotations map[string]string
+	}{
+		{
+			name:            "secret",
+			u:               secret,
+			wantData:        map[string]any{"token": "<redacted: 6 bytes>"},
+			wantStringData:  map[string]any{"password": "<redacted: 7 bytes>"},
+			wantAnnotations: map[string]string{"owner": "team-a"},
+		},
+		{
+			name:           "secret shown",
+			u:              secret,
+			showSecrets:    true,
+			wantData:       map[string]any{"token": "c2VjcmV0"},
+			wantStringData: map[string]any{"password": "hunter2"},
+			wantAnnotations: map[string]string{
+				corev1.LastAppliedConfigAnnotation: `{"data":{"token":"c2VjcmV0"}}`,
+				"owner":                            "team-a",
+			},
+		},
+		{
+			name:     "config map",
+			u:        configMap,
+			wantData: map[string]any{"mode": "fast"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := &unstructured.Unstructured{Object: trimObject(tt.u, tt.showSecrets)}
+			if got.GetManagedFields() != nil {
+				t.Errorf("trimObject() kept managed fields")
+			}
+			if diff := cmp.Diff(tt.wantData, got.Object["data"]); diff != "" {
+				t.Errorf("trimObject() data mismatch (-want +got):\n%s", diff)
+			}
+			if tt.wantStringData != nil {
+				if diff := cmp.Diff(tt.wantStringData, got.Object["stringData"]); diff != "" {
+					t.Errorf("trimObject() stringData mismatch (-want +got):\n%s", diff)
+				}
+			}
+			if diff := cmp.Diff(tt.wantAnnotations, got.GetAnnotations()); diff != "" {
+				t.Errorf("trimObject() annotations mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if got := secret.Object["data"].(map[string]any)["token"]; got != "c2VjcmV0" {
+		t.Errorf("trimObject() changed its argument's data to %q", got)
+	}
+}
+
+func TestGetPodLogs(t *testing.T) {
+	h := testKubeHandlers([]runtime.Object{&corev1.Pod{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "web"}}})
+	tests := []struct {
+		name          string
+		args          getPodLogsArgs
+		wantLogs      string
+		wantTruncated bool
+		wantErr       bool
+	}{
+		// The fake clientset always returns "fake logs".
+		{name: "logs", args: getPodLogsArgs{Pod: "web", Since: "10m", Previous: true}, wantLogs: "fake logs"},
+		{name: "truncated", args: getPodLogsArgs{Pod: "web", LimitBytes: 4}, wantLogs: "fake", wantTruncated: true},
+		{name: "invalid since", args: getPodLogsArgs{Pod: "web", Since: "yesterday"}, wantErr: true},
+		{name: "no pod", args: getPodLogsArgs{}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.Cluster = "c"
+			res, out, err := h.getPodLogs(context.Background(), nil, &tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("getPodLogs() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if out.Logs != tt.wantLogs || out.Truncated != tt.wantTruncated {
+				t.Errorf("getPodLogs() = %q, truncated %v, want %q, truncated %v", out.Logs, out.Truncated, tt.wantLogs, tt.wantTruncated)
+			}
+			if got := res.Content[len(res.Content)-1].(*mcp.TextContent).Text; got != tt.wantLogs {
+				t.Errorf("getPodLogs() text content = %q, want %q", got, tt.wantLogs)
+			}
+		})
+	}
+}
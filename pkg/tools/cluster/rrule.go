@@ -0,0 +1,254 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// rrule is a parsed RFC 5545 recurrence rule, limited to the parts GKE
+// maintenance windows use: daily, weekly and monthly frequencies filtered by
+// weekday or day of the month.
+type rrule struct {
+	freq     string
+	interval int
+	// byDay holds the weekdays to repeat on. For monthly rules, a non-zero
+	// ordinal selects e.g. the first (1) or last (-1) such weekday.
+	byDay      []rruleDay
+	byMonthDay []int
+	weekStart  time.Weekday
+	count      int
+	until      time.Time
+}
+
+type rruleDay struct {
+	ordinal int
+	weekday time.Weekday
+}
+
+// rruleHorizon bounds how far past the start of a rule occurrences are
+// searched for.
+const rruleHorizon = 10 * 366 * 24 * time.Hour
+
+var (
+	rruleWeekdays = map[string]time.Weekday{
+		"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+		"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+	}
+	rruleDayRE = regexp.MustCompile(`^([+-]?\d{1,2})?(SU|MO|TU|WE|TH|FR|SA)$`)
+)
+
+func parseRRule(s string) (*rrule, error) {
+	r := &rrule{interval: 1, weekStart: time.Monday}
+	s = strings.TrimPrefix(strings.TrimSpace(s), "RRULE:")
+	if s == "" {
+		return nil, fmt.Errorf("recurrence cannot be empty")
+	}
+	seen := map[string]bool{}
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("invalid recurrence part %q, want KEY=VALUE", part)
+		}
+		key = strings.ToUpper(key)
+		value = strings.ToUpper(value)
+		if seen[key] {
+			return nil, fmt.Errorf("recurrence has more than one %s", key)
+		}
+		seen[key] = true
+		var err error
+		switch key {
+		case "FREQ":
+			if value != "DAILY" && value != "WEEKLY" && value != "MONTHLY" {
+				return nil, fmt.Errorf("unsupported FREQ %q, must be DAILY, WEEKLY or MONTHLY", value)
+			}
+			r.freq = value
+		case "INTERVAL":
+			r.interval, err = strconv.Atoi(value)
+			if err != nil || r.interval < 1 {
+				return nil, fmt.Errorf("invalid INTERVAL %q, must be a positive number", value)
+			}
+		case "COUNT":
+			r.count, err = strconv.Atoi(value)
+			if err != nil || r.count < 1 {
+				return nil, fmt.Errorf("invalid COUNT %q, must be a positive number", value)
+			}
+		case "UNTIL":
+			r.until, err = parseRRuleTime(value)
+			if err != nil {
+				return nil, err
+			}
+		case "BYDAY":
+			for _, d := range strings.Split(value, ",") {
+				m := rruleDayRE.FindStringSubmatch(d)
+				if m == nil {
+					return nil, fmt.Errorf("invalid BYDAY value %q, want a weekday like MO, optionally with an ordinal like 1MO or -1FR", d)
+				}
+				day := rruleDay{weekday: rruleWeekdays[m[2]]}
+				if m[1] != "" {
+					day.ordinal, _ = strconv.Atoi(m[1])
+					if day.ordinal == 0 || day.ordinal < -5 || day.ordinal > 5 {
+						return nil, fmt.Errorf("invalid BYDAY ordinal in %q, must be between 1 and 5 or -5 and -1", d)
+					}
+				}
+				r.byDay = append(r.byDay, day)
+			}
+		case "BYMONTHDAY":
+			for _, d := range strings.Split(value, ",") {
+				n, err := strconv.Atoi(d)
+				if err != nil || n == 0 || n < -31 || n > 31 {
+					return nil, fmt.Errorf("invalid BYMONTHDAY value %q, must be between 1 and 31 or -31 and -1", d)
+				}
+				r.byMonthDay = append(r.byMonthDay, n)
+			}
+		case "WKST":
+			wd, ok := rruleWeekdays[value]
+			if !ok {
+				return nil, fmt.Errorf("invalid WKST %q, want a weekday like MO", value)
+			}
+			r.weekStart = wd
+		default:
+			return nil, fmt.Errorf("unsupported recurrence part %s, only FREQ, INTERVAL, COUNT, UNTIL, BYDAY, BYMONTHDAY and WKST are supported", key)
+		}
+	}
+
+	if r.freq == "" {
+		return nil, fmt.Errorf("recurrence must have a FREQ")
+	}
+	if r.count > 0 && !r.until.IsZero() {
+		return nil, fmt.Errorf("recurrence can't have both COUNT and UNTIL")
+	}
+	if r.freq != "MONTHLY" {
+		if len(r.byMonthDay) > 0 && r.freq == "WEEKLY" {
+			return nil, fmt.Errorf("BYMONTHDAY can't be used with FREQ=WEEKLY")
+		}
+		for _, d := range r.byDay {
+			if d.ordinal != 0 {
+				return nil, fmt.Errorf("BYDAY ordinals like 1MO can only be used with FREQ=MONTHLY")
+			}
+		}
+	}
+	return r, nil
+}
+
+func parseRRuleTime(s string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q, want a UTC date like 20250131 or 20250131T000000Z", s)
+}
+
+// occurrences returns up to n start times of the rule that are at or after
+// from, with start as the first occurrence. It stops early when the rule
+// ends or no occurrence is found within rruleHorizon.
+func (r *rrule) occurrences(start, from time.Time, n int) []time.Time {
+	var out []time.Time
+	start = start.UTC()
+	end := start.Add(rruleHorizon)
+	if from.After(start) {
+		end = from.Add(rruleHorizon)
+	}
+	count := 0
+	for day := start; len(out) < n && !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !r.until.IsZero() && day.After(r.until) {
+			break
+		}
+		if !r.matches(start, day) {
+			continue
+		}
+		count++
+		if r.count > 0 && count > r.count {
+			break
+		}
+		if !day.Before(from) {
+			out = append(out, day)
+		}
+	}
+	return out
+}
+
+// matches reports whether the rule has an occurrence on day, which has the
+// same time of day as start and isn't before it.
+func (r *rrule) matches(start, day time.Time) bool {
+	switch r.freq {
+	case "DAILY":
+		days := int(day.Sub(start).Hours()+12) / 24
+		if days%r.interval != 0 {
+			return false
+		}
+	case "WEEKLY":
+		weeks := int(weekStart(day, r.weekStart).Sub(weekStart(start, r.weekStart)).Hours()+12) / (24 * 7)
+		if weeks%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 {
+			return day.Weekday() == start.Weekday()
+		}
+	case "MONTHLY":
+		months := (day.Year()-start.Year())*12 + int(day.Month()) - int(start.Month())
+		if months%r.interval != 0 {
+			return false
+		}
+		if len(r.byDay) == 0 && len(r.byMonthDay) == 0 {
+			return day.Day() == start.Day()
+		}
+	}
+	if len(r.byMonthDay) > 0 && !slices.ContainsFunc(r.byMonthDay, func(d int) bool { return monthDayMatches(day, d) }) {
+		return false
+	}
+	if len(r.byDay) > 0 && !slices.ContainsFunc(r.byDay, func(d rruleDay) bool { return d.matches(day) }) {
+		return false
+	}
+	return true
+}
+
+func (d rruleDay) matches(day time.Time) bool {
+	if day.Weekday() != d.weekday {
+		return false
+	}
+	switch {
+	case d.ordinal > 0:
+		return (day.Day()-1)/7+1 == d.ordinal
+	case d.ordinal < 0:
+		return (daysInMonth(day)-day.Day())/7+1 == -d.ordinal
+	}
+	return true
+}
+
+// monthDayMatches reports whether day is the given day of its month,
+// counting from the end of the month if it's negative.
+func monthDayMatches(day time.Time, monthDay int) bool {
+	if monthDay < 0 {
+		monthDay = daysInMonth(day) + monthDay + 1
+	}
+	return day.Day() == monthDay
+}
+
+func daysInMonth(t time.Time) int {
+	return time.Date(t.Year(), t.Month()+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// weekStart returns midnight of the first day of t's week.
+func weekStart(t time.Time, first time.Weekday) time.Time {
+	offset := (int(t.Weekday()) - int(first) + 7) % 7
+	return time.Date(t.Year(), t.Month(), t.Day()-offset, 0, 0, 0, 0, time.UTC)
+}
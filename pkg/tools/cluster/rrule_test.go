@@ -0,0 +1,146 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestParseRRuleErrors(t *testing.T) {
+	for _, s := range []string{
+		"",
+		"BYDAY=MO",
+		"FREQ=YEARLY",
+		"FREQ=HOURLY",
+		"FREQ=DAILY;FREQ=WEEKLY",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=1MO",
+		"FREQ=MONTHLY;BYDAY=6MO",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=WEEKLY;BYMONTHDAY=1",
+		"FREQ=DAILY;INTERVAL=0",
+		"FREQ=DAILY;COUNT=3;UNTIL=20250101",
+		"FREQ=DAILY;UNTIL=tomorrow",
+		"FREQ=DAILY;BYHOUR=3",
+		"FREQ",
+	} {
+		if _, err := parseRRule(s); err == nil {
+			t.Errorf("parseRRule(%q) succeeded, want error", s)
+		}
+	}
+}
+
+func TestRRuleOccurrences(t *testing.T) {
+	// A Saturday.
+	start := time.Date(2025, 1, 4, 2, 0, 0, 0, time.UTC)
+	tests := []struct {
+		rule string
+		from time.Time
+		n    int
+		want []string
+	}{
+		{
+			rule: "FREQ=DAILY",
+			from: time.Date(2025, 3, 1, 3, 0, 0, 0, time.UTC),
+			n:    2,
+			want: []string{"2025-03-02T02:00:00Z", "2025-03-03T02:00:00Z"},
+		},
+		{
+			rule: "RRULE:FREQ=DAILY;INTERVAL=3",
+			from: start,
+			n:    3,
+			want: []string{"2025-01-04T02:00:00Z", "2025-01-07T02:00:00Z", "2025-01-10T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=WEEKLY;BYDAY=SA,SU",
+			from: start.Add(time.Hour),
+			n:    3,
+			want: []string{"2025-01-05T02:00:00Z", "2025-01-11T02:00:00Z", "2025-01-12T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=WEEKLY",
+			from: start,
+			n:    2,
+			want: []string{"2025-01-04T02:00:00Z", "2025-01-11T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			from: start,
+			n:    3,
+			// The week of the start has no Tuesday left, but it's the first
+			// week of the rule.
+			want: []string{"2025-01-14T02:00:00Z", "2025-01-28T02:00:00Z", "2025-02-11T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYDAY=1SU",
+			from: start,
+			n:    3,
+			want: []string{"2025-01-05T02:00:00Z", "2025-02-02T02:00:00Z", "2025-03-02T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYDAY=-1FR",
+			from: start,
+			n:    2,
+			want: []string{"2025-01-31T02:00:00Z", "2025-02-28T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=MONTHLY;BYMONTHDAY=-1",
+			from: start,
+			n:    3,
+			want: []string{"2025-01-31T02:00:00Z", "2025-02-28T02:00:00Z", "2025-03-31T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=MONTHLY",
+			from: start,
+			n:    2,
+			want: []string{"2025-01-04T02:00:00Z", "2025-02-04T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=DAILY;COUNT=5",
+			from: time.Date(2025, 1, 7, 0, 0, 0, 0, time.UTC),
+			n:    5,
+			want: []string{"2025-01-07T02:00:00Z", "2025-01-08T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=DAILY;UNTIL=20250106T020000Z",
+			from: start,
+			n:    5,
+			want: []string{"2025-01-04T02:00:00Z", "2025-01-05T02:00:00Z", "2025-01-06T02:00:00Z"},
+		},
+		{
+			rule: "FREQ=DAILY;COUNT=2",
+			from: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			n:    1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.rule, func(t *testing.T) {
+			r, err := parseRRule(tt.rule)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got []string
+			for _, o := range r.occurrences(start, tt.from, tt.n) {
+				got = append(got, o.Format(time.RFC3339))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("occurrences() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
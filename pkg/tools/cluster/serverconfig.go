@@ -0,0 +1,173 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type getServerConfigArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster,omitempty" jsonschema:"Only return versions newer than this cluster's current control plane version, e.g. to choose an upgrade target."`
+}
+
+type channelVersions struct {
+	Channel              string   `json:"channel"`
+	DefaultVersion       string   `json:"default_version,omitempty"`
+	UpgradeTargetVersion string   `json:"upgrade_target_version,omitempty" jsonschema:"Version clusters in the channel are automatically upgraded to."`
+	ValidVersions        []string `json:"valid_versions,omitempty"`
+}
+
+type getServerConfigOutput struct {
+	ProjectID             string            `json:"project_id"`
+	Location              string            `json:"location"`
+	Cluster               string            `json:"cluster,omitempty"`
+	ClusterVersion        string            `json:"cluster_version,omitempty" jsonschema:"Current control plane version of the cluster; only newer versions are listed."`
+	ClusterChannel        string            `json:"cluster_channel,omitempty" jsonschema:"Release channel of the cluster, empty if it isn't enrolled in one."`
+	DefaultClusterVersion string            `json:"default_cluster_version,omitempty"`
+	DefaultImageType      string            `json:"default_image_type,omitempty"`
+	ValidMasterVersions   []string          `json:"valid_master_versions,omitempty"`
+	ValidNodeVersions     []string          `json:"valid_node_versions,omitempty"`
+	ValidImageTypes       []string          `json:"valid_image_types,omitempty"`
+	Channels              []channelVersions `json:"channels,omitempty"`
+}
+
+// installServerConfig registers the get_server_config tool.
+func (h *handlers) installServerConfig(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "get_server_config",
+		Description: "Get the GKE versions available in a location: default and valid control plane and node versions, and the versions of each release channel. Pass a cluster to only list versions it can be upgraded to. Prefer to use this tool instead of gcloud container get-server-config",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.getServerConfig)
+}
+
+func (h *handlers) getServerConfig(ctx context.Context, _ *mcp.CallToolRequest, args *getServerConfigArgs) (*mcp.CallToolResult, *getServerConfigOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+
+	var cluster *containerpb.Cluster
+	var sc *containerpb.ServerConfig
+	var err error
+	if args.Cluster != "" {
+		name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+		if err != nil {
+			return nil, nil, err
+		}
+		cluster, sc, err = h.clusterAndServerConfig(ctx, name, args.ProjectID, args.Location)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
+		sc, err = h.cmClient.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{
+			Name: fmt.Sprintf("projects/%s/locations/%s", args.ProjectID, args.Location),
+		})
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	out, err := newServerConfigOutput(sc, cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	out.ProjectID = args.ProjectID
+	out.Location = args.Location
+
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+// newServerConfigOutput describes sc. If cluster isn't nil, only versions
+// newer than its control plane version are kept.
+func newServerConfigOutput(sc *containerpb.ServerConfig, cluster *containerpb.Cluster) (*getServerConfigOutput, error) {
+	keep := func(versions []string) []string { return versions }
+	out := &getServerConfigOutput{
+		DefaultClusterVersion: sc.GetDefaultClusterVersion(),
+		DefaultImageType:      sc.GetDefaultImageType(),
+		ValidImageTypes:       sc.GetValidImageTypes(),
+	}
+	if cluster != nil {
+		current, err := parseGKEVersion(cluster.GetCurrentMasterVersion())
+		if err != nil {
+			return nil, fmt.Errorf("cluster %s: %w", cluster.GetName(), err)
+		}
+		keep = func(versions []string) []string { return newerVersions(versions, current) }
+		info := newClusterInfo(cluster)
+		out.Cluster = info.Name
+		out.ClusterVersion = info.CurrentMasterVersion
+		out.ClusterChannel = info.ReleaseChannel
+	}
+
+	out.ValidMasterVersions = keep(sc.GetValidMasterVersions())
+	out.ValidNodeVersions = keep(sc.GetValidNodeVersions())
+	for _, c := range sc.GetChannels() {
+		out.Channels = append(out.Channels, channelVersions{
+			Channel:              c.GetChannel().String(),
+			DefaultVersion:       c.GetDefaultVersion(),
+			UpgradeTargetVersion: c.GetUpgradeTargetVersion(),
+			ValidVersions:        keep(c.GetValidVersions()),
+		})
+	}
+	return out, nil
+}
+
+// newerVersions returns the versions newer than v, skipping any that can't
+// be parsed.
+func newerVersions(versions []string, v gkeVersion) []string {
+	var newer []string
+	for _, s := range versions {
+		if w, err := parseGKEVersion(s); err == nil && w.compare(v) > 0 {
+			newer = append(newer, s)
+		}
+	}
+	return newer
+}
+
+func (o *getServerConfigOutput) summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "GKE versions in %s (project %s), default %s.", o.Location, o.ProjectID, o.DefaultClusterVersion)
+	if o.Cluster != "" {
+		channel := o.ClusterChannel
+		if channel == "" {
+			channel = "no"
+		}
+		fmt.Fprintf(&b, "\nOnly versions newer than cluster %s's control plane version %s (%s release channel) are listed.", o.Cluster, o.ClusterVersion, channel)
+	}
+	for _, c := range o.Channels {
+		fmt.Fprintf(&b, "\n%s channel: default %s, upgrade target %s, valid: %s", c.Channel, c.DefaultVersion, c.UpgradeTargetVersion, versionList(c.ValidVersions))
+	}
+	fmt.Fprintf(&b, "\nValid control plane versions: %s", versionList(o.ValidMasterVersions))
+	return b.String()
+}
+
+func versionList(versions []string) string {
+	if len(versions) == 0 {
+		return "none"
+	}
+	return strings.Join(versions, ", ")
+}
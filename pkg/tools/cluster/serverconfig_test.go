@@ -0,0 +1,47 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestNewServerConfigOutput(t *testing.T) {
+	all, err := newServerConfigOutput(testServerConfig, nil)
+	if err != nil {
+		t.Fatalf("newServerConfigOutput() error = %v", err)
+	}
+	if diff := cmp.Diff(testServerConfig.GetValidNodeVersions(), all.ValidNodeVersions); diff != "" {
+		t.Errorf("ValidNodeVersions without a cluster mismatch (-want +got):\n%s", diff)
+	}
+
+	newer, err := newServerConfigOutput(testServerConfig, testUpgradeCluster("1.32.9-gke.500"))
+	if err != nil {
+		t.Fatalf("newServerConfigOutput() error = %v", err)
+	}
+	want := &getServerConfigOutput{
+		Cluster:             "prod",
+		ClusterVersion:      "1.32.9-gke.500",
+		ClusterChannel:      "REGULAR",
+		ValidMasterVersions: []string{"1.34.1-gke.100", "1.33.5-gke.1000"},
+		ValidNodeVersions:   []string{"1.34.1-gke.100", "1.33.5-gke.1000"},
+		Channels:            []channelVersions{{Channel: "REGULAR", ValidVersions: []string{"1.33.5-gke.1000"}}},
+	}
+	if diff := cmp.Diff(want, newer); diff != "" {
+		t.Errorf("newServerConfigOutput() with cluster mismatch (-want +got):\n%s", diff)
+	}
+}
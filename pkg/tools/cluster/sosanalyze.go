@@ -0,0 +1,833 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ulikunitz/xz"
+)
+
+const (
+	severityCritical = "critical"
+	severityWarning  = "warning"
+	severityInfo     = "info"
+
+	sosCategoryKubelet       = "kubelet"
+	sosCategoryContainerd    = "containerd"
+	sosCategoryOOM           = "oom"
+	sosCategoryDiskPressure  = "disk_pressure"
+	sosCategoryInodePressure = "inode_pressure"
+	sosCategoryKernelTaint   = "kernel_taint"
+	sosCategorySystemd       = "systemd"
+	sosCategoryNetwork       = "network"
+
+	defaultSosMaxFindings = 10
+	maxSosMaxFindings     = 100
+	// maxSosLogKeys bounds the distinct messages tracked per log category.
+	maxSosLogKeys = 1000
+	// maxSosLineBytes is the longest log line that is scanned; longer lines
+	// end the scan of their file.
+	maxSosLineBytes = 1 << 20
+
+	defaultSosFileBytes = 64 << 10
+	maxSosFileBytes     = 1 << 20
+	maxSosListedFiles   = 1000
+	maxSosSymlinkHops   = 5
+
+	sosUsageWarningPercent  = 85
+	sosUsageCriticalPercent = 95
+)
+
+type analyzeSosReportArgs struct {
+	Path        string `json:"path" jsonschema:"Local path of a SOS report archive (.tar.xz, .tar.gz or .tar), like one downloaded by get_node_sos_report."`
+	MaxFindings int    `json:"max_findings,omitempty" jsonschema:"Most log findings to return per category, most frequent first. Defaults to 10, at most 100."`
+}
+
+type readSosReportFileArgs struct {
+	Path     string `json:"path" jsonschema:"Local path of a SOS report archive (.tar.xz, .tar.gz or .tar)."`
+	File     string `json:"file,omitempty" jsonschema:"Path of the file inside the report, relative to its top directory, like 'sos_commands/kernel/dmesg'. Leave it empty or end it with '/' to list the files of a directory instead."`
+	MaxBytes int    `json:"max_bytes,omitempty" jsonschema:"Most bytes of the file to return. Defaults to 65536, at most 1048576."`
+	Tail     bool   `json:"tail,omitempty" jsonschema:"Return the end of the file instead of its beginning when it is larger than max_bytes. Useful for logs."`
+}
+
+type analyzeSosReportOutput struct {
+	Path      string       `json:"path"`
+	Hostname  string       `json:"hostname,omitempty"`
+	Kernel    string       `json:"kernel,omitempty" jsonschema:"The uname -a output of the node."`
+	LogSource string       `json:"log_source,omitempty" jsonschema:"The log of the report that kubelet, containerd and kernel messages were read from."`
+	Findings  []sosFinding `json:"findings,omitempty" jsonschema:"Suspected problems, most severe first."`
+	Unchecked []string     `json:"unchecked,omitempty" jsonschema:"Categories that couldn't be checked because the report lacks their files."`
+}
+
+type sosFinding struct {
+	Category string `json:"category" jsonschema:"One of kubelet, containerd, oom, disk_pressure, inode_pressure, kernel_taint, systemd or network."`
+	Severity string `json:"severity" jsonschema:"critical, warning or info."`
+	Summary  string `json:"summary"`
+	Count    int    `json:"count,omitempty" jsonschema:"How many log lines reported it."`
+	Example  string `json:"example,omitempty" jsonschema:"One of the log lines that reported it."`
+	Source   string `json:"source,omitempty" jsonschema:"The file of the report it was found in, for read_sos_report_file."`
+}
+
+type readSosReportFileOutput struct {
+	Path      string          `json:"path"`
+	File      string          `json:"file,omitempty" jsonschema:"The file that was read, after following symbolic links, or the listed directory."`
+	Size      int64           `json:"size,omitempty" jsonschema:"Size of the whole file in bytes."`
+	Content   string          `json:"content,omitempty"`
+	Truncated bool            `json:"truncated,omitempty" jsonschema:"Whether the file was larger than max_bytes, or the directory had too many files to list."`
+	Files     []sosReportFile `json:"files,omitempty"`
+}
+
+type sosReportFile struct {
+	Name string `json:"name"`
+	Size int64  `json:"size,omitempty"`
+	Link string `json:"link,omitempty" jsonschema:"The target of a symbolic link."`
+}
+
+func (h *handlers) installSosAnalyzer(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "analyze_sos_report",
+		Description: "Triage a local SOS report archive of a GKE node, like one downloaded by get_node_sos_report, without extracting it. " +
+			"Reports kubelet and containerd errors from the journal, OOM-killer events, disk and inode pressure, kernel taints, failed systemd units and NIC errors, most severe first. " +
+			"Use read_sos_report_file to look at the files a finding comes from.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.analyzeSosReport)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "read_sos_report_file",
+		Description: "Read one file of a local SOS report archive by its path inside the report, or list the files of a directory of the report. Large files are truncated to max_bytes.",
+		Annotations: &mcp.ToolAnnotations{
+			ReadOnlyHint: true,
+		},
+	}, h.readSosReportFile)
+}
+
+func (h *handlers) analyzeSosReport(ctx context.Context, _ *mcp.CallToolRequest, args *analyzeSosReportArgs) (*mcp.CallToolResult, *analyzeSosReportOutput, error) {
+	if args.Path == "" {
+		return nil, nil, fmt.Errorf("path argument cannot be empty")
+	}
+	if args.MaxFindings < 0 || args.MaxFindings > maxSosMaxFindings {
+		return nil, nil, fmt.Errorf("max_findings must be between 0 and %d", maxSosMaxFindings)
+	}
+	if args.MaxFindings == 0 {
+		args.MaxFindings = defaultSosMaxFindings
+	}
+
+	a := newSosAnalyzer()
+	if err := walkSosArchive(ctx, args.Path, a.scan); err != nil {
+		return nil, nil, err
+	}
+	out := a.result(args.MaxFindings)
+	out.Path = args.Path
+
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+func (h *handlers) readSosReportFile(ctx context.Context, _ *mcp.CallToolRequest, args *readSosReportFileArgs) (*mcp.CallToolResult, *readSosReportFileOutput, error) {
+	if args.Path == "" {
+		return nil, nil, fmt.Errorf("path argument cannot be empty")
+	}
+	if args.MaxBytes < 0 || args.MaxBytes > maxSosFileBytes {
+		return nil, nil, fmt.Errorf("max_bytes must be between 0 and %d", maxSosFileBytes)
+	}
+	if args.MaxBytes == 0 {
+		args.MaxBytes = defaultSosFileBytes
+	}
+	file := strings.TrimPrefix(args.File, "/")
+
+	out := &readSosReportFileOutput{Path: args.Path, File: file}
+	if file == "" || strings.HasSuffix(file, "/") {
+		files, truncated, err := listSosFiles(ctx, args.Path, file)
+		if err != nil {
+			return nil, nil, err
+		}
+		out.Files, out.Truncated = files, truncated
+		summary := fmt.Sprintf("%d files under %s in %s.", len(files), valueOrDash(file), args.Path)
+		if truncated {
+			summary += fmt.Sprintf(" Only the first %d are listed.", maxSosListedFiles)
+		}
+		res, err := toolresult.New(summary, out)
+		return res, out, err
+	}
+
+	name := path.Clean(file)
+	for hops := 0; ; hops++ {
+		hdr, content, truncated, err := readSosFile(ctx, args.Path, name, int64(args.MaxBytes), args.Tail)
+		if err != nil {
+			return nil, nil, err
+		}
+		if hdr.Typeflag == tar.TypeSymlink {
+			// sos links well-known files like "uname" to the output of the
+			// command that produced them.
+			if hops == maxSosSymlinkHops {
+				return nil, nil, fmt.Errorf("too many symbolic links resolving %s", file)
+			}
+			name = path.Clean(path.Join(path.Dir(name), hdr.Linkname))
+			continue
+		}
+		if hdr.Typeflag == tar.TypeDir {
+			return nil, nil, fmt.Errorf("%s is a directory, end it with '/' to list its files", file)
+		}
+		out.File, out.Size, out.Content, out.Truncated = name, hdr.Size, content, truncated
+		break
+	}
+
+	summary := fmt.Sprintf("Read %s (%d bytes) from %s", out.File, out.Size, args.Path)
+	if out.Truncated {
+		summary += fmt.Sprintf(", truncated to %d bytes", args.MaxBytes)
+	}
+	summary += ". The content follows."
+	return toolresult.NewText(summary, out.Content), out, nil
+}
+
+// errStopSosWalk ends walkSosArchive early without an error.
+var errStopSosWalk = errors.New("stop walking the SOS report")
+
+// walkSosArchive calls fn with each entry of the SOS report archive at
+// archivePath. Names are relative to the report's top directory, like
+// "sos_commands/kernel/dmesg". The archive is streamed, never extracted.
+func walkSosArchive(ctx context.Context, archivePath string, fn func(name string, hdr *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	r, err := decompressSosArchive(bufio.NewReader(f))
+	if err != nil {
+		return fmt.Errorf("failed to open SOS report %s: %w", archivePath, err)
+	}
+	tr := tar.NewReader(r)
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("failed to read SOS report %s: %w", archivePath, err)
+		}
+		name := sosEntryName(hdr.Name)
+		if name == "" {
+			continue
+		}
+		if err := fn(name, hdr, tr); err != nil {
+			if errors.Is(err, errStopSosWalk) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+// decompressSosArchive detects the compression of a tar archive from its
+// magic bytes; sos writes xz by default and gzip with -z gzip.
+func decompressSosArchive(r *bufio.Reader) (io.Reader, error) {
+	magic, _ := r.Peek(6)
+	switch {
+	case bytes.HasPrefix(magic, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00}):
+		return xz.NewReader(r)
+	case bytes.HasPrefix(magic, []byte{0x1f, 0x8b}):
+		return gzip.NewReader(r)
+	default:
+		return r, nil
+	}
+}
+
+// sosEntryName strips the report's top directory, like
+// "sosreport-node-2025-01-01-abcdef/", from an archive entry name.
+func sosEntryName(name string) string {
+	name = strings.TrimPrefix(path.Clean("/"+name), "/")
+	_, rest, ok := strings.Cut(name, "/")
+	if !ok {
+		return ""
+	}
+	return rest
+}
+
+// readSosFile returns the header and up to maxBytes of the content of the
+// archive entry called name.
+func readSosFile(ctx context.Context, archivePath, name string, maxBytes int64, tail bool) (*tar.Header, string, bool, error) {
+	var found *tar.Header
+	var content []byte
+	var truncated bool
+	err := walkSosArchive(ctx, archivePath, func(n string, hdr *tar.Header, r io.Reader) error {
+		if n != name {
+			return nil
+		}
+		found = hdr
+		var err error
+		content, truncated, err = readLimited(r, maxBytes, tail)
+		if err != nil {
+			return err
+		}
+		return errStopSosWalk
+	})
+	if err != nil {
+		return nil, "", false, err
+	}
+	if found == nil {
+		return nil, "", false, fmt.Errorf("file %s not found in SOS report %s", name, archivePath)
+	}
+	return found, string(content), truncated, nil
+}
+
+// readLimited reads up to maxBytes of r, from its end if tail is set, and
+// whether r held more.
+func readLimited(r io.Reader, maxBytes int64, tail bool) ([]byte, bool, error) {
+	if !tail {
+		b, err := io.ReadAll(io.LimitReader(r, maxBytes+1))
+		if err != nil {
+			return nil, false, err
+		}
+		if int64(len(b)) > maxBytes {
+			return b[:maxBytes], true, nil
+		}
+		return b, false, nil
+	}
+	// Keep a window of the last maxBytes read.
+	var buf []byte
+	chunk := make([]byte, 32<<10)
+	truncated := false
+	for {
+		n, err := r.Read(chunk)
+		buf = append(buf, chunk[:n]...)
+		if int64(len(buf)) > 2*maxBytes {
+			buf = append(buf[:0], buf[int64(len(buf))-maxBytes:]...)
+			truncated = true
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, false, err
+		}
+	}
+	if int64(len(buf)) > maxBytes {
+		buf = buf[int64(len(buf))-maxBytes:]
+		truncated = true
+	}
+	return buf, truncated, nil
+}
+
+// listSosFiles lists the files of the archive under dir, which is empty or
+// ends with "/".
+func listSosFiles(ctx context.Context, archivePath, dir string) ([]sosReportFile, bool, error) {
+	var files []sosReportFile
+	truncated := false
+	err := walkSosArchive(ctx, archivePath, func(name string, hdr *tar.Header, _ io.Reader) error {
+		if !strings.HasPrefix(name, dir) || hdr.Typeflag == tar.TypeDir {
+			return nil
+		}
+		if len(files) == maxSosListedFiles {
+			truncated = true
+			return errStopSosWalk
+		}
+		f := sosReportFile{Name: name, Size: hdr.Size}
+		if hdr.Typeflag == tar.TypeSymlink {
+			f.Size = 0
+			f.Link = hdr.Linkname
+		}
+		files = append(files, f)
+		return nil
+	})
+	return files, truncated, err
+}
+
+// sosLogSources are the logs kubelet, containerd and kernel messages are
+// read from, in order of preference. Only the first one in the report is
+// used so that messages aren't counted twice.
+var sosLogSources = []string{
+	"sos_commands/logs/journalctl_--no-pager",
+	"sos_commands/logs/journalctl_--no-pager_--boot",
+	"var/log/messages",
+	"var/log/syslog",
+}
+
+// sosKernelLog holds kernel messages when there is no other log source.
+const sosKernelLog = "sos_commands/kernel/dmesg"
+
+var (
+	// A syslog or short journalctl line, like
+	// "Jan 02 03:04:05 node-1 kubelet[123]: message".
+	sosLogLineRE = regexp.MustCompile(`^\w{3} +\d+ [\d:]+ \S+ ([^\s\[:]+)(?:\[\d+\])?: (.*)$`)
+	// klogErrorRE matches the header of klog error and fatal messages.
+	klogErrorRE = regexp.MustCompile(`^[EF]\d{4} [\d:.]+ +\d+ [^\]]+\] `)
+	// containerdErrorRE matches logrus errors like 'level=error msg="..."'.
+	containerdErrorRE = regexp.MustCompile(`level=(?:error|fatal)`)
+	logrusMsgRE       = regexp.MustCompile(`msg="((?:[^"\\]|\\.)*)"`)
+	systemOOMRE       = regexp.MustCompile(`(?:^|\] |: )Out of memory: Killed process \d+ \(([^)]*)\)`)
+	cgroupOOMRE       = regexp.MustCompile(`Memory cgroup out of memory: Killed process \d+ \(([^)]*)\)`)
+
+	uuidRE   = regexp.MustCompile(`[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}`)
+	hexIDRE  = regexp.MustCompile(`[0-9a-f]{32,}`)
+	numberRE = regexp.MustCompile(`\d+`)
+)
+
+// sosLogCounts groups the log messages of one category by their normalized
+// text.
+type sosLogCounts struct {
+	source string
+	counts map[string]*sosFinding
+}
+
+func (c *sosLogCounts) add(category, severity, key, summary, example string) {
+	if _, ok := c.counts[key]; !ok && len(c.counts) >= maxSosLogKeys {
+		key, summary = "other", "Other messages"
+	}
+	f, ok := c.counts[key]
+	if !ok {
+		f = &sosFinding{Category: category, Severity: severity, Summary: summary, Example: truncateString(example, 500), Source: c.source}
+		c.counts[key] = f
+	}
+	f.Count++
+}
+
+// sosLogScan holds what was found in one log file.
+type sosLogScan struct {
+	source string
+	byCat  map[string]*sosLogCounts
+}
+
+func (s *sosLogScan) add(category, severity, key, summary, example string) {
+	c, ok := s.byCat[category]
+	if !ok {
+		c = &sosLogCounts{source: s.source, counts: map[string]*sosFinding{}}
+		s.byCat[category] = c
+	}
+	c.add(category, severity, key, summary, example)
+}
+
+type sosAnalyzer struct {
+	hostname string
+	kernel   string
+	logs     map[string]*sosLogScan
+	findings []sosFinding
+	checked  map[string]bool
+	// failedUnits dedups units listed by several systemctl outputs.
+	failedUnits map[string]bool
+	// usage dedups mounts listed by several df outputs.
+	usage map[string]bool
+}
+
+func newSosAnalyzer() *sosAnalyzer {
+	return &sosAnalyzer{
+		logs:        map[string]*sosLogScan{},
+		checked:     map[string]bool{},
+		failedUnits: map[string]bool{},
+		usage:       map[string]bool{},
+	}
+}
+
+// scan feeds one archive entry to the checks interested in it.
+func (a *sosAnalyzer) scan(name string, hdr *tar.Header, r io.Reader) error {
+	if hdr.Typeflag != tar.TypeReg {
+		return nil
+	}
+	base := path.Base(name)
+	var err error
+	switch {
+	case name == "sos_commands/host/hostname" || name == "hostname":
+		a.hostname = firstLine(r)
+	case name == "sos_commands/kernel/uname_-a" || name == "uname":
+		a.kernel = firstLine(r)
+	case name == sosKernelLog || isSosLogSource(name):
+		err = a.scanLog(name, r)
+	case name == "proc/sys/kernel/tainted":
+		a.checked[sosCategoryKernelTaint] = true
+		a.scanTainted(name, r)
+	case strings.HasPrefix(name, "sos_commands/filesys/") && strings.HasPrefix(base, "df_"):
+		category := sosCategoryDiskPressure
+		if strings.Contains(base, "-ali") || strings.Contains(base, "_-i") {
+			category = sosCategoryInodePressure
+		}
+		a.checked[category] = true
+		err = a.scanDf(name, category, r)
+	case strings.HasPrefix(name, "sos_commands/systemd/") && strings.HasPrefix(base, "systemctl_list-units"):
+		a.checked[sosCategorySystemd] = true
+		err = a.scanUnits(name, r)
+	case name == "proc/net/dev":
+		a.checked[sosCategoryNetwork] = true
+		err = a.scanNetDev(name, r)
+	}
+	if err != nil {
+		// One unreadable file shouldn't prevent the triage of the others.
+		log.Printf("Failed to scan %s of SOS report: %v", name, err)
+	}
+	return nil
+}
+
+func isSosLogSource(name string) bool {
+	for _, s := range sosLogSources {
+		if name == s {
+			return true
+		}
+	}
+	return false
+}
+
+func firstLine(r io.Reader) string {
+	line, _ := bufio.NewReader(io.LimitReader(r, 4096)).ReadString('\n')
+	return strings.TrimSpace(line)
+}
+
+func newSosScanner(r io.Reader) *bufio.Scanner {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), maxSosLineBytes)
+	return sc
+}
+
+// scanLog collects kubelet and containerd errors, OOM kills and kubelet
+// evictions of one log file.
+func (a *sosAnalyzer) scanLog(name string, r io.Reader) error {
+	s := &sosLogScan{source: name, byCat: map[string]*sosLogCounts{}}
+	a.logs[name] = s
+	sc := newSosScanner(r)
+	for sc.Scan() {
+		line := sc.Text()
+		if m := systemOOMRE.FindStringSubmatch(line); m != nil {
+			s.add(sosCategoryOOM, severityCritical, "system:"+m[1], fmt.Sprintf("The kernel ran out of memory and killed %s", m[1]), line)
+			continue
+		}
+		if m := cgroupOOMRE.FindStringSubmatch(line); m != nil {
+			s.add(sosCategoryOOM, severityWarning, "cgroup:"+m[1], fmt.Sprintf("%s was killed for exceeding its memory limit", m[1]), line)
+			continue
+		}
+		m := sosLogLineRE.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+		ident, msg := m[1], m[2]
+		switch ident {
+		case "kubelet":
+			if lower := strings.ToLower(msg); strings.Contains(lower, "eviction manager") && strings.Contains(lower, "attempting to reclaim") {
+				category := sosCategoryDiskPressure
+				if strings.Contains(msg, "inodes") {
+					category = sosCategoryInodePressure
+				} else if strings.Contains(msg, "memory") {
+					category = sosCategoryOOM
+				}
+				s.add(category, severityCritical, "eviction:"+category, "The kubelet evicted pods to reclaim resources", line)
+				continue
+			}
+			if loc := klogErrorRE.FindStringIndex(msg); loc != nil {
+				text := msg[loc[1]:]
+				s.add(sosCategoryKubelet, severityWarning, normalizeLogMessage(text), truncateString(text, 200), line)
+			}
+		case "containerd":
+			if containerdErrorRE.MatchString(msg) {
+				text := msg
+				if mm := logrusMsgRE.FindStringSubmatch(msg); mm != nil {
+					text = mm[1]
+				}
+				s.add(sosCategoryContainerd, severityWarning, normalizeLogMessage(text), truncateString(text, 200), line)
+			}
+		}
+	}
+	return sc.Err()
+}
+
+// normalizeLogMessage replaces the IDs and numbers of a log message so
+// that messages differing only in them are grouped together.
+func normalizeLogMessage(msg string) string {
+	msg = uuidRE.ReplaceAllString(msg, "<uuid>")
+	msg = hexIDRE.ReplaceAllString(msg, "<id>")
+	msg = numberRE.ReplaceAllString(msg, "N")
+	return truncateString(msg, 200)
+}
+
+func truncateString(s string, n int) string {
+	if len(s) <= n {
+		return s
+	}
+	return s[:n] + "..."
+}
+
+// kernelTaints describes the bits of /proc/sys/kernel/tainted, see
+// https://docs.kernel.org/admin-guide/tainted-kernels.html.
+var kernelTaints = []struct {
+	flag     string
+	reason   string
+	severity string
+}{
+	{"P", "proprietary module was loaded", severityInfo},
+	{"F", "module was force loaded", severityWarning},
+	{"S", "kernel running on an out of specification system", severityWarning},
+	{"R", "module was force unloaded", severityWarning},
+	{"M", "processor reported a Machine Check Exception", severityCritical},
+	{"B", "bad page referenced or unexpected page flags", severityCritical},
+	{"U", "taint requested by userspace", severityInfo},
+	{"D", "kernel died recently (OOPS or BUG)", severityCritical},
+	{"A", "ACPI table overridden by user", severityInfo},
+	{"W", "kernel issued a warning", severityWarning},
+	{"C", "staging driver was loaded", severityInfo},
+	{"I", "workaround for a platform firmware bug applied", severityInfo},
+	{"O", "externally-built (out-of-tree) module was loaded", severityInfo},
+	{"E", "unsigned module was loaded", severityInfo},
+	{"L", "soft lockup occurred", severityCritical},
+	{"K", "kernel has been live patched", severityInfo},
+	{"X", "auxiliary taint", severityInfo},
+	{"T", "kernel built with the struct randomization plugin", severityInfo},
+	{"N", "in-kernel test has been run", severityInfo},
+}
+
+func (a *sosAnalyzer) scanTainted(name string, r io.Reader) {
+	v, err := strconv.ParseUint(firstLine(r), 10, 64)
+	if err != nil || v == 0 {
+		return
+	}
+	for bit, t := range kernelTaints {
+		if v&(1<<bit) == 0 {
+			continue
+		}
+		a.findings = append(a.findings, sosFinding{
+			Category: sosCategoryKernelTaint,
+			Severity: t.severity,
+			Summary:  fmt.Sprintf("Kernel tainted (%s): %s", t.flag, t.reason),
+			Source:   name,
+		})
+	}
+}
+
+// scanDf reports the file systems of df output, in blocks or inodes, that
+// are almost full.
+func (a *sosAnalyzer) scanDf(name, category string, r io.Reader) error {
+	what := "disk space"
+	if category == sosCategoryInodePressure {
+		what = "inodes"
+	}
+	sc := newSosScanner(r)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		// Filesystem Size Used Avail Use% Mounted on, or the same with inodes.
+		for i, f := range fields {
+			if i == 0 || !strings.HasSuffix(f, "%") || i == len(fields)-1 {
+				continue
+			}
+			pct, err := strconv.Atoi(strings.TrimSuffix(f, "%"))
+			if err != nil {
+				break
+			}
+			mount := strings.Join(fields[i+1:], " ")
+			key := category + " " + mount
+			if pct < sosUsageWarningPercent || a.usage[key] {
+				break
+			}
+			a.usage[key] = true
+			severity := severityWarning
+			if pct >= sosUsageCriticalPercent {
+				severity = severityCritical
+			}
+			a.findings = append(a.findings, sosFinding{
+				Category: category,
+				Severity: severity,
+				Summary:  fmt.Sprintf("%s is %d%% full of %s (%s)", mount, pct, what, fields[0]),
+				Source:   name,
+			})
+			break
+		}
+	}
+	return sc.Err()
+}
+
+// scanUnits reports the failed units of systemctl list-units output.
+func (a *sosAnalyzer) scanUnits(name string, r io.Reader) error {
+	sc := newSosScanner(r)
+	for sc.Scan() {
+		// UNIT LOAD ACTIVE SUB DESCRIPTION, with failed units marked by a dot.
+		fields := strings.Fields(strings.TrimPrefix(strings.TrimSpace(sc.Text()), "●"))
+		if len(fields) < 4 || fields[2] != "failed" || a.failedUnits[fields[0]] {
+			continue
+		}
+		a.failedUnits[fields[0]] = true
+		a.findings = append(a.findings, sosFinding{
+			Category: sosCategorySystemd,
+			Severity: severityWarning,
+			Summary:  fmt.Sprintf("Unit %s failed: %s", fields[0], strings.Join(fields[4:], " ")),
+			Source:   name,
+		})
+	}
+	return sc.Err()
+}
+
+// scanNetDev reports the interfaces of /proc/net/dev with errors or
+// dropped packets.
+func (a *sosAnalyzer) scanNetDev(name string, r io.Reader) error {
+	sc := newSosScanner(r)
+	for sc.Scan() {
+		iface, counters, ok := strings.Cut(sc.Text(), ":")
+		if !ok {
+			continue
+		}
+		fields := strings.Fields(counters)
+		if len(fields) < 12 {
+			continue
+		}
+		n := make([]uint64, 12)
+		for i := range n {
+			n[i], _ = strconv.ParseUint(fields[i], 10, 64)
+		}
+		// Receive bytes packets errs drop fifo frame compressed multicast,
+		// then transmit bytes packets errs drop.
+		rxErrs, rxDrop, txErrs, txDrop := n[2], n[3], n[10], n[11]
+		iface = strings.TrimSpace(iface)
+		switch {
+		case rxErrs > 0 || txErrs > 0:
+			a.findings = append(a.findings, sosFinding{
+				Category: sosCategoryNetwork,
+				Severity: severityWarning,
+				Summary:  fmt.Sprintf("Interface %s has %d receive and %d transmit errors, %d and %d dropped packets", iface, rxErrs, txErrs, rxDrop, txDrop),
+				Source:   name,
+			})
+		case rxDrop > 0 || txDrop > 0:
+			a.findings = append(a.findings, sosFinding{
+				Category: sosCategoryNetwork,
+				Severity: severityInfo,
+				Summary:  fmt.Sprintf("Interface %s dropped %d received and %d transmitted packets", iface, rxDrop, txDrop),
+				Source:   name,
+			})
+		}
+	}
+	return sc.Err()
+}
+
+// result picks the log source, adds its most frequent messages to the
+// findings and sorts them, most severe first.
+func (a *sosAnalyzer) result(maxFindings int) *analyzeSosReportOutput {
+	out := &analyzeSosReportOutput{Hostname: a.hostname, Kernel: a.kernel}
+
+	var logScan *sosLogScan
+	for _, s := range sosLogSources {
+		if l, ok := a.logs[s]; ok {
+			logScan = l
+			break
+		}
+	}
+	dmesg := a.logs[sosKernelLog]
+	if logScan == nil {
+		logScan = dmesg
+	}
+	if logScan != nil {
+		out.LogSource = logScan.source
+		a.checked[sosCategoryKubelet] = logScan != dmesg
+		a.checked[sosCategoryContainerd] = logScan != dmesg
+		a.checked[sosCategoryOOM] = true
+		// Journals may have rotated past OOM kills the ring buffer still has.
+		if _, ok := logScan.byCat[sosCategoryOOM]; !ok && dmesg != nil {
+			if c, ok := dmesg.byCat[sosCategoryOOM]; ok {
+				logScan.byCat[sosCategoryOOM] = c
+			}
+		}
+		for _, c := range logScan.byCat {
+			var fs []sosFinding
+			for _, f := range c.counts {
+				fs = append(fs, *f)
+			}
+			sort.Slice(fs, func(i, j int) bool {
+				if fs[i].Count != fs[j].Count {
+					return fs[i].Count > fs[j].Count
+				}
+				return fs[i].Summary < fs[j].Summary
+			})
+			if len(fs) > maxFindings {
+				fs = fs[:maxFindings]
+			}
+			a.findings = append(a.findings, fs...)
+		}
+	}
+
+	out.Findings = a.findings
+	sort.SliceStable(out.Findings, func(i, j int) bool {
+		fi, fj := out.Findings[i], out.Findings[j]
+		if si, sj := severityRank(fi.Severity), severityRank(fj.Severity); si != sj {
+			return si < sj
+		}
+		if fi.Category != fj.Category {
+			return fi.Category < fj.Category
+		}
+		return fi.Count > fj.Count
+	})
+	for _, category := range []string{sosCategoryKubelet, sosCategoryContainerd, sosCategoryOOM, sosCategoryDiskPressure, sosCategoryInodePressure, sosCategoryKernelTaint, sosCategorySystemd, sosCategoryNetwork} {
+		if !a.checked[category] {
+			out.Unchecked = append(out.Unchecked, category)
+		}
+	}
+	return out
+}
+
+func severityRank(severity string) int {
+	switch severity {
+	case severityCritical:
+		return 0
+	case severityWarning:
+		return 1
+	default:
+		return 2
+	}
+}
+
+func (o *analyzeSosReportOutput) summary() string {
+	var b strings.Builder
+	host := valueOrDash(o.Hostname)
+	fmt.Fprintf(&b, "SOS report %s of host %s", o.Path, host)
+	if o.Kernel != "" {
+		fmt.Fprintf(&b, " (%s)", o.Kernel)
+	}
+	counts := map[string]int{}
+	for _, f := range o.Findings {
+		counts[f.Severity]++
+	}
+	fmt.Fprintf(&b, ": %d critical, %d warning and %d info findings.", counts[severityCritical], counts[severityWarning], counts[severityInfo])
+	if len(o.Findings) > 0 {
+		b.WriteString("\n\n")
+		w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "SEVERITY\tCATEGORY\tCOUNT\tSUMMARY\tSOURCE")
+		for _, f := range o.Findings {
+			count := "-"
+			if f.Count > 0 {
+				count = strconv.Itoa(f.Count)
+			}
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", f.Severity, f.Category, count, f.Summary, f.Source)
+		}
+		_ = w.Flush()
+	}
+	summary := strings.TrimRight(b.String(), "\n")
+	if len(o.Unchecked) > 0 {
+		summary += fmt.Sprintf("\nThe report has no data to check: %s", strings.Join(o.Unchecked, ", "))
+	}
+	return summary
+}
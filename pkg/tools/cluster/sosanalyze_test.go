@@ -0,0 +1,272 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"github.com/ulikunitz/xz"
+)
+
+const sosTopDir = "sosreport-node-1-2025-01-01-abcdef/"
+
+// sosFixture is the content of a SOS report of an unhealthy node.
+var sosFixture = map[string]string{
+	"sos_commands/host/hostname":   "gke-cluster-pool-a-1234\n",
+	"sos_commands/kernel/uname_-a": "Linux gke-cluster-pool-a-1234 6.1.100+ #1 SMP x86_64 GNU/Linux\n",
+	"sos_commands/logs/journalctl_--no-pager": `Jan 01 10:00:00 gke-node kubelet[900]: I0101 10:00:00.000000     900 kubelet.go:100] "Starting kubelet"
+Jan 01 10:00:01 gke-node kubelet[900]: E0101 10:00:01.000000     900 pod_workers.go:1301] "Error syncing pod, skipping" pod="default/web-1"
+Jan 01 10:00:02 gke-node kubelet[900]: E0101 10:00:02.000000     900 pod_workers.go:1301] "Error syncing pod, skipping" pod="default/web-2"
+Jan 01 10:00:03 gke-node kubelet[900]: E0101 10:00:03.000000     900 kubelet_node_status.go:540] "Error updating node status, will retry"
+Jan 01 10:00:04 gke-node containerd[800]: time="2025-01-01T10:00:04Z" level=error msg="failed to pull image 12345" error="not found"
+Jan 01 10:00:05 gke-node containerd[800]: time="2025-01-01T10:00:05Z" level=info msg="pulled image"
+Jan 01 10:00:06 gke-node kernel: Memory cgroup out of memory: Killed process 4321 (java) total-vm:100kB
+Jan 01 10:00:07 gke-node kernel: Out of memory: Killed process 999 (containerd-shim) total-vm:100kB
+Jan 01 10:00:08 gke-node kubelet[900]: I0101 10:00:08.000000     900 eviction_manager.go:366] "Eviction manager: attempting to reclaim" resourceName="ephemeral-storage"
+`,
+	// The same messages again; only the journal should be counted.
+	"var/log/messages": `Jan 01 10:00:03 gke-node kubelet[900]: E0101 10:00:03.000000     900 kubelet_node_status.go:540] "Error updating node status, will retry"
+`,
+	"sos_commands/kernel/dmesg": "[  10.000000] Out of memory: Killed process 999 (containerd-shim)\n",
+	"proc/sys/kernel/tainted":   "2176\n",
+	"sos_commands/filesys/df_-al_-x_autofs": `Filesystem     1K-blocks    Used Available Use% Mounted on
+/dev/sda1       98831908 96000000   2831908  97% /
+/dev/sda8          11760     9400      2360  80% /usr/share/oem
+tmpfs            8192000        0   8192000   0% /dev/shm
+`,
+	"sos_commands/filesys/df_-ali_-x_autofs": `Filesystem      Inodes  IUsed   IFree IUse% Mounted on
+/dev/sda1      6291456 5600000 691456   89% /
+proc                 0      0      0     - /proc
+`,
+	"sos_commands/systemd/systemctl_list-units": `  UNIT                     LOAD   ACTIVE SUB     DESCRIPTION
+  kubelet.service          loaded active running kubelet: The Kubernetes Node Agent
+● kube-node-configuration.service loaded failed failed  Configures Kubernetes Node
+`,
+	"sos_commands/systemd/systemctl_list-units_--all": `● kube-node-configuration.service loaded failed failed  Configures Kubernetes Node
+`,
+	"proc/net/dev": `Inter-|   Receive                                                |  Transmit
+ face |bytes    packets errs drop fifo frame compressed multicast|bytes    packets errs drop fifo colls carrier compressed
+    lo: 1000      10    0    0    0     0          0         0     1000      10    0    0    0     0       0          0
+  eth0: 5000      50    3    7    0     0          0         0     6000      60    0    0    0     0       0          0
+  veth1: 5000     50    0    2    0     0          0         0     6000      60    0    0    0     0       0          0
+`,
+}
+
+// writeSosArchive writes a SOS report with files and symbolic links to a
+// temporary file, compressed with "xz", "gzip" or nothing.
+func writeSosArchive(t *testing.T, compression string, files, links map[string]string) string {
+	t.Helper()
+	name := filepath.Join(t.TempDir(), "sosreport.tar")
+	f, err := os.Create(name)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	var w io.WriteCloser = nopWriteCloser{f}
+	switch compression {
+	case "xz":
+		if w, err = xz.NewWriter(f); err != nil {
+			t.Fatal(err)
+		}
+	case "gzip":
+		w = gzip.NewWriter(f)
+	}
+	tw := tar.NewWriter(w)
+	write := func(hdr *tar.Header, content string) {
+		if err := tw.WriteHeader(hdr); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := io.WriteString(tw, content); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(&tar.Header{Name: sosTopDir, Typeflag: tar.TypeDir, Mode: 0o755}, "")
+	var names []string
+	for n := range files {
+		names = append(names, n)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		write(&tar.Header{Name: sosTopDir + n, Typeflag: tar.TypeReg, Mode: 0o644, Size: int64(len(files[n]))}, files[n])
+	}
+	for n, target := range links {
+		write(&tar.Header{Name: sosTopDir + n, Typeflag: tar.TypeSymlink, Linkname: target, Mode: 0o777}, "")
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return name
+}
+
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+func TestAnalyzeSosReport(t *testing.T) {
+	h := testKubeHandlers(nil)
+	for _, compression := range []string{"xz", "gzip", "none"} {
+		t.Run(compression, func(t *testing.T) {
+			archive := writeSosArchive(t, compression, sosFixture, nil)
+			_, out, err := h.analyzeSosReport(context.Background(), nil, &analyzeSosReportArgs{Path: archive})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.Hostname != "gke-cluster-pool-a-1234" || !strings.HasPrefix(out.Kernel, "Linux") || out.LogSource != "sos_commands/logs/journalctl_--no-pager" {
+				t.Errorf("host %q, kernel %q, log source %q", out.Hostname, out.Kernel, out.LogSource)
+			}
+			var got []string
+			for _, f := range out.Findings {
+				got = append(got, strings.Join([]string{f.Severity, f.Category, f.Summary}, " | "))
+			}
+			want := []string{
+				`critical | disk_pressure | The kubelet evicted pods to reclaim resources`,
+				`critical | disk_pressure | / is 97% full of disk space (/dev/sda1)`,
+				`critical | kernel_taint | Kernel tainted (D): kernel died recently (OOPS or BUG)`,
+				`critical | oom | The kernel ran out of memory and killed containerd-shim`,
+				`warning | containerd | failed to pull image 12345`,
+				`warning | inode_pressure | / is 89% full of inodes (/dev/sda1)`,
+				`warning | kubelet | "Error syncing pod, skipping" pod="default/web-1"`,
+				`warning | kubelet | "Error updating node status, will retry"`,
+				`warning | network | Interface eth0 has 3 receive and 0 transmit errors, 7 and 0 dropped packets`,
+				`warning | oom | java was killed for exceeding its memory limit`,
+				`warning | systemd | Unit kube-node-configuration.service failed: Configures Kubernetes Node`,
+				`info | kernel_taint | Kernel tainted (I): workaround for a platform firmware bug applied`,
+				`info | network | Interface veth1 dropped 2 received and 0 transmitted packets`,
+			}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("findings mismatch (-want +got):\n%s", diff)
+			}
+			for _, f := range out.Findings {
+				if f.Category == sosCategoryKubelet && strings.Contains(f.Summary, "syncing") && f.Count != 2 {
+					t.Errorf("pod sync errors counted %d times, want 2", f.Count)
+				}
+			}
+			if len(out.Unchecked) != 0 {
+				t.Errorf("unchecked = %v, want none", out.Unchecked)
+			}
+		})
+	}
+}
+
+func TestAnalyzeSosReportMissingData(t *testing.T) {
+	h := testKubeHandlers(nil)
+	archive := writeSosArchive(t, "xz", map[string]string{
+		"sos_commands/kernel/dmesg": "[  10.000000] Memory cgroup out of memory: Killed process 7 (python)\n",
+		"proc/sys/kernel/tainted":   "0\n",
+	}, nil)
+	_, out, err := h.analyzeSosReport(context.Background(), nil, &analyzeSosReportArgs{Path: archive})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(out.Findings) != 1 || out.Findings[0].Source != "sos_commands/kernel/dmesg" || out.Findings[0].Count != 1 {
+		t.Errorf("findings = %+v, want the OOM kill from dmesg", out.Findings)
+	}
+	want := []string{"kubelet", "containerd", "disk_pressure", "inode_pressure", "systemd", "network"}
+	if diff := cmp.Diff(want, out.Unchecked); diff != "" {
+		t.Errorf("unchecked mismatch (-want +got):\n%s", diff)
+	}
+
+	notArchive := filepath.Join(t.TempDir(), "report.tar.xz")
+	if err := os.WriteFile(notArchive, []byte{0xfd, '7', 'z', 'X', 'Z', 0x00, 1, 2, 3}, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, args := range []*analyzeSosReportArgs{{}, {Path: notArchive}, {Path: filepath.Join(t.TempDir(), "missing")}, {Path: archive, MaxFindings: 1000}} {
+		if _, _, err := h.analyzeSosReport(context.Background(), nil, args); err == nil {
+			t.Errorf("analyzeSosReport(%+v) succeeded, want error", args)
+		}
+	}
+}
+
+func TestReadSosReportFile(t *testing.T) {
+	h := testKubeHandlers(nil)
+	archive := writeSosArchive(t, "xz", map[string]string{
+		"sos_commands/kernel/uname_-a": "Linux node\n",
+		"sos_commands/kernel/dmesg":    "0123456789",
+		"proc/net/dev":                 "lo: 0\n",
+	}, map[string]string{
+		"uname": "sos_commands/kernel/uname_-a",
+		"loop":  "loop",
+	})
+	tests := []struct {
+		name          string
+		args          readSosReportFileArgs
+		wantFile      string
+		wantContent   string
+		wantTruncated bool
+		wantErr       bool
+	}{
+		{name: "file", args: readSosReportFileArgs{File: "/proc/net/dev"}, wantFile: "proc/net/dev", wantContent: "lo: 0\n"},
+		{name: "symbolic link", args: readSosReportFileArgs{File: "uname"}, wantFile: "sos_commands/kernel/uname_-a", wantContent: "Linux node\n"},
+		{name: "head", args: readSosReportFileArgs{File: "sos_commands/kernel/dmesg", MaxBytes: 4}, wantFile: "sos_commands/kernel/dmesg", wantContent: "0123", wantTruncated: true},
+		{name: "tail", args: readSosReportFileArgs{File: "sos_commands/kernel/dmesg", MaxBytes: 4, Tail: true}, wantFile: "sos_commands/kernel/dmesg", wantContent: "6789", wantTruncated: true},
+		{name: "whole tail", args: readSosReportFileArgs{File: "sos_commands/kernel/dmesg", MaxBytes: 10, Tail: true}, wantFile: "sos_commands/kernel/dmesg", wantContent: "0123456789"},
+		{name: "missing", args: readSosReportFileArgs{File: "etc/hosts"}, wantErr: true},
+		{name: "directory", args: readSosReportFileArgs{File: "proc"}, wantErr: true},
+		{name: "link loop", args: readSosReportFileArgs{File: "loop"}, wantErr: true},
+		{name: "too large", args: readSosReportFileArgs{File: "proc/net/dev", MaxBytes: 2 << 20}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.args.Path = archive
+			res, out, err := h.readSosReportFile(context.Background(), nil, &tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("readSosReportFile() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if out.File != tt.wantFile || out.Content != tt.wantContent || out.Truncated != tt.wantTruncated {
+				t.Errorf("readSosReportFile() = %s %q, truncated %v, want %s %q, truncated %v", out.File, out.Content, out.Truncated, tt.wantFile, tt.wantContent, tt.wantTruncated)
+			}
+			if got := res.Content[len(res.Content)-1].(*mcp.TextContent).Text; got != tt.wantContent {
+				t.Errorf("readSosReportFile() text content = %q, want %q", got, tt.wantContent)
+			}
+		})
+	}
+
+	_, out, err := h.readSosReportFile(context.Background(), nil, &readSosReportFileArgs{Path: archive, File: "sos_commands/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []sosReportFile{{Name: "sos_commands/kernel/dmesg", Size: 10}, {Name: "sos_commands/kernel/uname_-a", Size: 11}}
+	if diff := cmp.Diff(want, out.Files); diff != "" {
+		t.Errorf("listed files mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestReadLimitedTail(t *testing.T) {
+	content := strings.Repeat("a", 100<<10) + "end"
+	got, truncated, err := readLimited(strings.NewReader(content), 10, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "aaaaaaaend" || !truncated {
+		t.Errorf("readLimited() = %q, truncated %v, want %q, truncated", got, truncated, "aaaaaaaend")
+	}
+}
@@ -0,0 +1,607 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"golang.org/x/sync/errgroup"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilrand "k8s.io/apimachinery/pkg/util/rand"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// sosPodPrefix starts the names of SOS report debug pods, including
+	// those of versions that didn't label them.
+	sosPodPrefix    = "sos-debug-"
+	sosPodNamespace = metav1.NamespaceDefault
+	sosPodImage     = "gke.gcr.io/debian-base"
+
+	sosPurposeLabel       = "gke-mcp/purpose"
+	sosPurpose            = "sos-report"
+	sosNodeLabel          = "gke-mcp/node"
+	sosExpiresAnnotation  = "gke-mcp/expires-at"
+	sosPodReadyTimeout    = 2 * time.Minute
+	sosPodTTLMargin       = 5 * time.Minute
+	sosUnlabeledPodMaxAge = time.Hour
+
+	defaultSosParallelism = 4
+	maxSosParallelism     = 16
+	maxSosNodes           = 100
+
+	// gkeNodePoolLabel is the node label holding the node's node pool.
+	gkeNodePoolLabel = "cloud.google.com/gke-nodepool"
+)
+
+// nodeNameRE matches valid node names, which tools pass to commands.
+var nodeNameRE = regexp.MustCompile(`^[a-z0-9]([a-z0-9.-]*[a-z0-9])?$`)
+
+type getNodeSosReportArgs struct {
+	ProjectID      string   `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location       string   `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster        string   `json:"cluster" jsonschema:"GKE cluster the nodes belong to. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Node           string   `json:"node,omitempty" jsonschema:"GKE node name to collect SOS report from."`
+	Nodes          []string `json:"nodes,omitempty" jsonschema:"Names of several nodes to collect SOS reports from."`
+	NodePool       string   `json:"node_pool,omitempty" jsonschema:"Collect SOS reports from every node of this node pool."`
+	LabelSelector  string   `json:"label_selector,omitempty" jsonschema:"Collect SOS reports from the nodes matching this Kubernetes label selector, like 'cloud.google.com/gke-spot=true'. Can be combined with node_pool."`
+	Parallelism    int      `json:"parallelism,omitempty" jsonschema:"How many nodes to collect reports from at the same time. Defaults to 4, at most 16."`
+	Destination    string   `json:"destination,omitempty" jsonschema:"Local directory to download the SOS report to. Defaults to /tmp/sos-report if not specified."`
+	Method         string   `json:"method,omitempty" jsonschema:"Method to get sos report. Can be 'pod', 'ssh' or 'any'. Defaults to 'any'. When the node is unhealthy from api server, use ssh only."`
+	TimeoutSeconds int      `json:"timeout,omitempty" jsonschema:"Timeout in seconds for the report collection of each node (applies to both pod and ssh methods). Defaults to 180 (3 minutes)."`
+}
+
+type sosReportOutput struct {
+	Reports     []nodeSosReport `json:"reports" jsonschema:"The report of each selected node, in node name order."`
+	Succeeded   int             `json:"succeeded"`
+	Failed      int             `json:"failed"`
+	DeletedPods []string        `json:"deleted_pods,omitempty" jsonschema:"Debug pods left behind by earlier runs that were deleted."`
+}
+
+type nodeSosReport struct {
+	Node     string `json:"node"`
+	Method   string `json:"method,omitempty" jsonschema:"How the report was collected: pod or ssh."`
+	Path     string `json:"path,omitempty" jsonschema:"Local path of the downloaded report archive."`
+	Size     int64  `json:"size,omitempty" jsonschema:"Size of the report archive in bytes."`
+	SHA256   string `json:"sha256,omitempty" jsonschema:"Hex SHA-256 checksum of the report archive."`
+	PodError string `json:"pod_error,omitempty" jsonschema:"Why the pod method failed, if the report was collected over SSH instead."`
+	Error    string `json:"error,omitempty" jsonschema:"Why the report couldn't be collected."`
+}
+
+// installSosReport registers the SOS report tool.
+func (h *handlers) installSosReport(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "get_node_sos_report",
+		Description: "Generate and download SOS reports from one or more nodes of a GKE cluster, selected by name, node pool or label selector. Can use 'pod', 'ssh' or 'any' methods. Defaults to 'any' (pod with fallback to ssh). Use 'ssh' if node is API-unhealthy. " +
+			"The pod method runs a privileged debug pod on the node and deletes it afterwards, along with debug pods that earlier runs left behind. " +
+			"Returns a manifest of the reports with their local paths and SHA-256 checksums, and sends a progress notification as each node finishes.",
+	}, h.getNodeSosReport)
+}
+
+func (h *handlers) getNodeSosReport(ctx context.Context, req *mcp.CallToolRequest, args *getNodeSosReportArgs) (*mcp.CallToolResult, *sosReportOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	if args.Cluster == "" {
+		return nil, nil, fmt.Errorf("cluster argument cannot be empty")
+	}
+	byName := args.Node != "" || len(args.Nodes) > 0
+	bySelector := args.NodePool != "" || args.LabelSelector != ""
+	if !byName && !bySelector {
+		return nil, nil, fmt.Errorf("node argument cannot be empty: pass node, nodes, node_pool or label_selector")
+	}
+	if byName && bySelector {
+		return nil, nil, fmt.Errorf("select nodes either by name or by node_pool and label_selector, not both")
+	}
+	if args.Destination == "" {
+		args.Destination = "/tmp/sos-report"
+	}
+	if args.Method == "" {
+		args.Method = "any"
+	}
+	if args.Method != "pod" && args.Method != "ssh" && args.Method != "any" {
+		return nil, nil, fmt.Errorf("invalid method %q, must be pod, ssh or any", args.Method)
+	}
+	if args.TimeoutSeconds <= 0 {
+		args.TimeoutSeconds = 180 // Default to 3 minutes
+	}
+	switch {
+	case args.Parallelism <= 0:
+		args.Parallelism = defaultSosParallelism
+	case args.Parallelism > maxSosParallelism:
+		args.Parallelism = maxSosParallelism
+	}
+
+	// The pod method, and selecting nodes by label, need the cluster's API
+	// server. SSH alone doesn't.
+	var k *kubeClients
+	if args.Method != "ssh" || bySelector {
+		var err error
+		k, err = h.kubeClientsFor(ctx, args.ProjectID, args.Location, args.Cluster)
+		if err != nil && (args.Method == "pod" || bySelector) {
+			return nil, nil, err
+		}
+		if err != nil {
+			log.Printf("Failed to connect to cluster %s, falling back to SSH: %v", args.Cluster, err)
+		}
+	}
+
+	nodes := append([]string{args.Node}, args.Nodes...)
+	if bySelector {
+		var err error
+		if nodes, err = selectNodes(ctx, k, args.NodePool, args.LabelSelector); err != nil {
+			return nil, nil, err
+		}
+	}
+	nodes = slices.Compact(slices.Sorted(slices.Values(slices.DeleteFunc(nodes, func(n string) bool { return n == "" }))))
+	if len(nodes) == 0 {
+		return nil, nil, fmt.Errorf("no nodes match node_pool %q and label_selector %q", args.NodePool, args.LabelSelector)
+	}
+	if len(nodes) > maxSosNodes {
+		return nil, nil, fmt.Errorf("%d nodes selected, at most %d can be collected at once: narrow the selection", len(nodes), maxSosNodes)
+	}
+	for _, node := range nodes {
+		// Basic validation for node name to prevent command injection
+		if !nodeNameRE.MatchString(node) {
+			return nil, nil, fmt.Errorf("invalid node name: %s", node)
+		}
+	}
+
+	if err := os.MkdirAll(args.Destination, 0750); err != nil {
+		return nil, nil, fmt.Errorf("failed to create destination directory %s: %w", args.Destination, err)
+	}
+
+	out := &sosReportOutput{Reports: make([]nodeSosReport, len(nodes))}
+	if k != nil && args.Method != "ssh" {
+		out.DeletedPods = deleteOrphanedSosPods(ctx, k, time.Now())
+	}
+
+	progress := progressNotifier(req)
+	var mu sync.Mutex
+	done := 0
+	var g errgroup.Group
+	g.SetLimit(args.Parallelism)
+	for i, node := range nodes {
+		g.Go(func() error {
+			r := collectNodeSosReport(ctx, k, args, node)
+			out.Reports[i] = r
+
+			mu.Lock()
+			defer mu.Unlock()
+			done++
+			if progress != nil {
+				status := "collected via " + r.Method
+				if r.Error != "" {
+					status = "failed"
+				}
+				progress(ctx, float64(done)*100/float64(len(nodes)), fmt.Sprintf("%s: %s (%d/%d nodes)", node, status, done, len(nodes)))
+			}
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	var errs []error
+	for _, r := range out.Reports {
+		if r.Error != "" {
+			out.Failed++
+			errs = append(errs, fmt.Errorf("%s: %s", r.Node, r.Error))
+			continue
+		}
+		out.Succeeded++
+	}
+	if out.Succeeded == 0 {
+		return nil, nil, fmt.Errorf("failed to get sos report: %w", errors.Join(errs...))
+	}
+	res, err := toolresult.New(out.summary(), out)
+	return res, out, err
+}
+
+// collectNodeSosReport collects the report of node with the pod method, the
+// SSH method or the pod method falling back to SSH. k is nil if the cluster
+// can't be reached.
+func collectNodeSosReport(ctx context.Context, k *kubeClients, args *getNodeSosReportArgs, node string) nodeSosReport {
+	r := nodeSosReport{Node: node}
+	timeout := time.Duration(args.TimeoutSeconds) * time.Second
+
+	if k != nil && args.Method != "ssh" {
+		// 1. Try Pod-based approach with timeout
+		podCtx, podCancel := context.WithTimeout(ctx, timeout)
+		path, err := sosReportWithPod(podCtx, k, args, node)
+		podCancel()
+		if err == nil {
+			r.Method, r.Path = "pod", path
+			return r.withChecksum()
+		}
+		if args.Method == "pod" {
+			r.Error = fmt.Sprintf("failed to get sos report with pod: %v", err)
+			return r
+		}
+		// If method is any and pod failed (e.g. timeout), fall through to ssh
+		r.PodError = err.Error()
+		log.Printf("Failed to get SOS report of node %s with a pod, falling back to SSH: %v", node, err)
+	}
+
+	// 2. Fallback or direct SSH approach with timeout
+	sshCtx, sshCancel := context.WithTimeout(ctx, timeout)
+	defer sshCancel()
+	path, err := sosReportWithSSH(sshCtx, args, node)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	r.Method, r.Path = "ssh", path
+	return r.withChecksum()
+}
+
+// withChecksum sets the size and SHA-256 checksum of the downloaded report.
+func (r nodeSosReport) withChecksum() nodeSosReport {
+	f, err := os.Open(r.Path)
+	if err != nil {
+		r.Error = fmt.Sprintf("failed to open downloaded report: %v", err)
+		return r
+	}
+	defer f.Close()
+	hash := sha256.New()
+	n, err := io.Copy(hash, f)
+	if err != nil {
+		r.Error = fmt.Sprintf("failed to read downloaded report: %v", err)
+		return r
+	}
+	r.Size, r.SHA256 = n, hex.EncodeToString(hash.Sum(nil))
+	return r
+}
+
+// selectNodes returns the names of the nodes of nodePool that match
+// labelSelector.
+func selectNodes(ctx context.Context, k *kubeClients, nodePool, labelSelector string) ([]string, error) {
+	selector := labelSelector
+	if nodePool != "" {
+		selector = strings.Trim(gkeNodePoolLabel+"="+nodePool+","+selector, ",")
+	}
+	list, err := k.clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list nodes: %w", err)
+	}
+	var nodes []string
+	for _, n := range list.Items {
+		nodes = append(nodes, n.Name)
+	}
+	return nodes, nil
+}
+
+func (o *sosReportOutput) summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Collected SOS reports from %d of %d nodes.\n\n", o.Succeeded, len(o.Reports))
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NODE\tMETHOD\tPATH\tSHA256\tERROR")
+	for _, r := range o.Reports {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", r.Node, valueOrDash(r.Method), valueOrDash(r.Path), valueOrDash(r.SHA256), r.Error)
+	}
+	_ = w.Flush()
+	summary := strings.TrimRight(b.String(), "\n")
+	if len(o.DeletedPods) > 0 {
+		summary += fmt.Sprintf("\nDeleted debug pods left behind by earlier runs: %s", strings.Join(o.DeletedPods, ", "))
+	}
+	return summary
+}
+
+// sosReportWithPod collects the report of node through a privileged debug
+// pod, if the node is ready, and returns its local path.
+func sosReportWithPod(ctx context.Context, k *kubeClients, args *getNodeSosReportArgs, nodeName string) (string, error) {
+	node, err := k.clientset.CoreV1().Nodes().Get(ctx, nodeName, metav1.GetOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to get node %s: %w", nodeName, err)
+	}
+	if !nodeReady(node) {
+		return "", fmt.Errorf("node %s isn't ready", nodeName)
+	}
+
+	// 1. Prepare and run debug pod. Its TTL outlives the collection, so
+	// later runs can tell pods that are still in use from orphans.
+	now := time.Now()
+	ttl := time.Duration(args.TimeoutSeconds)*time.Second + sosPodTTLMargin
+	pod, err := k.clientset.CoreV1().Pods(sosPodNamespace).Create(ctx, newSosPod(nodeName, now, ttl), metav1.CreateOptions{})
+	if err != nil {
+		return "", fmt.Errorf("failed to create debug pod: %w", err)
+	}
+	defer func() {
+		// Cleanup pod, even if ctx is done.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := k.clientset.CoreV1().Pods(sosPodNamespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)}); err != nil {
+			log.Printf("Failed to delete debug pod %s: %v", pod.Name, err)
+		}
+	}()
+
+	// 2. Wait for pod to be ready
+	if err := waitForPodReady(ctx, k, sosPodNamespace, pod.Name, sosPodReadyTimeout); err != nil {
+		return "", err
+	}
+
+	// 3. Run sos report inside the pod, against the host's root file system.
+	// We create a temp dir for the report to avoid conflicts in /tmp
+	remoteTmpDir := "/tmp/sos-" + pod.Name
+	execScript := fmt.Sprintf("apt update && apt install -y sosreport && mkdir -p /host%s && sos report --sysroot=/host --all-logs --batch --tmp-dir=/host%s", remoteTmpDir, remoteTmpDir)
+	var output bytes.Buffer
+	if err := k.exec(ctx, sosPodNamespace, pod.Name, []string{"sh", "-c", execScript}, &output, &output); err != nil {
+		return "", fmt.Errorf("failed to generate sos report: %s, %w", output.String(), err)
+	}
+	defer func() {
+		// Cleanup remote files on host (via pod), even if ctx is done.
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), 30*time.Second)
+		defer cancel()
+		if err := k.exec(ctx, sosPodNamespace, pod.Name, []string{"rm", "-rf", "/host" + remoteTmpDir}, io.Discard, io.Discard); err != nil {
+			log.Printf("Failed to remove %s from node %s: %v", remoteTmpDir, nodeName, err)
+		}
+	}()
+
+	// 4. Parse the output to find the filename
+	remotePath, err := sosReportRemotePath(output.String(), remoteTmpDir)
+	if err != nil {
+		return "", err
+	}
+
+	// 5. Stream the file from the pod to the destination
+	localPath := filepath.Clean(filepath.Join(args.Destination, fmt.Sprintf("sosreport-%s-%s.tar.xz", nodeName, now.Format("2006-01-02-15-04-05"))))
+	f, err := os.Create(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to create local file %s: %w", localPath, err)
+	}
+	var stderr bytes.Buffer
+	err = k.exec(ctx, sosPodNamespace, pod.Name, []string{"cat", remotePath}, f, &stderr)
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		_ = os.Remove(localPath) // Best-effort cleanup
+		return "", fmt.Errorf("failed to copy sos report from pod: %s, %w", stderr.String(), err)
+	}
+	return localPath, nil
+}
+
+// sosReportRemotePath returns the path of the report in the debug pod, from
+// the output of sos report.
+func sosReportRemotePath(output, remoteTmpDir string) (string, error) {
+	// The output usually contains: "Your sosreport has been generated and saved in: /path/to/file.tar.xz"
+	// The path might be reported as /host/tmp/... or /tmp/... depending on how sos report was invoked.
+	// We also handle both .tar.xz and .tar.gz extensions.
+	re := regexp.MustCompile(`(/host)?` + regexp.QuoteMeta(remoteTmpDir) + `/[^\s]+\.tar\.(xz|gz)`)
+	match := re.FindString(output)
+	if match == "" {
+		return "", fmt.Errorf("could not find sos report filename in output: %s", output)
+	}
+	// The host's root file system is mounted at /host.
+	if !strings.HasPrefix(match, "/host") {
+		match = "/host" + match
+	}
+	return match, nil
+}
+
+// newSosPod returns a privileged pod that runs on node with access to its
+// root file system, PID and network namespaces. It expires after ttl: the
+// kubelet stops it, and deleteOrphanedSosPods deletes it.
+func newSosPod(node string, now time.Time, ttl time.Duration) *corev1.Pod {
+	seconds := int64(ttl / time.Second)
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      sosPodPrefix + utilrand.String(8),
+			Namespace: sosPodNamespace,
+			Labels: map[string]string{
+				sosPurposeLabel: sosPurpose,
+				sosNodeLabel:    node,
+			},
+			Annotations: map[string]string{
+				sosExpiresAnnotation: now.Add(ttl).UTC().Format(time.RFC3339),
+			},
+		},
+		Spec: corev1.PodSpec{
+			NodeName:              node,
+			HostNetwork:           true,
+			HostPID:               true,
+			HostIPC:               true,
+			RestartPolicy:         corev1.RestartPolicyNever,
+			ActiveDeadlineSeconds: &seconds,
+			// Run on nodes whatever their taints, as the pod is for debugging.
+			Tolerations: []corev1.Toleration{{Operator: corev1.TolerationOpExists}},
+			Containers: []corev1.Container{{
+				Name:    "main",
+				Image:   sosPodImage,
+				Command: []string{"/bin/sleep", fmt.Sprint(seconds)},
+				SecurityContext: &corev1.SecurityContext{
+					Privileged: ptr.To(true),
+					RunAsUser:  ptr.To[int64](0),
+				},
+				VolumeMounts: []corev1.VolumeMount{{Name: "root", MountPath: "/host"}},
+			}},
+			Volumes: []corev1.Volume{{
+				Name: "root",
+				VolumeSource: corev1.VolumeSource{
+					HostPath: &corev1.HostPathVolumeSource{Path: "/", Type: ptr.To(corev1.HostPathDirectory)},
+				},
+			}},
+		},
+	}
+}
+
+// legacySosPodNameRE matches the names of the unlabeled debug pods of earlier
+// versions, sos-debug-<unix seconds>, so that other pods are never deleted.
+var legacySosPodNameRE = regexp.MustCompile(`^sos-debug-\d+$`)
+
+// deleteOrphanedSosPods deletes the debug pods that crashed or interrupted
+// runs left behind: pods that expired or stopped, and unlabeled pods of
+// earlier versions older than sosUnlabeledPodMaxAge. Failures are logged, as
+// they don't affect the report.
+func deleteOrphanedSosPods(ctx context.Context, k *kubeClients, now time.Time) []string {
+	pods, err := k.clientset.CoreV1().Pods(sosPodNamespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		log.Printf("Failed to list debug pods: %v", err)
+		return nil
+	}
+	var deleted []string
+	for _, pod := range pods.Items {
+		if !sosPodOrphaned(&pod, now) {
+			continue
+		}
+		if err := k.clientset.CoreV1().Pods(pod.Namespace).Delete(ctx, pod.Name, metav1.DeleteOptions{GracePeriodSeconds: ptr.To[int64](0)}); err != nil {
+			log.Printf("Failed to delete orphaned debug pod %s: %v", pod.Name, err)
+			continue
+		}
+		deleted = append(deleted, pod.Name)
+	}
+	return deleted
+}
+
+func sosPodOrphaned(pod *corev1.Pod, now time.Time) bool {
+	if !strings.HasPrefix(pod.Name, sosPodPrefix) || !pod.DeletionTimestamp.IsZero() {
+		return false
+	}
+	if pod.Labels[sosPurposeLabel] != sosPurpose {
+		return legacySosPodNameRE.MatchString(pod.Name) && now.Sub(pod.CreationTimestamp.Time) > sosUnlabeledPodMaxAge
+	}
+	if pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed {
+		return true
+	}
+	expires, err := time.Parse(time.RFC3339, pod.Annotations[sosExpiresAnnotation])
+	return err != nil || now.After(expires)
+}
+
+// waitForPodReady waits until a pod is ready, failing early if it can't
+// start.
+func waitForPodReady(ctx context.Context, k *kubeClients, namespace, name string, timeout time.Duration) error {
+	var last string
+	err := wait.PollUntilContextTimeout(ctx, time.Second, timeout, true, func(ctx context.Context) (bool, error) {
+		pod, err := k.clientset.CoreV1().Pods(namespace).Get(ctx, name, metav1.GetOptions{})
+		if err != nil {
+			return false, fmt.Errorf("failed to get pod %s: %w", name, err)
+		}
+		switch pod.Status.Phase {
+		case corev1.PodSucceeded, corev1.PodFailed:
+			return false, fmt.Errorf("pod %s stopped: %s %s", name, pod.Status.Reason, pod.Status.Message)
+		}
+		for _, c := range pod.Status.ContainerStatuses {
+			if w := c.State.Waiting; w != nil {
+				last = w.Reason + " " + w.Message
+				switch w.Reason {
+				case "ErrImagePull", "ImagePullBackOff", "InvalidImageName", "CreateContainerConfigError", "CreateContainerError":
+					return false, fmt.Errorf("pod %s can't start: %s", name, strings.TrimSpace(last))
+				}
+			}
+		}
+		for _, c := range pod.Status.Conditions {
+			if c.Type == corev1.PodReady && c.Status == corev1.ConditionTrue {
+				return true, nil
+			}
+		}
+		return false, nil
+	})
+	if err != nil && wait.Interrupted(err) {
+		return fmt.Errorf("debug pod %s did not become ready: %s", name, strings.TrimSpace(firstNonEmpty(last, err.Error())))
+	}
+	return err
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, c := range node.Status.Conditions {
+		if c.Type == corev1.NodeReady {
+			return c.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// sosReportWithSSH collects the report of node over SSH and returns its local
+// path.
+func sosReportWithSSH(ctx context.Context, args *getNodeSosReportArgs, node string) (string, error) {
+	// 1. Find the zone of the VM
+	// gcloud compute instances list --filter="name=NODE_NAME" --format="value(zone)"
+	// #nosec G204
+	findZoneCmd := exec.CommandContext(ctx, "gcloud", "compute", "instances", "list", "--project", args.ProjectID, fmt.Sprintf("--filter=name=%s", node), "--format=value(zone)")
+	zoneOut, err := findZoneCmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("failed to find zone for node %s using gcloud: %w", node, err)
+	}
+	zone := strings.TrimSpace(string(zoneOut))
+	if zone == "" {
+		return "", fmt.Errorf("could not find zone for node %s", node)
+	}
+
+	// 2. Generate SOS report via SSH
+	// gcloud compute ssh --zone "ZONE" "NODE_NAME" --command "sudo sos report --all-logs --batch --tmp-dir=/var"
+	// #nosec G204
+	sshCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--project", args.ProjectID, "--zone", zone, node, "--command", "sudo sos report --all-logs --batch --tmp-dir=/var")
+	outBytes, err := sshCmd.CombinedOutput()
+	output := string(outBytes)
+	if err != nil {
+		return "", fmt.Errorf("failed to generate sos report via ssh: %s, %w", output, err)
+	}
+
+	// 3. Parse output for filename
+	// Matches /var/sosreport-.*.tar.xz
+	re := regexp.MustCompile(`/var/sosreport-[^\s]+\.tar\.xz`)
+	match := re.FindString(output)
+	if match == "" {
+		return "", fmt.Errorf("could not find sos report filename in ssh output: %s", output)
+	}
+	remotePath := match
+
+	// 4. Change ownership of the file
+	// gcloud compute ssh ... --command "sudo chown $USER REMOTE_PATH"
+	// #nosec G204
+	chownCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--project", args.ProjectID, "--zone", zone, node, "--command", fmt.Sprintf("sudo chown $USER %s", remotePath))
+	if out, err := chownCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to chown remote file: %s, %w", string(out), err)
+	}
+
+	// 5. SCP the file
+	// gcloud compute scp --zone "ZONE" "NODE_NAME:REMOTE_PATH" LOCAL_DESTINATION
+	localFilename := fmt.Sprintf("sosreport-%s-%s.tar.xz", node, time.Now().Format("2006-01-02-15-04-05"))
+	localPath := filepath.Join(args.Destination, localFilename)
+	// #nosec G204
+	scpCmd := exec.CommandContext(ctx, "gcloud", "compute", "scp", "--project", args.ProjectID, "--zone", zone, fmt.Sprintf("%s:%s", node, remotePath), localPath)
+	if out, err := scpCmd.CombinedOutput(); err != nil {
+		return "", fmt.Errorf("failed to scp file: %s, %w", string(out), err)
+	}
+
+	// 6. Cleanup remote files on host
+	// #nosec G204
+	rmCmd := exec.CommandContext(ctx, "gcloud", "compute", "ssh", "--project", args.ProjectID, "--zone", zone, node, "--command", fmt.Sprintf("sudo rm %s", remotePath))
+	_ = rmCmd.Run() // Best-effort cleanup
+
+	return localPath, nil
+}
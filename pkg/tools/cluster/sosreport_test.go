@@ -0,0 +1,253 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+func testNode(name string, ready corev1.ConditionStatus) *corev1.Node {
+	return &corev1.Node{
+		ObjectMeta: metav1.ObjectMeta{Name: name},
+		Status: corev1.NodeStatus{
+			Conditions: []corev1.NodeCondition{{Type: corev1.NodeReady, Status: ready}},
+		},
+	}
+}
+
+// startPodsOnCreate makes pods created through clientset ready at once.
+func startPodsOnCreate(clientset *fake.Clientset) {
+	clientset.PrependReactor("create", "pods", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		pod := action.(k8stesting.CreateAction).GetObject().(*corev1.Pod)
+		pod.Status.Phase = corev1.PodRunning
+		pod.Status.Conditions = []corev1.PodCondition{{Type: corev1.PodReady, Status: corev1.ConditionTrue}}
+		return false, nil, nil
+	})
+}
+
+func TestNewSosPod(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	pod := newSosPod("node-1", now, 10*time.Minute)
+
+	if !strings.HasPrefix(pod.Name, sosPodPrefix) {
+		t.Errorf("pod name %q doesn't start with %q", pod.Name, sosPodPrefix)
+	}
+	if diff := cmp.Diff(map[string]string{sosPurposeLabel: sosPurpose, sosNodeLabel: "node-1"}, pod.Labels); diff != "" {
+		t.Errorf("labels mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := pod.Annotations[sosExpiresAnnotation], "2025-01-01T12:10:00Z"; got != want {
+		t.Errorf("expiry = %q, want %q", got, want)
+	}
+	if pod.Spec.NodeName != "node-1" || !pod.Spec.HostPID || *pod.Spec.ActiveDeadlineSeconds != 600 {
+		t.Errorf("spec = %+v, want it on node-1 with the host PID namespace and a 600s deadline", pod.Spec)
+	}
+	if c := pod.Spec.Containers[0]; !*c.SecurityContext.Privileged {
+		t.Errorf("container isn't privileged")
+	}
+	if sosPodOrphaned(pod, now.Add(5*time.Minute)) || !sosPodOrphaned(pod, now.Add(11*time.Minute)) {
+		t.Errorf("pod should only be orphaned after it expires")
+	}
+}
+
+func TestDeleteOrphanedSosPods(t *testing.T) {
+	now := time.Date(2025, 1, 1, 12, 0, 0, 0, time.UTC)
+	sosPod := func(name string, expires time.Time, phase corev1.PodPhase) *corev1.Pod {
+		pod := newSosPod("node-1", expires, 0)
+		pod.Name = name
+		pod.Status.Phase = phase
+		return pod
+	}
+	unlabeled := func(name string, created time.Time) *corev1.Pod {
+		return &corev1.Pod{ObjectMeta: metav1.ObjectMeta{
+			Namespace:         sosPodNamespace,
+			Name:              name,
+			CreationTimestamp: metav1.NewTime(created),
+		}}
+	}
+	clientset := fake.NewClientset(
+		sosPod("sos-debug-expired", now.Add(-time.Minute), corev1.PodRunning),
+		sosPod("sos-debug-in-use", now.Add(time.Minute), corev1.PodRunning),
+		sosPod("sos-debug-failed", now.Add(time.Minute), corev1.PodFailed),
+		unlabeled("sos-debug-1700000000", now.Add(-2*time.Hour)),
+		unlabeled("sos-debug-1735732000", now.Add(-time.Minute)),
+		unlabeled("sos-debug-tools", now.Add(-2*time.Hour)),
+		unlabeled("web", now.Add(-2*time.Hour)),
+	)
+
+	got := deleteOrphanedSosPods(context.Background(), &kubeClients{clientset: clientset}, now)
+	sort.Strings(got)
+	want := []string{"sos-debug-1700000000", "sos-debug-expired", "sos-debug-failed"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("deleteOrphanedSosPods() mismatch (-want +got):\n%s", diff)
+	}
+	pods, err := clientset.CoreV1().Pods(sosPodNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 4 {
+		t.Errorf("%d pods left, want 4", len(pods.Items))
+	}
+}
+
+func TestSosReportRemotePath(t *testing.T) {
+	tests := []struct {
+		output  string
+		want    string
+		wantErr bool
+	}{
+		{output: "Your sosreport has been generated and saved in:\n\t/host/tmp/sos-x/sosreport-node-2025.tar.xz\n", want: "/host/tmp/sos-x/sosreport-node-2025.tar.xz"},
+		{output: "saved in: /tmp/sos-x/sosreport-node-2025.tar.gz", want: "/host/tmp/sos-x/sosreport-node-2025.tar.gz"},
+		{output: "saved in: /var/tmp/sosreport-node-2025.tar.xz", wantErr: true},
+	}
+	for _, tt := range tests {
+		got, err := sosReportRemotePath(tt.output, "/tmp/sos-x")
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("sosReportRemotePath(%q) = %q, %v, want %q, error %v", tt.output, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+// testSosHandlers returns handlers whose cluster has the given nodes, and
+// whose debug pods produce an archive containing "archive".
+func testSosHandlers(nodes ...runtime.Object) (*handlers, *fake.Clientset, func() []string) {
+	clientset := fake.NewClientset(nodes...)
+	startPodsOnCreate(clientset)
+
+	var mu sync.Mutex
+	var commands []string
+	k := &kubeClients{
+		clientset: clientset,
+		execFn: func(_ context.Context, namespace, pod string, command []string, stdout, _ io.Writer) error {
+			mu.Lock()
+			commands = append(commands, command[0])
+			mu.Unlock()
+			switch command[0] {
+			case "sh":
+				fmt.Fprintf(stdout, "Your sosreport has been generated and saved in:\n\t/host/tmp/sos-%s/sosreport-node.tar.xz\n", pod)
+			case "cat":
+				fmt.Fprint(stdout, "archive")
+			}
+			return nil
+		},
+	}
+	h := testKubeHandlers(nil)
+	h.kube = func(context.Context, string, string, string) (*kubeClients, error) { return k, nil }
+	return h, clientset, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return slices.Clone(commands)
+	}
+}
+
+func TestGetNodeSosReportWithPod(t *testing.T) {
+	h, clientset, commands := testSosHandlers(testNode("node-1", corev1.ConditionTrue), testNode("node-2", corev1.ConditionFalse))
+
+	dest := t.TempDir()
+	_, out, err := h.getNodeSosReport(context.Background(), nil, &getNodeSosReportArgs{Cluster: "c", Node: "node-1", Destination: dest, Method: "pod"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := out.Reports[0]
+	b, err := os.ReadFile(r.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(b) != "archive" || r.Method != "pod" {
+		t.Errorf("report = %q collected with %s, want archive collected with pod", b, r.Method)
+	}
+	// The SHA-256 checksum of "archive".
+	if want := "0eb3e36bfb24dcd9bb1d1bece1531216b59539a8fde17ee80224af0653c92aa3"; r.SHA256 != want || r.Size != 7 {
+		t.Errorf("checksum = %q, size %d, want %q and 7", r.SHA256, r.Size, want)
+	}
+	if diff := cmp.Diff([]string{"sh", "cat", "rm"}, commands()); diff != "" {
+		t.Errorf("commands mismatch (-want +got):\n%s", diff)
+	}
+	pods, err := clientset.CoreV1().Pods(sosPodNamespace).List(context.Background(), metav1.ListOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods.Items) != 0 {
+		t.Errorf("debug pod %s wasn't deleted", pods.Items[0].Name)
+	}
+
+	if _, _, err := h.getNodeSosReport(context.Background(), nil, &getNodeSosReportArgs{Cluster: "c", Node: "node-2", Destination: dest, Method: "pod"}); err == nil {
+		t.Errorf("getNodeSosReport() of a node that isn't ready succeeded, want error")
+	}
+	if _, _, err := h.getNodeSosReport(context.Background(), nil, &getNodeSosReportArgs{Node: "node-1"}); err == nil {
+		t.Errorf("getNodeSosReport() without a cluster succeeded, want error")
+	}
+}
+
+func TestGetNodeSosReportNodeSelection(t *testing.T) {
+	inPool := func(n *corev1.Node, pool string) *corev1.Node {
+		n.Labels = map[string]string{gkeNodePoolLabel: pool}
+		return n
+	}
+	h, _, _ := testSosHandlers(
+		inPool(testNode("pool-a-1", corev1.ConditionTrue), "pool-a"),
+		inPool(testNode("pool-a-2", corev1.ConditionFalse), "pool-a"),
+		inPool(testNode("pool-a-3", corev1.ConditionTrue), "pool-a"),
+		inPool(testNode("pool-b-1", corev1.ConditionTrue), "pool-b"),
+	)
+	dest := t.TempDir()
+
+	_, out, err := h.getNodeSosReport(context.Background(), nil, &getNodeSosReportArgs{Cluster: "c", NodePool: "pool-a", Destination: dest, Method: "pod", Parallelism: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, r := range out.Reports {
+		got = append(got, r.Node+" "+r.Method+" "+strconv.FormatBool(r.Error == ""))
+	}
+	want := []string{"pool-a-1 pod true", "pool-a-2  false", "pool-a-3 pod true"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("reports mismatch (-want +got):\n%s", diff)
+	}
+	if out.Succeeded != 2 || out.Failed != 1 {
+		t.Errorf("succeeded %d, failed %d, want 2 and 1", out.Succeeded, out.Failed)
+	}
+	if out.Reports[0].Path == out.Reports[2].Path {
+		t.Errorf("reports of different nodes share the path %s", out.Reports[0].Path)
+	}
+
+	for _, args := range []*getNodeSosReportArgs{
+		{Cluster: "c", Node: "pool-a-1", NodePool: "pool-a"},
+		{Cluster: "c", NodePool: "pool-c"},
+		{Cluster: "c", Nodes: []string{"pool-a-1", "Bad;name"}},
+		{Cluster: "c", Node: "pool-a-1", Method: "scp"},
+	} {
+		args.Destination = dest
+		if _, _, err := h.getNodeSosReport(context.Background(), nil, args); err == nil {
+			t.Errorf("getNodeSosReport(%+v) succeeded, want error", args)
+		}
+	}
+}
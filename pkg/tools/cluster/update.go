@@ -0,0 +1,407 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"reflect"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+type updateClusterArgs struct {
+	ProjectID                string                     `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location                 string                     `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster                  string                     `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Update                   *containerpb.ClusterUpdate `json:"update,omitempty" jsonschema:"Any other cluster changes, as a GKE ClusterUpdate with the desired_* fields to change, e.g. desired_addons_config. GKE usually applies one field per call."`
+	LoggingComponents        []string                   `json:"logging_components,omitempty" jsonschema:"Components to send logs from, replacing the current list, e.g. SYSTEM_COMPONENTS, WORKLOADS, APISERVER. Pass an empty list to disable logging."`
+	MonitoringComponents     []string                   `json:"monitoring_components,omitempty" jsonschema:"Components to send metrics from, replacing the current list, e.g. SYSTEM_COMPONENTS, APISERVER, POD, DEPLOYMENT. Pass an empty list to disable them all."`
+	MasterAuthorizedNetworks []string                   `json:"master_authorized_networks,omitempty" jsonschema:"CIDR ranges allowed to reach the control plane, replacing the current ones. Pass an empty list to disable master authorized networks."`
+	AutoscalingProfile       string                     `json:"autoscaling_profile,omitempty" jsonschema:"Cluster autoscaler profile: BALANCED or OPTIMIZE_UTILIZATION."`
+	ResourceLabels           map[string]string          `json:"resource_labels,omitempty" jsonschema:"GCP labels for the cluster, replacing all existing ones. Pass an empty object to remove them all. Can't be combined with other changes in one call."`
+	DryRun                   *bool                      `json:"dry_run,omitempty" jsonschema:"Only preview the changes. Defaults to true; set to false only after the user has approved the previewed changes."`
+}
+
+// fieldChange is one cluster value an update changes.
+type fieldChange struct {
+	Field   string `json:"field" jsonschema:"Cluster field, using the JSON names get_cluster returns, e.g. loggingConfig.componentConfig.enableComponents."`
+	Current any    `json:"current,omitempty"`
+	Desired any    `json:"desired,omitempty"`
+}
+
+type updateClusterOutput struct {
+	Cluster   string            `json:"cluster" jsonschema:"Full resource name of the cluster."`
+	DryRun    bool              `json:"dry_run"`
+	Changes   []fieldChange     `json:"changes,omitempty"`
+	Unknown   []string          `json:"unknown,omitempty" jsonschema:"ClusterUpdate fields whose current value couldn't be looked up, so they are missing from changes."`
+	Update    map[string]any    `json:"update,omitempty" jsonschema:"The ClusterUpdate sent, or that would be sent, to GKE."`
+	Operation *operationSummary `json:"operation,omitempty" jsonschema:"The update operation, if the update was started."`
+}
+
+// desiredClusterFields maps ClusterUpdate fields to the cluster fields they
+// change, where the names don't simply drop the "desired" prefix. Both use
+// JSON names.
+var desiredClusterFields = map[string]string{
+	"desiredMasterVersion":                         "currentMasterVersion",
+	"desiredNodeVersion":                           "currentNodeVersion",
+	"desiredClusterAutoscaling":                    "autoscaling",
+	"desiredDnsConfig":                             "networkConfig.dnsConfig",
+	"desiredGatewayApiConfig":                      "networkConfig.gatewayApiConfig",
+	"desiredDatapathProvider":                      "networkConfig.datapathProvider",
+	"desiredPrivateIpv6GoogleAccess":               "networkConfig.privateIpv6GoogleAccess",
+	"desiredDefaultSnatStatus":                     "networkConfig.defaultSnatStatus",
+	"desiredServiceExternalIpsConfig":              "networkConfig.serviceExternalIpsConfig",
+	"desiredNetworkPerformanceConfig":              "networkConfig.networkPerformanceConfig",
+	"desiredEnableFqdnNetworkPolicy":               "networkConfig.enableFqdnNetworkPolicy",
+	"desiredEnableMultiNetworking":                 "networkConfig.enableMultiNetworking",
+	"desiredEnableCiliumClusterwideNetworkPolicy":  "networkConfig.enableCiliumClusterwideNetworkPolicy",
+	"desiredDefaultEnablePrivateNodes":             "networkConfig.defaultEnablePrivateNodes",
+	"desiredEnablePrivateEndpoint":                 "privateClusterConfig.enablePrivateEndpoint",
+	"desiredStackType":                             "ipAllocationPolicy.stackType",
+	"desiredK8sBetaApis":                           "enableK8sBetaApis",
+	"desiredAutopilotWorkloadPolicyConfig":         "autopilot.workloadPolicyConfig",
+	"desiredNodePoolAutoConfigNetworkTags":         "nodePoolAutoConfig.networkTags",
+	"desiredNodePoolAutoConfigResourceManagerTags": "nodePoolAutoConfig.resourceManagerTags",
+	"desiredNodePoolAutoConfigKubeletConfig":       "nodePoolAutoConfig.nodeKubeletConfig",
+	"desiredNodePoolLoggingConfig":                 "nodePoolDefaults.nodeConfigDefaults.loggingConfig",
+	"desiredContainerdConfig":                      "nodePoolDefaults.nodeConfigDefaults.containerdConfig",
+	"desiredNodeKubeletConfig":                     "nodePoolDefaults.nodeConfigDefaults.nodeKubeletConfig",
+	"desiredGcfsConfig":                            "nodePoolDefaults.nodeConfigDefaults.gcfsConfig",
+}
+
+// installUpdate registers the update_cluster tool.
+func (h *handlers) installUpdate(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "update_cluster",
+		Description: "Update the settings of a GKE cluster, such as logging and monitoring components, master authorized networks, labels, addons or the autoscaling profile. " +
+			"Always previews the current and desired values first; the change is only applied when dry_run is false. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.updateCluster)
+}
+
+func (h *handlers) updateCluster(ctx context.Context, _ *mcp.CallToolRequest, args *updateClusterArgs) (*mcp.CallToolResult, *updateClusterOutput, error) {
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	cluster, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster %s: %w", name, err)
+	}
+
+	out := &updateClusterOutput{
+		Cluster: name,
+		DryRun:  args.DryRun == nil || *args.DryRun,
+	}
+	var update *containerpb.ClusterUpdate
+	if args.ResourceLabels != nil {
+		if hasClusterUpdate(args) {
+			return nil, nil, fmt.Errorf("resource_labels can't be changed together with other settings, update them in a separate call")
+		}
+		out.Changes = labelChanges(cluster.GetResourceLabels(), args.ResourceLabels)
+	} else {
+		update, err = newClusterUpdate(cluster, args)
+		if err != nil {
+			return nil, nil, err
+		}
+		out.Changes, out.Unknown, err = clusterUpdateChanges(cluster, update)
+		if err != nil {
+			return nil, nil, err
+		}
+		if out.Update, err = toolresult.ProtoMap(update); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if len(out.Changes) == 0 && len(out.Unknown) == 0 {
+		res, err := toolresult.New(fmt.Sprintf("Cluster %s already has the requested settings, nothing to update.", args.Cluster), out)
+		return res, out, err
+	}
+	if out.DryRun {
+		summary := fmt.Sprintf("Preview of updating cluster %s, nothing was changed:\n\n%s\n\nShow these changes to the user and call again with dry_run set to false once they approve them.", args.Cluster, out.changesTable())
+		res, err := toolresult.New(summary, out)
+		return res, out, err
+	}
+
+	var op *containerpb.Operation
+	if update != nil {
+		op, err = h.cmClient.UpdateCluster(ctx, &containerpb.UpdateClusterRequest{Name: name, Update: update})
+	} else {
+		op, err = h.cmClient.SetLabels(ctx, &containerpb.SetLabelsRequest{
+			Name:             name,
+			ResourceLabels:   args.ResourceLabels,
+			LabelFingerprint: cluster.GetLabelFingerprint(),
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	s := newOperationSummary(op)
+	out.Operation = &s
+	summary := fmt.Sprintf("Started updating cluster %s, operation %s is %s. Use wait_operation to wait for it.\n\n%s", args.Cluster, s.Name, s.Status, out.changesTable())
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+// hasClusterUpdate reports whether args change anything UpdateCluster covers.
+func hasClusterUpdate(args *updateClusterArgs) bool {
+	return (args.Update != nil && proto.Size(args.Update) > 0) ||
+		args.LoggingComponents != nil || args.MonitoringComponents != nil ||
+		args.MasterAuthorizedNetworks != nil || args.AutoscalingProfile != ""
+}
+
+// newClusterUpdate builds the ClusterUpdate for args. The convenience
+// arguments start from the cluster's current configuration, so settings they
+// don't cover are kept.
+func newClusterUpdate(cluster *containerpb.Cluster, args *updateClusterArgs) (*containerpb.ClusterUpdate, error) {
+	if !hasClusterUpdate(args) {
+		return nil, fmt.Errorf("no changes requested: set update, logging_components, monitoring_components, master_authorized_networks, autoscaling_profile or resource_labels")
+	}
+	update := &containerpb.ClusterUpdate{}
+	if args.Update != nil {
+		update = proto.Clone(args.Update).(*containerpb.ClusterUpdate)
+	}
+
+	if args.LoggingComponents != nil {
+		cfg := &containerpb.LoggingComponentConfig{EnableComponents: []containerpb.LoggingComponentConfig_Component{}}
+		for _, c := range args.LoggingComponents {
+			v, ok := containerpb.LoggingComponentConfig_Component_value[strings.ToUpper(c)]
+			if !ok || v == int32(containerpb.LoggingComponentConfig_COMPONENT_UNSPECIFIED) {
+				return nil, fmt.Errorf("invalid logging component %q, must be one of %s", c, strings.Join(enumNames(containerpb.LoggingComponentConfig_Component_value), ", "))
+			}
+			cfg.EnableComponents = append(cfg.EnableComponents, containerpb.LoggingComponentConfig_Component(v))
+		}
+		update.DesiredLoggingConfig = &containerpb.LoggingConfig{ComponentConfig: cfg}
+	}
+
+	if args.MonitoringComponents != nil {
+		mc := &containerpb.MonitoringConfig{}
+		if cur := cluster.GetMonitoringConfig(); cur != nil {
+			mc = proto.Clone(cur).(*containerpb.MonitoringConfig)
+		}
+		mc.ComponentConfig = &containerpb.MonitoringComponentConfig{EnableComponents: []containerpb.MonitoringComponentConfig_Component{}}
+		for _, c := range args.MonitoringComponents {
+			v, ok := containerpb.MonitoringComponentConfig_Component_value[strings.ToUpper(c)]
+			if !ok || v == int32(containerpb.MonitoringComponentConfig_COMPONENT_UNSPECIFIED) {
+				return nil, fmt.Errorf("invalid monitoring component %q, must be one of %s", c, strings.Join(enumNames(containerpb.MonitoringComponentConfig_Component_value), ", "))
+			}
+			mc.ComponentConfig.EnableComponents = append(mc.ComponentConfig.EnableComponents, containerpb.MonitoringComponentConfig_Component(v))
+		}
+		update.DesiredMonitoringConfig = mc
+	}
+
+	if args.MasterAuthorizedNetworks != nil {
+		man := &containerpb.MasterAuthorizedNetworksConfig{}
+		if cur := cluster.GetMasterAuthorizedNetworksConfig(); cur != nil {
+			man = proto.Clone(cur).(*containerpb.MasterAuthorizedNetworksConfig)
+		}
+		man.Enabled = len(args.MasterAuthorizedNetworks) > 0
+		man.CidrBlocks = nil
+		for _, cidr := range args.MasterAuthorizedNetworks {
+			if _, _, err := net.ParseCIDR(cidr); err != nil {
+				return nil, fmt.Errorf("invalid master authorized network %q: %w", cidr, err)
+			}
+			man.CidrBlocks = append(man.CidrBlocks, &containerpb.MasterAuthorizedNetworksConfig_CidrBlock{CidrBlock: cidr})
+		}
+		update.DesiredMasterAuthorizedNetworksConfig = man
+	}
+
+	if args.AutoscalingProfile != "" {
+		v, ok := containerpb.ClusterAutoscaling_AutoscalingProfile_value[strings.ToUpper(args.AutoscalingProfile)]
+		if !ok || v == int32(containerpb.ClusterAutoscaling_PROFILE_UNSPECIFIED) {
+			return nil, fmt.Errorf("invalid autoscaling profile %q, must be one of %s", args.AutoscalingProfile, strings.Join(enumNames(containerpb.ClusterAutoscaling_AutoscalingProfile_value), ", "))
+		}
+		ca := &containerpb.ClusterAutoscaling{}
+		if cur := cluster.GetAutoscaling(); cur != nil {
+			ca = proto.Clone(cur).(*containerpb.ClusterAutoscaling)
+		}
+		ca.AutoscalingProfile = containerpb.ClusterAutoscaling_AutoscalingProfile(v)
+		update.DesiredClusterAutoscaling = ca
+	}
+	return update, nil
+}
+
+// enumNames returns the names of an enum's values, except the unspecified one.
+func enumNames(values map[string]int32) []string {
+	var names []string
+	for name, v := range values {
+		if v != 0 {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// clusterUpdateChanges compares the values set in update with the cluster's
+// current ones. Nested messages that update leaves unset are skipped, as GKE
+// keeps them. It also returns the update fields whose cluster field is
+// unknown.
+func clusterUpdateChanges(cluster *containerpb.Cluster, update *containerpb.ClusterUpdate) ([]fieldChange, []string, error) {
+	current, err := allFieldsMap(cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	desired, err := allFieldsMap(update)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var changes []fieldChange
+	var unknown []string
+	update.ProtoReflect().Range(func(fd protoreflect.FieldDescriptor, _ protoreflect.Value) bool {
+		jsonName := fd.JSONName()
+		field, ok := clusterField(jsonName)
+		if !ok {
+			unknown = append(unknown, jsonName)
+			return true
+		}
+		want := map[string]any{}
+		flattenJSON(field, desired[jsonName], want)
+		have := map[string]any{}
+		flattenJSON(field, lookupJSON(current, field), have)
+		for path, v := range want {
+			if !reflect.DeepEqual(have[path], v) {
+				changes = append(changes, fieldChange{Field: path, Current: have[path], Desired: v})
+			}
+		}
+		return true
+	})
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	sort.Strings(unknown)
+	return changes, unknown, nil
+}
+
+// clusterField returns the JSON path of the cluster field a ClusterUpdate
+// field changes.
+func clusterField(desired string) (string, bool) {
+	if field, ok := desiredClusterFields[desired]; ok {
+		return field, true
+	}
+	name, ok := strings.CutPrefix(desired, "desired")
+	if !ok || name == "" {
+		return "", false
+	}
+	name = strings.ToLower(name[:1]) + name[1:]
+	fields := (&containerpb.Cluster{}).ProtoReflect().Descriptor().Fields()
+	if fields.ByJSONName(name) == nil {
+		return "", false
+	}
+	return name, true
+}
+
+// allFieldsMap converts m to a JSON object like toolresult.ProtoMap, but
+// includes fields with zero values so they can be compared.
+func allFieldsMap(m proto.Message) (map[string]any, error) {
+	b, err := protojson.MarshalOptions{EmitUnpopulated: true}.Marshal(m)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal %s: %w", m.ProtoReflect().Descriptor().FullName(), err)
+	}
+	var out map[string]any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// lookupJSON returns the value at a dot-separated path in a JSON object, or
+// nil if there is none.
+func lookupJSON(m map[string]any, path string) any {
+	var v any = m
+	for _, key := range strings.Split(path, ".") {
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return nil
+		}
+		v = obj[key]
+	}
+	return v
+}
+
+// flattenJSON adds the non-null leaf values of v to out, keyed by their path
+// below prefix. Lists are leaves.
+func flattenJSON(prefix string, v any, out map[string]any) {
+	switch v := v.(type) {
+	case nil:
+	case map[string]any:
+		for k, child := range v {
+			flattenJSON(prefix+"."+k, child, out)
+		}
+	default:
+		out[prefix] = v
+	}
+}
+
+// labelChanges compares the current resource labels with the ones replacing
+// them.
+func labelChanges(current, desired map[string]string) []fieldChange {
+	var changes []fieldChange
+	for k, v := range desired {
+		if cur, ok := current[k]; !ok || cur != v {
+			c := fieldChange{Field: "resourceLabels." + k, Desired: v}
+			if ok {
+				c.Current = cur
+			}
+			changes = append(changes, c)
+		}
+	}
+	for k, v := range current {
+		if _, ok := desired[k]; !ok {
+			changes = append(changes, fieldChange{Field: "resourceLabels." + k, Current: v})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].Field < changes[j].Field })
+	return changes
+}
+
+// changesTable renders the changes as a text table.
+func (o *updateClusterOutput) changesTable() string {
+	var b strings.Builder
+	w := tabwriter.NewWriter(&b, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "FIELD\tCURRENT\tDESIRED")
+	for _, c := range o.Changes {
+		fmt.Fprintf(w, "%s\t%s\t%s\n", c.Field, jsonValue(c.Current), jsonValue(c.Desired))
+	}
+	_ = w.Flush()
+	table := strings.TrimRight(b.String(), "\n")
+	if len(o.Unknown) > 0 {
+		table += "\n\nCurrent values unknown, see the update for the desired values: " + strings.Join(o.Unknown, ", ")
+	}
+	return table
+}
+
+func jsonValue(v any) string {
+	if v == nil {
+		return "(unset)"
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Sprint(v)
+	}
+	return string(b)
+}
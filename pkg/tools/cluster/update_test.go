@@ -0,0 +1,240 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"strings"
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/testing/protocmp"
+)
+
+func TestDesiredClusterFields(t *testing.T) {
+	updateFields := (&containerpb.ClusterUpdate{}).ProtoReflect().Descriptor().Fields()
+	for desired, field := range desiredClusterFields {
+		if updateFields.ByJSONName(desired) == nil {
+			t.Errorf("ClusterUpdate has no field %q", desired)
+		}
+		md := (&containerpb.Cluster{}).ProtoReflect().Descriptor()
+		parts := strings.Split(field, ".")
+		for i, part := range parts {
+			fd := md.Fields().ByJSONName(part)
+			if fd == nil {
+				t.Errorf("%s: Cluster has no field %q", desired, strings.Join(parts[:i+1], "."))
+				break
+			}
+			if i < len(parts)-1 {
+				if fd.Kind() != protoreflect.MessageKind {
+					t.Errorf("%s: Cluster field %q isn't a message", desired, strings.Join(parts[:i+1], "."))
+					break
+				}
+				md = fd.Message()
+			}
+		}
+	}
+}
+
+func TestNewClusterUpdate(t *testing.T) {
+	cluster := &containerpb.Cluster{
+		MonitoringConfig: &containerpb.MonitoringConfig{
+			ComponentConfig:         &containerpb.MonitoringComponentConfig{EnableComponents: []containerpb.MonitoringComponentConfig_Component{containerpb.MonitoringComponentConfig_SYSTEM_COMPONENTS}},
+			ManagedPrometheusConfig: &containerpb.ManagedPrometheusConfig{Enabled: true},
+		},
+		Autoscaling: &containerpb.ClusterAutoscaling{EnableNodeAutoprovisioning: true},
+		MasterAuthorizedNetworksConfig: &containerpb.MasterAuthorizedNetworksConfig{
+			Enabled:                     true,
+			CidrBlocks:                  []*containerpb.MasterAuthorizedNetworksConfig_CidrBlock{{CidrBlock: "10.0.0.0/8"}},
+			GcpPublicCidrsAccessEnabled: boolPtr(true),
+		},
+	}
+	tests := []struct {
+		name    string
+		args    updateClusterArgs
+		want    *containerpb.ClusterUpdate
+		wantErr bool
+	}{
+		{name: "no changes", wantErr: true},
+		{
+			name: "raw update",
+			args: updateClusterArgs{Update: &containerpb.ClusterUpdate{DesiredLoggingService: "none"}},
+			want: &containerpb.ClusterUpdate{DesiredLoggingService: "none"},
+		},
+		{
+			name: "logging components",
+			args: updateClusterArgs{LoggingComponents: []string{"system_components", "WORKLOADS"}},
+			want: &containerpb.ClusterUpdate{DesiredLoggingConfig: &containerpb.LoggingConfig{
+				ComponentConfig: &containerpb.LoggingComponentConfig{EnableComponents: []containerpb.LoggingComponentConfig_Component{
+					containerpb.LoggingComponentConfig_SYSTEM_COMPONENTS, containerpb.LoggingComponentConfig_WORKLOADS,
+				}},
+			}},
+		},
+		{
+			name:    "invalid logging component",
+			args:    updateClusterArgs{LoggingComponents: []string{"EVERYTHING"}},
+			wantErr: true,
+		},
+		{
+			name: "monitoring components keep managed prometheus",
+			args: updateClusterArgs{MonitoringComponents: []string{"APISERVER"}},
+			want: &containerpb.ClusterUpdate{DesiredMonitoringConfig: &containerpb.MonitoringConfig{
+				ComponentConfig:         &containerpb.MonitoringComponentConfig{EnableComponents: []containerpb.MonitoringComponentConfig_Component{containerpb.MonitoringComponentConfig_APISERVER}},
+				ManagedPrometheusConfig: &containerpb.ManagedPrometheusConfig{Enabled: true},
+			}},
+		},
+		{
+			name: "master authorized networks",
+			args: updateClusterArgs{MasterAuthorizedNetworks: []string{"192.168.0.0/16"}},
+			want: &containerpb.ClusterUpdate{DesiredMasterAuthorizedNetworksConfig: &containerpb.MasterAuthorizedNetworksConfig{
+				Enabled:                     true,
+				CidrBlocks:                  []*containerpb.MasterAuthorizedNetworksConfig_CidrBlock{{CidrBlock: "192.168.0.0/16"}},
+				GcpPublicCidrsAccessEnabled: boolPtr(true),
+			}},
+		},
+		{
+			name: "disable master authorized networks",
+			args: updateClusterArgs{MasterAuthorizedNetworks: []string{}},
+			want: &containerpb.ClusterUpdate{DesiredMasterAuthorizedNetworksConfig: &containerpb.MasterAuthorizedNetworksConfig{
+				GcpPublicCidrsAccessEnabled: boolPtr(true),
+			}},
+		},
+		{
+			name:    "invalid CIDR",
+			args:    updateClusterArgs{MasterAuthorizedNetworks: []string{"10.0.0.1"}},
+			wantErr: true,
+		},
+		{
+			name: "autoscaling profile keeps node auto-provisioning",
+			args: updateClusterArgs{AutoscalingProfile: "optimize_utilization"},
+			want: &containerpb.ClusterUpdate{DesiredClusterAutoscaling: &containerpb.ClusterAutoscaling{
+				EnableNodeAutoprovisioning: true,
+				AutoscalingProfile:         containerpb.ClusterAutoscaling_OPTIMIZE_UTILIZATION,
+			}},
+		},
+		{
+			name:    "invalid autoscaling profile",
+			args:    updateClusterArgs{AutoscalingProfile: "FAST"},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := newClusterUpdate(cluster, &tt.args)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("newClusterUpdate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, got, protocmp.Transform()); diff != "" {
+				t.Errorf("newClusterUpdate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+	if cluster.GetAutoscaling().GetAutoscalingProfile() != containerpb.ClusterAutoscaling_PROFILE_UNSPECIFIED {
+		t.Errorf("newClusterUpdate() modified the cluster")
+	}
+}
+
+func TestClusterUpdateChanges(t *testing.T) {
+	cluster := &containerpb.Cluster{
+		LoggingService: "logging.googleapis.com/kubernetes",
+		LoggingConfig: &containerpb.LoggingConfig{
+			ComponentConfig: &containerpb.LoggingComponentConfig{EnableComponents: []containerpb.LoggingComponentConfig_Component{containerpb.LoggingComponentConfig_SYSTEM_COMPONENTS}},
+		},
+		VerticalPodAutoscaling: &containerpb.VerticalPodAutoscaling{Enabled: true},
+		NetworkConfig:          &containerpb.NetworkConfig{DatapathProvider: containerpb.DatapathProvider_LEGACY_DATAPATH},
+		AddonsConfig: &containerpb.AddonsConfig{
+			HttpLoadBalancing:        &containerpb.HttpLoadBalancing{},
+			HorizontalPodAutoscaling: &containerpb.HorizontalPodAutoscaling{},
+		},
+	}
+	tests := []struct {
+		name        string
+		update      *containerpb.ClusterUpdate
+		wantChanges []fieldChange
+		wantUnknown []string
+	}{
+		{
+			name:   "unchanged",
+			update: &containerpb.ClusterUpdate{DesiredLoggingService: "logging.googleapis.com/kubernetes"},
+		},
+		{
+			name:        "scalar",
+			update:      &containerpb.ClusterUpdate{DesiredLoggingService: "none"},
+			wantChanges: []fieldChange{{Field: "loggingService", Current: "logging.googleapis.com/kubernetes", Desired: "none"}},
+		},
+		{
+			name: "list",
+			update: &containerpb.ClusterUpdate{DesiredLoggingConfig: &containerpb.LoggingConfig{
+				ComponentConfig: &containerpb.LoggingComponentConfig{EnableComponents: []containerpb.LoggingComponentConfig_Component{}},
+			}},
+			wantChanges: []fieldChange{{Field: "loggingConfig.componentConfig.enableComponents", Current: []any{"SYSTEM_COMPONENTS"}, Desired: []any{}}},
+		},
+		{
+			name:        "zero value",
+			update:      &containerpb.ClusterUpdate{DesiredVerticalPodAutoscaling: &containerpb.VerticalPodAutoscaling{}},
+			wantChanges: []fieldChange{{Field: "verticalPodAutoscaling.enabled", Current: true, Desired: false}},
+		},
+		{
+			name:        "renamed field",
+			update:      &containerpb.ClusterUpdate{DesiredDatapathProvider: containerpb.DatapathProvider_ADVANCED_DATAPATH},
+			wantChanges: []fieldChange{{Field: "networkConfig.datapathProvider", Current: "LEGACY_DATAPATH", Desired: "ADVANCED_DATAPATH"}},
+		},
+		{
+			name: "unset addons are kept",
+			update: &containerpb.ClusterUpdate{DesiredAddonsConfig: &containerpb.AddonsConfig{
+				HttpLoadBalancing: &containerpb.HttpLoadBalancing{Disabled: true},
+			}},
+			wantChanges: []fieldChange{{Field: "addonsConfig.httpLoadBalancing.disabled", Current: false, Desired: true}},
+		},
+		{
+			name:        "previously unset",
+			update:      &containerpb.ClusterUpdate{DesiredShieldedNodes: &containerpb.ShieldedNodes{Enabled: true}},
+			wantChanges: []fieldChange{{Field: "shieldedNodes.enabled", Desired: true}},
+		},
+		{
+			name:        "unknown cluster field",
+			update:      &containerpb.ClusterUpdate{DesiredNodePoolId: "default-pool"},
+			wantUnknown: []string{"desiredNodePoolId"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			changes, unknown, err := clusterUpdateChanges(cluster, tt.update)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if diff := cmp.Diff(tt.wantChanges, changes); diff != "" {
+				t.Errorf("clusterUpdateChanges() changes mismatch (-want +got):\n%s", diff)
+			}
+			if diff := cmp.Diff(tt.wantUnknown, unknown); diff != "" {
+				t.Errorf("clusterUpdateChanges() unknown mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLabelChanges(t *testing.T) {
+	current := map[string]string{"env": "prod", "team": "a", "old": "x"}
+	desired := map[string]string{"env": "prod", "team": "b", "new": "y"}
+	want := []fieldChange{
+		{Field: "resourceLabels.new", Desired: "y"},
+		{Field: "resourceLabels.old", Current: "x"},
+		{Field: "resourceLabels.team", Current: "a", Desired: "b"},
+	}
+	if diff := cmp.Diff(want, labelChanges(current, desired)); diff != "" {
+		t.Errorf("labelChanges() mismatch (-want +got):\n%s", diff)
+	}
+}
@@ -0,0 +1,352 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+// maxNodeVersionSkew is how many minor versions older than the control plane
+// GKE supports node pools to be.
+const maxNodeVersionSkew = 2
+
+type upgradeClusterArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	Version   string `json:"version" jsonschema:"Target control plane version, e.g. 1.33.5-gke.1000."`
+	Confirm   bool   `json:"confirm,omitempty" jsonschema:"Set to true only after the user has approved this exact upgrade. When false, only the pre-flight checks run."`
+}
+
+type upgradeNodePoolArgs struct {
+	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
+	Location  string `json:"location,omitempty" jsonschema:"GKE cluster location. Leave this empty if the user doesn't provide it."`
+	Cluster   string `json:"cluster" jsonschema:"GKE cluster name. Do not select it yourself, make sure the user provides or confirms the cluster name."`
+	NodePool  string `json:"node_pool" jsonschema:"Node pool name."`
+	Version   string `json:"version,omitempty" jsonschema:"Target node version. Defaults to the control plane version."`
+	Confirm   bool   `json:"confirm,omitempty" jsonschema:"Set to true only after the user has approved this exact upgrade. When false, only the pre-flight checks run."`
+}
+
+// preflightCheck is the result of one check run before an upgrade.
+type preflightCheck struct {
+	Check  string `json:"check"`
+	Passed bool   `json:"passed"`
+	Detail string `json:"detail,omitempty"`
+}
+
+type upgradeOutput struct {
+	Cluster        string            `json:"cluster" jsonschema:"Full resource name of the cluster."`
+	NodePool       string            `json:"node_pool,omitempty"`
+	ReleaseChannel string            `json:"release_channel,omitempty"`
+	CurrentVersion string            `json:"current_version"`
+	TargetVersion  string            `json:"target_version"`
+	Checks         []preflightCheck  `json:"checks"`
+	Operation      *operationSummary `json:"operation,omitempty" jsonschema:"The upgrade operation, if the upgrade was started."`
+}
+
+// installUpgrades registers the upgrade tools.
+func (h *handlers) installUpgrades(s *mcp.Server) {
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "upgrade_cluster",
+		Description: "Upgrade the control plane of a GKE cluster to a new version. Runs pre-flight checks against the versions available in the cluster's release channel and the node version skew rules first, and only upgrades when confirm is true. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.upgradeCluster)
+
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "upgrade_node_pool",
+		Description: "Upgrade the nodes of a GKE node pool to a new version, by default the control plane version. Runs pre-flight checks first and only upgrades when confirm is true. Prefer to use this tool instead of gcloud",
+		Annotations: &mcp.ToolAnnotations{
+			DestructiveHint: boolPtr(true),
+		},
+	}, h.upgradeNodePool)
+}
+
+func (h *handlers) upgradeCluster(ctx context.Context, _ *mcp.CallToolRequest, args *upgradeClusterArgs) (*mcp.CallToolResult, *upgradeOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.Version == "" {
+		return nil, nil, fmt.Errorf("version argument cannot be empty")
+	}
+
+	cluster, sc, err := h.clusterAndServerConfig(ctx, name, args.ProjectID, args.Location)
+	if err != nil {
+		return nil, nil, err
+	}
+	out := &upgradeOutput{
+		Cluster:        name,
+		ReleaseChannel: newClusterInfo(cluster).ReleaseChannel,
+		CurrentVersion: cluster.GetCurrentMasterVersion(),
+		TargetVersion:  args.Version,
+		Checks:         checkClusterUpgrade(cluster, sc, args.Version),
+	}
+	what := fmt.Sprintf("control plane of cluster %s", args.Cluster)
+	if res, ok, err := preflightResult(out, what, args.Confirm); !ok {
+		return res, out, err
+	}
+
+	op, err := h.cmClient.UpdateMaster(ctx, &containerpb.UpdateMasterRequest{
+		Name:          name,
+		MasterVersion: args.Version,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return upgradeStartedResult(out, what, op)
+}
+
+func (h *handlers) upgradeNodePool(ctx context.Context, _ *mcp.CallToolRequest, args *upgradeNodePoolArgs) (*mcp.CallToolResult, *upgradeOutput, error) {
+	if args.ProjectID == "" {
+		args.ProjectID = h.c.DefaultProjectID()
+	}
+	if args.Location == "" {
+		args.Location = h.c.DefaultLocation()
+	}
+	name, err := h.clusterName(args.ProjectID, args.Location, args.Cluster)
+	if err != nil {
+		return nil, nil, err
+	}
+	if args.NodePool == "" {
+		return nil, nil, fmt.Errorf("node_pool argument cannot be empty")
+	}
+
+	cluster, sc, err := h.clusterAndServerConfig(ctx, name, args.ProjectID, args.Location)
+	if err != nil {
+		return nil, nil, err
+	}
+	i := slices.IndexFunc(cluster.GetNodePools(), func(np *containerpb.NodePool) bool { return np.GetName() == args.NodePool })
+	if i < 0 {
+		return nil, nil, fmt.Errorf("node pool %s not found in cluster %s", args.NodePool, args.Cluster)
+	}
+	pool := cluster.GetNodePools()[i]
+	if args.Version == "" {
+		args.Version = cluster.GetCurrentMasterVersion()
+	}
+
+	out := &upgradeOutput{
+		Cluster:        name,
+		NodePool:       args.NodePool,
+		ReleaseChannel: newClusterInfo(cluster).ReleaseChannel,
+		CurrentVersion: pool.GetVersion(),
+		TargetVersion:  args.Version,
+		Checks:         checkNodePoolUpgrade(cluster, pool, sc, args.Version),
+	}
+	what := fmt.Sprintf("node pool %s of cluster %s", args.NodePool, args.Cluster)
+	if res, ok, err := preflightResult(out, what, args.Confirm); !ok {
+		return res, out, err
+	}
+
+	op, err := h.cmClient.UpdateNodePool(ctx, &containerpb.UpdateNodePoolRequest{
+		Name:        name + "/nodePools/" + args.NodePool,
+		NodeVersion: args.Version,
+		// Keep the current image type; the API requires one.
+		ImageType: pool.GetConfig().GetImageType(),
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return upgradeStartedResult(out, what, op)
+}
+
+func (h *handlers) clusterAndServerConfig(ctx context.Context, name, projectID, location string) (*containerpb.Cluster, *containerpb.ServerConfig, error) {
+	cluster, err := h.cmClient.GetCluster(ctx, &containerpb.GetClusterRequest{Name: name})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get cluster: %w", err)
+	}
+	sc, err := h.cmClient.GetServerConfig(ctx, &containerpb.GetServerConfigRequest{
+		Name: fmt.Sprintf("projects/%s/locations/%s", projectID, location),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get server config: %w", err)
+	}
+	return cluster, sc, nil
+}
+
+// preflightResult reports whether the upgrade in out may go ahead. If not, it
+// returns the result to send instead: an error if a check failed, or the
+// passed checks if the upgrade isn't confirmed.
+func preflightResult(out *upgradeOutput, what string, confirm bool) (*mcp.CallToolResult, bool, error) {
+	var failed []string
+	for _, c := range out.Checks {
+		if !c.Passed {
+			failed = append(failed, fmt.Sprintf("- %s: %s", c.Check, c.Detail))
+		}
+	}
+	if len(failed) > 0 {
+		return nil, false, fmt.Errorf("pre-flight checks failed for upgrading the %s from %s to %s:\n%s", what, out.CurrentVersion, out.TargetVersion, strings.Join(failed, "\n"))
+	}
+	if confirm {
+		return nil, true, nil
+	}
+	summary := fmt.Sprintf("Pre-flight checks passed for upgrading the %s from %s to %s. Nothing was changed: call again with confirm set to true once the user has approved the upgrade.", what, out.CurrentVersion, out.TargetVersion)
+	res, err := toolresult.New(summary, out)
+	return res, false, err
+}
+
+func upgradeStartedResult(out *upgradeOutput, what string, op *containerpb.Operation) (*mcp.CallToolResult, *upgradeOutput, error) {
+	summary := newOperationSummary(op)
+	out.Operation = &summary
+	text := fmt.Sprintf("Started upgrading the %s from %s to %s, operation %s is %s. Use wait_operation to wait for it.", what, out.CurrentVersion, out.TargetVersion, summary.Name, summary.Status)
+	res, err := toolresult.New(text, out)
+	return res, out, err
+}
+
+// checkClusterUpgrade runs the pre-flight checks for upgrading the control
+// plane of c to target.
+func checkClusterUpgrade(c *containerpb.Cluster, sc *containerpb.ServerConfig, target string) []preflightCheck {
+	to, err := parseGKEVersion(target)
+	if err != nil {
+		return []preflightCheck{{Check: "version format", Detail: err.Error()}}
+	}
+	checks := []preflightCheck{
+		statusCheck("cluster status", c.GetStatus() == containerpb.Cluster_RUNNING, c.GetStatus().String()),
+		availableCheck(target, clusterVersions(c, sc)),
+	}
+
+	from, err := parseGKEVersion(c.GetCurrentMasterVersion())
+	if err != nil {
+		return explainFailures(append(checks, preflightCheck{Check: "newer than current version", Detail: err.Error()}))
+	}
+	checks = append(checks, newerCheck(from, to, c.GetCurrentMasterVersion()))
+	checks = append(checks, preflightCheck{
+		Check:  "one minor version at a time",
+		Passed: from.minorsBehind(to) <= 1,
+		Detail: fmt.Sprintf("the control plane can only be upgraded to the next minor version, %d.%d", from.major, from.minor+1),
+	})
+
+	var tooOld []string
+	for _, np := range c.GetNodePools() {
+		v, err := parseGKEVersion(np.GetVersion())
+		if err != nil || v.minorsBehind(to) > maxNodeVersionSkew {
+			tooOld = append(tooOld, fmt.Sprintf("%s (%s)", np.GetName(), np.GetVersion()))
+		}
+	}
+	checks = append(checks, preflightCheck{
+		Check:  "node version skew",
+		Passed: len(tooOld) == 0,
+		Detail: fmt.Sprintf("node pools %s would be more than %d minor versions older than the control plane at %s; upgrade them first", strings.Join(tooOld, ", "), maxNodeVersionSkew, to.minorString()),
+	})
+	return explainFailures(checks)
+}
+
+// checkNodePoolUpgrade runs the pre-flight checks for upgrading node pool np
+// of c to target.
+func checkNodePoolUpgrade(c *containerpb.Cluster, np *containerpb.NodePool, sc *containerpb.ServerConfig, target string) []preflightCheck {
+	to, err := parseGKEVersion(target)
+	if err != nil {
+		return []preflightCheck{{Check: "version format", Detail: err.Error()}}
+	}
+	checks := []preflightCheck{
+		statusCheck("cluster status", c.GetStatus() == containerpb.Cluster_RUNNING, c.GetStatus().String()),
+		statusCheck("node pool status", np.GetStatus() == containerpb.NodePool_RUNNING, np.GetStatus().String()),
+		availableCheck(target, nodeVersions(c, sc)),
+	}
+
+	if from, err := parseGKEVersion(np.GetVersion()); err != nil {
+		checks = append(checks, preflightCheck{Check: "newer than current version", Detail: err.Error()})
+	} else {
+		checks = append(checks, newerCheck(from, to, np.GetVersion()))
+	}
+
+	master, err := parseGKEVersion(c.GetCurrentMasterVersion())
+	if err != nil {
+		return explainFailures(append(checks, preflightCheck{Check: "not newer than control plane", Detail: err.Error()}))
+	}
+	checks = append(checks, preflightCheck{
+		Check:  "not newer than control plane",
+		Passed: to.compare(master) <= 0,
+		Detail: fmt.Sprintf("nodes can't run a newer version than the control plane (%s); upgrade the control plane first", c.GetCurrentMasterVersion()),
+	})
+	checks = append(checks, preflightCheck{
+		Check:  "node version skew",
+		Passed: to.minorsBehind(master) <= maxNodeVersionSkew,
+		Detail: fmt.Sprintf("nodes can be at most %d minor versions older than the control plane (%s)", maxNodeVersionSkew, master.minorString()),
+	})
+	return explainFailures(checks)
+}
+
+// explainFailures drops the details of the checks that passed, which only
+// explain failures.
+func explainFailures(checks []preflightCheck) []preflightCheck {
+	for i := range checks {
+		if checks[i].Passed {
+			checks[i].Detail = ""
+		}
+	}
+	return checks
+}
+
+// clusterVersions returns the control plane versions c can use: those of its
+// release channel, or all valid versions if it isn't enrolled in one.
+func clusterVersions(c *containerpb.Cluster, sc *containerpb.ServerConfig) []string {
+	return releaseChannelVersions(c, sc, sc.GetValidMasterVersions())
+}
+
+// nodeVersions returns the node versions c can use: those of its release
+// channel, or all valid node versions if it isn't enrolled in one.
+func nodeVersions(c *containerpb.Cluster, sc *containerpb.ServerConfig) []string {
+	return releaseChannelVersions(c, sc, sc.GetValidNodeVersions())
+}
+
+func releaseChannelVersions(c *containerpb.Cluster, sc *containerpb.ServerConfig, unenrolled []string) []string {
+	channel := c.GetReleaseChannel().GetChannel()
+	if channel == containerpb.ReleaseChannel_UNSPECIFIED {
+		return unenrolled
+	}
+	for _, cc := range sc.GetChannels() {
+		if cc.GetChannel() == channel {
+			return cc.GetValidVersions()
+		}
+	}
+	return nil
+}
+
+func statusCheck(check string, ok bool, status string) preflightCheck {
+	return preflightCheck{Check: check, Passed: ok, Detail: fmt.Sprintf("status is %s, want RUNNING", status)}
+}
+
+func availableCheck(target string, versions []string) preflightCheck {
+	c := preflightCheck{Check: "version available", Passed: slices.Contains(versions, target)}
+	if len(versions) == 0 {
+		c.Detail = "no versions are available"
+	} else {
+		c.Detail = fmt.Sprintf("%s isn't one of the available versions %s", target, strings.Join(versions, ", "))
+	}
+	return c
+}
+
+func newerCheck(from, to gkeVersion, current string) preflightCheck {
+	return preflightCheck{
+		Check:  "newer than current version",
+		Passed: to.compare(from) > 0,
+		Detail: fmt.Sprintf("the current version is %s; downgrades and no-op upgrades aren't allowed", current),
+	}
+}
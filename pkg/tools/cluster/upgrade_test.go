@@ -0,0 +1,165 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"testing"
+
+	containerpb "cloud.google.com/go/container/apiv1/containerpb"
+	"github.com/google/go-cmp/cmp"
+)
+
+var testServerConfig = &containerpb.ServerConfig{
+	ValidMasterVersions: []string{"1.34.1-gke.100", "1.33.5-gke.1000", "1.32.9-gke.500"},
+	ValidNodeVersions:   []string{"1.34.1-gke.100", "1.33.5-gke.1000", "1.32.9-gke.500", "1.31.12-gke.300"},
+	Channels: []*containerpb.ServerConfig_ReleaseChannelConfig{
+		{Channel: containerpb.ReleaseChannel_REGULAR, ValidVersions: []string{"1.33.5-gke.1000", "1.32.9-gke.500"}},
+	},
+}
+
+func testUpgradeCluster(master string, pools ...string) *containerpb.Cluster {
+	c := &containerpb.Cluster{
+		Name:                 "prod",
+		Status:               containerpb.Cluster_RUNNING,
+		CurrentMasterVersion: master,
+		ReleaseChannel:       &containerpb.ReleaseChannel{Channel: containerpb.ReleaseChannel_REGULAR},
+	}
+	for i, v := range pools {
+		c.NodePools = append(c.NodePools, &containerpb.NodePool{
+			Name:    string(rune('a' + i)),
+			Version: v,
+			Status:  containerpb.NodePool_RUNNING,
+		})
+	}
+	return c
+}
+
+// unenrolled removes c from its release channel.
+func unenrolled(c *containerpb.Cluster) *containerpb.Cluster {
+	c.ReleaseChannel = nil
+	return c
+}
+
+// failedChecks returns the names of the checks that didn't pass.
+func failedChecks(checks []preflightCheck) []string {
+	var failed []string
+	for _, c := range checks {
+		if !c.Passed {
+			failed = append(failed, c.Check)
+		} else if c.Detail != "" {
+			failed = append(failed, "passed check with detail: "+c.Check)
+		}
+	}
+	return failed
+}
+
+func TestCheckClusterUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *containerpb.Cluster
+		target  string
+		want    []string
+	}{
+		{
+			name:    "next minor in channel",
+			cluster: testUpgradeCluster("1.32.9-gke.500", "1.32.9-gke.500"),
+			target:  "1.33.5-gke.1000",
+		},
+		{
+			name:    "not in channel",
+			cluster: testUpgradeCluster("1.33.5-gke.1000", "1.33.5-gke.1000"),
+			target:  "1.34.1-gke.100",
+			want:    []string{"version available"},
+		},
+		{
+			name:    "downgrade",
+			cluster: testUpgradeCluster("1.33.5-gke.1000", "1.33.5-gke.1000"),
+			target:  "1.32.9-gke.500",
+			want:    []string{"newer than current version"},
+		},
+		{
+			name:    "skips a minor version and leaves nodes behind",
+			cluster: &containerpb.Cluster{Status: containerpb.Cluster_RUNNING, CurrentMasterVersion: "1.32.9-gke.500", NodePools: []*containerpb.NodePool{{Name: "old", Version: "1.31.12-gke.300"}}},
+			target:  "1.34.1-gke.100",
+			want:    []string{"one minor version at a time", "node version skew"},
+		},
+		{
+			name:    "invalid version",
+			cluster: testUpgradeCluster("1.32.9-gke.500"),
+			target:  "latest",
+			want:    []string{"version format"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedChecks(checkClusterUpgrade(tt.cluster, testServerConfig, tt.target))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("failed checks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestCheckNodePoolUpgrade(t *testing.T) {
+	tests := []struct {
+		name    string
+		cluster *containerpb.Cluster
+		target  string
+		want    []string
+	}{
+		{
+			name:    "to control plane version",
+			cluster: testUpgradeCluster("1.33.5-gke.1000", "1.32.9-gke.500"),
+			target:  "1.33.5-gke.1000",
+		},
+		{
+			name:    "newer than control plane",
+			cluster: unenrolled(testUpgradeCluster("1.33.5-gke.1000", "1.32.9-gke.500")),
+			target:  "1.34.1-gke.100",
+			want:    []string{"not newer than control plane"},
+		},
+		{
+			name:    "still too old",
+			cluster: unenrolled(testUpgradeCluster("1.34.1-gke.100", "1.30.1-gke.1")),
+			target:  "1.31.12-gke.300",
+			want:    []string{"node version skew"},
+		},
+		{
+			name:    "not in channel",
+			cluster: testUpgradeCluster("1.34.1-gke.100", "1.33.5-gke.1000"),
+			target:  "1.34.1-gke.100",
+			want:    []string{"version available"},
+		},
+		{
+			name:    "valid node version without a channel",
+			cluster: unenrolled(testUpgradeCluster("1.34.1-gke.100", "1.33.5-gke.1000")),
+			target:  "1.34.1-gke.100",
+		},
+		{
+			name:    "unknown version",
+			cluster: testUpgradeCluster("1.33.5-gke.1000", "1.32.9-gke.500"),
+			target:  "1.33.4-gke.1",
+			want:    []string{"version available"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := failedChecks(checkNodePoolUpgrade(tt.cluster, tt.cluster.GetNodePools()[0], testServerConfig, tt.target))
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("failed checks mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
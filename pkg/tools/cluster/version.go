@@ -0,0 +1,76 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import (
+	"cmp"
+	"fmt"
+	"regexp"
+	"strconv"
+)
+
+// gkeVersion is a GKE version such as 1.33.5-gke.1000.
+type gkeVersion struct {
+	major, minor, patch int
+	// gke is the GKE build number, 0 if the version has none.
+	gke int
+}
+
+var gkeVersionRE = regexp.MustCompile(`^v?(\d+)\.(\d+)\.(\d+)(?:-gke\.(\d+))?$`)
+
+func parseGKEVersion(s string) (gkeVersion, error) {
+	m := gkeVersionRE.FindStringSubmatch(s)
+	if m == nil {
+		return gkeVersion{}, fmt.Errorf("invalid GKE version %q, want a version like 1.33.5-gke.1000", s)
+	}
+	var v gkeVersion
+	v.major, _ = strconv.Atoi(m[1])
+	v.minor, _ = strconv.Atoi(m[2])
+	v.patch, _ = strconv.Atoi(m[3])
+	if m[4] != "" {
+		v.gke, _ = strconv.Atoi(m[4])
+	}
+	return v, nil
+}
+
+// compare returns -1, 0 or +1 depending on whether v is older than, the same
+// as or newer than w.
+func (v gkeVersion) compare(w gkeVersion) int {
+	if c := cmp.Compare(v.major, w.major); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.minor, w.minor); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(v.patch, w.patch); c != 0 {
+		return c
+	}
+	return cmp.Compare(v.gke, w.gke)
+}
+
+// minorsBehind returns how many minor versions v is older than w, which is
+// negative if v is newer.
+func (v gkeVersion) minorsBehind(w gkeVersion) int {
+	if v.major != w.major {
+		// Kubernetes has only ever had major version 1; treat any other
+		// major version difference as an unbounded skew.
+		return 100 * (w.major - v.major)
+	}
+	return w.minor - v.minor
+}
+
+func (v gkeVersion) minorString() string {
+	return fmt.Sprintf("%d.%d", v.major, v.minor)
+}
@@ -0,0 +1,51 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cluster
+
+import "testing"
+
+func TestGKEVersionCompare(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want int
+	}{
+		{"1.33.5-gke.1000", "1.33.5-gke.1000", 0},
+		{"1.33.5-gke.1000", "1.33.5-gke.999", 1},
+		{"1.33.5-gke.1000", "1.33.10-gke.1", -1},
+		{"1.32.9-gke.1", "1.33.0-gke.1", -1},
+		{"v1.33.5", "1.33.5-gke.1", -1},
+	}
+	for _, tt := range tests {
+		a, err := parseGKEVersion(tt.a)
+		if err != nil {
+			t.Fatalf("parseGKEVersion(%q) error = %v", tt.a, err)
+		}
+		b, err := parseGKEVersion(tt.b)
+		if err != nil {
+			t.Fatalf("parseGKEVersion(%q) error = %v", tt.b, err)
+		}
+		if got := a.compare(b); got != tt.want {
+			t.Errorf("compare(%s, %s) = %d, want %d", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestParseGKEVersionInvalid(t *testing.T) {
+	for _, s := range []string{"", "latest", "1.33", "1.33.x-gke.1"} {
+		if _, err := parseGKEVersion(s); err == nil {
+			t.Errorf("parseGKEVersion(%q) error = nil, want error", s)
+		}
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 )
 
@@ -31,6 +32,10 @@ type clusterToolkitDownloadArgs struct {
 	DownloadDirectory string `json:"download_directory" jsonschema:"Download directory for the git repo. By default use the absolute path to the current working directory."`
 }
 
+type clusterToolkitDownloadOutput struct {
+	Directory string `json:"directory" jsonschema:"Directory the Cluster Toolkit repository was cloned into."`
+}
+
 // Install registers Cluster Toolkit tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	mcp.AddTool(s, &mcp.Tool{
@@ -41,7 +46,7 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	return nil
 }
 
-func clusterToolkitDownload(_ context.Context, _ *mcp.CallToolRequest, args *clusterToolkitDownloadArgs) (*mcp.CallToolResult, any, error) {
+func clusterToolkitDownload(_ context.Context, _ *mcp.CallToolRequest, args *clusterToolkitDownloadArgs) (*mcp.CallToolResult, *clusterToolkitDownloadOutput, error) {
 	if args.DownloadDirectory == "" {
 		return nil, nil, fmt.Errorf("download_directory argument cannot be empty")
 	}
@@ -57,9 +62,7 @@ func clusterToolkitDownload(_ context.Context, _ *mcp.CallToolRequest, args *clu
 		return nil, nil, err
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: string(out)},
-		},
-	}, nil, nil
+	result := &clusterToolkitDownloadOutput{Directory: downloadDir}
+	res, err := toolresult.New(fmt.Sprintf("Downloaded Cluster Toolkit to %s.", downloadDir), result)
+	return res, result, err
 }
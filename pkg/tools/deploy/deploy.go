@@ -68,6 +68,10 @@ type deployArgs struct {
 	UserRequest string `json:"user_request" jsonschema:"A natural language request specifying the configuration file to deploy. e.g. 'my-app.yaml to staging'"`
 }
 
+type deployOutput struct {
+	Instructions string `json:"instructions" jsonschema:"Step-by-step guidance for deploying the requested workload."`
+}
+
 // Install registers deployment tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	mcp.AddTool(s, &mcp.Tool{
@@ -78,7 +82,7 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	return nil
 }
 
-func gkeDeployHandler(_ context.Context, _ *mcp.CallToolRequest, args *deployArgs) (*mcp.CallToolResult, any, error) {
+func gkeDeployHandler(_ context.Context, _ *mcp.CallToolRequest, args *deployArgs) (*mcp.CallToolResult, *deployOutput, error) {
 	if strings.TrimSpace(args.UserRequest) == "" {
 		return nil, nil, fmt.Errorf("argument 'user_request' cannot be empty")
 	}
@@ -94,5 +98,5 @@ func gkeDeployHandler(_ context.Context, _ *mcp.CallToolRequest, args *deployArg
 				Text: buf.String(),
 			},
 		},
-	}, nil, nil
+	}, &deployOutput{Instructions: buf.String()}, nil
 }
@@ -32,6 +32,10 @@ type giqGenerateManifestArgs struct {
 	TargetNTPOTMilliseconds string `json:"target_ntpot_milliseconds,omitempty" jsonschema:"The maximum normalized time per output token (NTPOT) in milliseconds.NTPOT is measured as the request_latency / output_tokens."`
 }
 
+type giqGenerateManifestOutput struct {
+	Manifest string `json:"manifest" jsonschema:"Kubernetes manifest YAML for the inference workload."`
+}
+
 // Install registers GIQ tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	mcp.AddTool(s, &mcp.Tool{
@@ -46,7 +50,7 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	return nil
 }
 
-func giqGenerateManifest(_ context.Context, _ *mcp.CallToolRequest, args *giqGenerateManifestArgs) (*mcp.CallToolResult, any, error) {
+func giqGenerateManifest(_ context.Context, _ *mcp.CallToolRequest, args *giqGenerateManifestArgs) (*mcp.CallToolResult, *giqGenerateManifestOutput, error) {
 	if args.Model == "" {
 		return nil, nil, fmt.Errorf("model argument cannot be empty")
 	}
@@ -81,5 +85,5 @@ func giqGenerateManifest(_ context.Context, _ *mcp.CallToolRequest, args *giqGen
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: string(out)},
 		},
-	}, nil, nil
+	}, &giqGenerateManifestOutput{Manifest: string(out)}, nil
 }
@@ -44,6 +44,12 @@ type getGkeReleaseNotesArgs struct {
 	TargetVersion string `json:"TargetVersion" jsonschema:"A target GKE version an upgrade happens from. For example, '1.34.3-gke.240500'."`
 }
 
+type getGkeReleaseNotesOutput struct {
+	SourceVersion string `json:"source_version"`
+	TargetVersion string `json:"target_version"`
+	ReleaseNotes  string `json:"release_notes" jsonschema:"Release notes published between the source and target versions."`
+}
+
 // Install registers the GKE release notes tool with the MCP server.
 func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	mcp.AddTool(s, &mcp.Tool{
@@ -58,7 +64,7 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	return nil
 }
 
-func getGkeReleaseNotes(_ context.Context, _ *mcp.CallToolRequest, args *getGkeReleaseNotesArgs) (*mcp.CallToolResult, any, error) {
+func getGkeReleaseNotes(_ context.Context, _ *mcp.CallToolRequest, args *getGkeReleaseNotesArgs) (*mcp.CallToolResult, *getGkeReleaseNotesOutput, error) {
 	releaseNotesFilePath := fmt.Sprintf("release-notes-%s.html", time.Now().Format("2006-01-02"))
 	releaseNotesFilePath = filepath.Clean(releaseNotesFilePath)
 
@@ -115,7 +121,11 @@ func getGkeReleaseNotes(_ context.Context, _ *mcp.CallToolRequest, args *getGkeR
 		Content: []mcp.Content{
 			&mcp.TextContent{Text: reducedReleaseNotes},
 		},
-	}, nil, nil
+	}, &getGkeReleaseNotesOutput{
+		SourceVersion: args.SourceVersion,
+		TargetVersion: args.TargetVersion,
+		ReleaseNotes:  reducedReleaseNotes,
+	}, nil
 }
 
 func extractReleaseNotesRelevantForUpgrade(fullReleaseNotes string, sourceVersion string, targetVersion string) (string, error) {
@@ -37,6 +37,11 @@ type getK8sChangelogArgs struct {
 	KubernetesMinorVersion string `json:"KubernetesMinorVersion" jsonschema:"The kubernetes minor version to get changelog for. For example, '1.33'."`
 }
 
+type getK8sChangelogOutput struct {
+	KubernetesMinorVersion string `json:"kubernetes_minor_version"`
+	Changes                string `json:"changes" jsonschema:"Markdown changelog of the minor version's releases, without dependency and download sections."`
+}
+
 // Install registers Kubernetes changelog tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	mcp.AddTool(s, &mcp.Tool{
@@ -51,7 +56,7 @@ func Install(_ context.Context, s *mcp.Server, _ *config.Config) error {
 	return nil
 }
 
-func getK8sChangelog(_ context.Context, _ *mcp.CallToolRequest, args *getK8sChangelogArgs) (*mcp.CallToolResult, any, error) {
+func getK8sChangelog(_ context.Context, _ *mcp.CallToolRequest, args *getK8sChangelogArgs) (*mcp.CallToolResult, *getK8sChangelogOutput, error) {
 	version := strings.TrimSpace(args.KubernetesMinorVersion)
 	if !kubernetesMinorVersionRegexp.MatchString(version) {
 		return nil, nil, fmt.Errorf("invalid kubernetes minor version: %s", version)
@@ -77,13 +82,13 @@ func getK8sChangelog(_ context.Context, _ *mcp.CallToolRequest, args *getK8sChan
 		log.Printf("Failed to read changelog response body: %v", err)
 		return nil, nil, err
 	}
-	changelogFileContent := string(body)
+	changes := keepOnlyChanges(string(body))
 
 	return &mcp.CallToolResult{
 		Content: []mcp.Content{
-			&mcp.TextContent{Text: keepOnlyChanges(changelogFileContent)},
+			&mcp.TextContent{Text: changes},
 		},
-	}, nil, nil
+	}, &getK8sChangelogOutput{KubernetesMinorVersion: version, Changes: changes}, nil
 }
 
 var (
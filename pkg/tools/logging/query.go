@@ -26,9 +26,9 @@ import (
 	logging "cloud.google.com/go/logging/apiv2"
 	"cloud.google.com/go/logging/apiv2/loggingpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
 	_ "google.golang.org/genproto/googleapis/cloud/audit" // Import for AuditLog proto so we can convert to JSON.
 	"google.golang.org/protobuf/encoding/protojson"
 )
@@ -40,9 +40,19 @@ type LogQueryRequest struct {
 	TimeRange TimeRange `json:"time_range,omitempty" jsonschema:"Time range for log query. If empty, no restrictions are applied."`
 	Since     string    `json:"since,omitempty" jsonschema:"Only return logs newer than a relative duration like 5s, 2m, or 3h. The only supported units are seconds ('s'), minutes ('m'), and hours ('h')."`
 	Limit     int       `json:"limit,omitempty" jsonschema:"Maximum number of log entries to return. Cannot be greater than 100. Consider multiple calls if needed. Defaults to 10."`
+	Order     string    `json:"order,omitempty" jsonschema:"Order of the log entries: 'asc' (default) for oldest first, or 'desc' for newest first. Use 'desc' with a limit to get the most recent entries."`
 	Format    string    `json:"format,omitempty" jsonschema:"Go template string to format each log entry. If empty, the full JSON representation is returned. Note that empty fields are not included in the response. Example: '{{.timestamp}} [{{.severity}}] {{.textPayload}}'. It's strongly recommended to use a template to minimize the size of the response and only include the fields you need. Use the get_schema tool before this tool to get information about supported log types and their schemas."`
 }
 
+// QueryLogsResult is the structured output of the query_logs tool.
+type QueryLogsResult struct {
+	ProjectID string           `json:"project_id"`
+	Filter    string           `json:"filter" jsonschema:"LQL filter that was run, including the time range."`
+	Entries   []map[string]any `json:"entries,omitempty" jsonschema:"Log entries as JSON objects. Set when no format template is given."`
+	Lines     []string         `json:"lines,omitempty" jsonschema:"Log entries rendered with the format template."`
+	Truncated bool             `json:"truncated" jsonschema:"Whether more entries matched than the limit."`
+}
+
 // TimeRange captures an optional start/end window for log queries.
 type TimeRange struct {
 	StartTime time.Time `json:"start_time" jsonschema:"Start time for log query (RFC3339 format)"`
@@ -52,6 +62,9 @@ type TimeRange struct {
 const (
 	defaultLimit = 10
 	maxLimit     = 100
+
+	orderAsc  = "asc"
+	orderDesc = "desc"
 )
 
 func installQueryLogsTool(s *mcp.Server, conf *config.Config) {
@@ -59,7 +72,7 @@ func installQueryLogsTool(s *mcp.Server, conf *config.Config) {
 
 	mcp.AddTool(s, &mcp.Tool{
 		Name:        "query_logs",
-		Description: "Query Google Cloud Platform logs using Logging Query Language (LQL). Before using this tool, it's **strongly** recommended to call the 'get_log_schema' tool to get information about supported log types and their schemas. Logs are returned in ascending order, based on the timestamp (i.e. oldest first).",
+		Description: "Query Google Cloud Platform logs using Logging Query Language (LQL). Before using this tool, it's **strongly** recommended to call the 'get_log_schema' tool to get information about supported log types and their schemas. Logs are returned in ascending order, based on the timestamp (i.e. oldest first), unless order is 'desc'.",
 		Annotations: &mcp.ToolAnnotations{
 			ReadOnlyHint: true,
 		},
@@ -76,21 +89,38 @@ func newQueryLogsTool(conf *config.Config) *queryLogsTool {
 	}
 }
 
-func (t *queryLogsTool) queryLogs(ctx context.Context, _ *mcp.CallToolRequest, req *LogQueryRequest) (*mcp.CallToolResult, any, error) {
-	req.setDefaults()
-	if err := req.validate(); err != nil {
-		return nil, nil, err
-	}
-	result, err := t.queryGCPLogs(ctx, req)
+func (t *queryLogsTool) queryLogs(ctx context.Context, _ *mcp.CallToolRequest, req *LogQueryRequest) (*mcp.CallToolResult, *QueryLogsResult, error) {
+	out, err := QueryLogs(ctx, t.conf, req)
 	if err != nil {
 		return nil, nil, err
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: result},
-		},
-	}, nil, nil
+	res, err := toolresult.New(out.summary(req.Limit), out)
+	return res, out, err
+}
+
+// QueryLogs runs a log query like the query_logs tool, for other tools that
+// query logs with filters of their own.
+func QueryLogs(ctx context.Context, conf *config.Config, req *LogQueryRequest) (*QueryLogsResult, error) {
+	req.setDefaults()
+	if err := req.validate(); err != nil {
+		return nil, err
+	}
+	return newQueryLogsTool(conf).queryGCPLogs(ctx, req)
+}
+
+// summary describes the query and its result size without the entries.
+func (r *QueryLogsResult) summary(limit int) string {
+	n := len(r.Entries) + len(r.Lines)
+	summary := fmt.Sprintf("Project ID: %s\nLQL Query:\n```\n%s\n```\n", r.ProjectID, r.Filter)
+	if n == 0 {
+		return summary + "No log entries found."
+	}
+	summary += fmt.Sprintf("Found %d log entries.", n)
+	if r.Truncated {
+		summary += fmt.Sprintf("\n\nWarning: Results truncated. The query returned more than the limit of %d log entries. You can use the `limit` parameter to request more entries (up to %d).", limit, maxLimit)
+	}
+	return summary
 }
 
 func (r *LogQueryRequest) setDefaults() {
@@ -106,6 +136,9 @@ func (r *LogQueryRequest) validate() error {
 	if r.Limit > maxLimit {
 		return fmt.Errorf("limit parameter cannot be greater than %d", maxLimit)
 	}
+	if r.Order != "" && r.Order != orderAsc && r.Order != orderDesc {
+		return fmt.Errorf("order parameter must be %q or %q", orderAsc, orderDesc)
+	}
 	if r.Since != "" {
 		if _, err := time.ParseDuration(r.Since); err != nil {
 			return fmt.Errorf("invalid since parameter: %w", err)
@@ -124,10 +157,10 @@ func (r *LogQueryRequest) validate() error {
 	return nil
 }
 
-func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest) (string, error) {
-	client, err := logging.NewClient(ctx, option.WithUserAgent(t.conf.UserAgent()))
+func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest) (*QueryLogsResult, error) {
+	client, err := logging.NewClient(ctx, t.conf.ClientOptions(config.ServiceLogging)...)
 	if err != nil {
-		return "", fmt.Errorf("failed to create logging client: %v", err)
+		return nil, fmt.Errorf("failed to create logging client: %v", err)
 	}
 	defer func() {
 		if err := client.Close(); err != nil {
@@ -149,7 +182,7 @@ func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest)
 			break
 		}
 		if err != nil {
-			return "", fmt.Errorf("failed to iterate log entries: %v", err)
+			return nil, fmt.Errorf("failed to iterate log entries: %v", err)
 		}
 		entries = append(entries, entry)
 		if len(entries) > req.Limit {
@@ -162,32 +195,33 @@ func (t *queryLogsTool) queryGCPLogs(ctx context.Context, req *LogQueryRequest)
 		entries = entries[:req.Limit]
 	}
 
-	allLogLines := strings.Builder{}
-	if len(entries) == 0 {
-		allLogLines.WriteString("No log entries found.")
-	} else {
-		formatter, err := formatterForRequest(req)
-		if err != nil {
-			return "", fmt.Errorf("failed to create formatter: %w", err)
-		}
-
-		for i, entry := range entries {
-			if i > 0 {
-				allLogLines.WriteString("\n")
-			}
-			logLine, err := formatter.format(entry)
+	result := &QueryLogsResult{
+		ProjectID: req.ProjectID,
+		Filter:    listLogsReq.Filter,
+		Truncated: truncated,
+	}
+	if req.Format == "" {
+		for _, entry := range entries {
+			m, err := toolresult.ProtoMap(entry)
 			if err != nil {
-				return "", fmt.Errorf("failed to format log entry: %w", err)
+				return nil, fmt.Errorf("could not marshal log entry to JSON: %w", err)
 			}
-			allLogLines.WriteString(logLine)
+			result.Entries = append(result.Entries, m)
 		}
+		return result, nil
 	}
 
-	result := fmt.Sprintf("Project ID: %s\nLQL Query:\n```\n%s\n```\nResult:\n\n%s", req.ProjectID, listLogsReq.Filter, allLogLines.String())
-	if truncated {
-		result += fmt.Sprintf("\n\nWarning: Results truncated. The query returned more than the limit of %d log entries. You can use the `limit` parameter to request more entries (up to %d).", req.Limit, maxLimit)
+	formatter, err := newTemplateFormatter(req.Format)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create formatter: %w", err)
+	}
+	for _, entry := range entries {
+		logLine, err := formatter.format(entry)
+		if err != nil {
+			return nil, fmt.Errorf("failed to format log entry: %w", err)
+		}
+		result.Lines = append(result.Lines, logLine)
 	}
-
 	return result, nil
 }
 
@@ -219,46 +253,27 @@ func buildListLogEntriesRequest(req *LogQueryRequest) *loggingpb.ListLogEntriesR
 			filter += strings.Join(timeFilters, " AND ")
 		}
 	}
+	orderBy := "timestamp asc"
+	if req.Order == orderDesc {
+		orderBy = "timestamp desc"
+	}
 	return &loggingpb.ListLogEntriesRequest{
 		ResourceNames: []string{fmt.Sprintf("projects/%s", req.ProjectID)},
 		Filter:        filter,
 		// #nosec G115
 		PageSize: int32(req.Limit),
-		OrderBy:  "timestamp asc",
+		OrderBy:  orderBy,
 	}
 }
 
-func formatterForRequest(req *LogQueryRequest) (formatter, error) {
-	if req.Format == "" {
-		return &jsonFormatter{}, nil
-	}
-
-	tmpl, err := template.New("log").Parse(req.Format)
+func newTemplateFormatter(format string) (*goTemplateFormatter, error) {
+	tmpl, err := template.New("log").Parse(format)
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse format template: %w", err)
 	}
 	return &goTemplateFormatter{tmpl: tmpl}, nil
 }
 
-type formatter interface {
-	format(entry *loggingpb.LogEntry) (string, error)
-}
-
-type jsonFormatter struct{}
-
-func (f *jsonFormatter) format(entry *loggingpb.LogEntry) (string, error) {
-	m := protojson.MarshalOptions{
-		Multiline:       true,
-		Indent:          "  ",
-		EmitUnpopulated: false,
-	}
-	logLine, err := m.Marshal(entry)
-	if err != nil {
-		return "", fmt.Errorf("could not marshal log entry to JSON: %w", err)
-	}
-	return string(logLine), nil
-}
-
 type goTemplateFormatter struct {
 	tmpl *template.Template
 }
@@ -20,6 +20,7 @@ import (
 	"time"
 
 	"cloud.google.com/go/logging/apiv2/loggingpb"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
 	"github.com/google/go-cmp/cmp"
 	ltype "google.golang.org/genproto/googleapis/logging/type"
 	"google.golang.org/protobuf/testing/protocmp"
@@ -41,6 +42,14 @@ func TestLogQueryRequest_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid order",
+			req: LogQueryRequest{
+				ProjectID: "test-project",
+				Order:     "newest",
+			},
+			wantErr: true,
+		},
 		{
 			name:    "missing project id",
 			req:     LogQueryRequest{},
@@ -131,6 +140,21 @@ func TestBuildListLogEntriesRequest(t *testing.T) {
 				OrderBy:       "timestamp asc",
 			},
 		},
+		{
+			name: "newest first",
+			req: LogQueryRequest{
+				ProjectID: "test-project",
+				Query:     "severity=ERROR",
+				Limit:     10,
+				Order:     "desc",
+			},
+			want: &loggingpb.ListLogEntriesRequest{
+				ResourceNames: []string{"projects/test-project"},
+				Filter:        "severity=ERROR",
+				PageSize:      10,
+				OrderBy:       "timestamp desc",
+			},
+		},
 	}
 
 	for _, tt := range tests {
@@ -164,29 +188,22 @@ func TestFormatter(t *testing.T) {
 		Timestamp: timestamppb.New(time.Date(2023, 1, 1, 0, 0, 0, 0, time.UTC)),
 	}
 
-	tests := []struct {
-		name    string
-		req     LogQueryRequest
-		entry   *loggingpb.LogEntry
-		want    string
-		wantErr bool
-		isJSON  bool
+	jsonTests := []struct {
+		name  string
+		entry *loggingpb.LogEntry
+		want  string
 	}{
 		{
-			name:  "json formatter text payload",
-			req:   LogQueryRequest{},
+			name:  "text payload",
 			entry: entry,
 			want: `{
   "severity": "ERROR",
   "textPayload": "test log",
   "timestamp": "2023-01-01T00:00:00Z"
 }`,
-			wantErr: false,
-			isJSON:  true,
 		},
 		{
-			name:  "json formatter json payload",
-			req:   LogQueryRequest{},
+			name:  "json payload",
 			entry: jsonEntry,
 			want: `{
   "jsonPayload": {
@@ -195,49 +212,35 @@ func TestFormatter(t *testing.T) {
   "severity": "ERROR",
   "timestamp": "2023-01-01T00:00:00Z"
 }`,
-			wantErr: false,
-			isJSON:  true,
-		},
-		{
-			name: "template formatter",
-			req: LogQueryRequest{
-				Format: "{{.textPayload}} - {{.severity}}",
-			},
-			entry:   entry,
-			want:    "test log - ERROR",
-			wantErr: false,
-			isJSON:  false,
 		},
 	}
-
-	for _, tt := range tests {
+	for _, tt := range jsonTests {
 		t.Run(tt.name, func(t *testing.T) {
-			f, err := formatterForRequest(&tt.req)
+			got, err := toolresult.ProtoMap(tt.entry)
 			if err != nil {
-				t.Fatalf("formatterForRequest() error = %v", err)
+				t.Fatalf("ProtoMap() error = %v", err)
 			}
-			got, err := f.format(tt.entry)
-			if (err != nil) != tt.wantErr {
-				t.Errorf("formatter.format() error = %v, wantErr %v", err, tt.wantErr)
-				return
+			var want map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.want), &want); err != nil {
+				t.Fatalf("failed to unmarshal want JSON: %v", err)
 			}
-
-			if tt.isJSON {
-				var gotMap, wantMap map[string]interface{}
-				if err := json.Unmarshal([]byte(got), &gotMap); err != nil {
-					t.Fatalf("failed to unmarshal got JSON: %v", err)
-				}
-				if err := json.Unmarshal([]byte(tt.want), &wantMap); err != nil {
-					t.Fatalf("failed to unmarshal want JSON: %v", err)
-				}
-				if diff := cmp.Diff(wantMap, gotMap); diff != "" {
-					t.Errorf("formatter.format() mismatch (-want +got):\n%s", diff)
-				}
-			} else {
-				if got != tt.want {
-					t.Errorf("formatter.format() = %v, want %v", got, tt.want)
-				}
+			if diff := cmp.Diff(want, got); diff != "" {
+				t.Errorf("ProtoMap() mismatch (-want +got):\n%s", diff)
 			}
 		})
 	}
+
+	t.Run("template formatter", func(t *testing.T) {
+		f, err := newTemplateFormatter("{{.textPayload}} - {{.severity}}")
+		if err != nil {
+			t.Fatalf("newTemplateFormatter() error = %v", err)
+		}
+		got, err := f.format(entry)
+		if err != nil {
+			t.Fatalf("formatter.format() error = %v", err)
+		}
+		if want := "test log - ERROR"; got != want {
+			t.Errorf("formatter.format() = %v, want %v", got, want)
+		}
+	})
 }
@@ -31,6 +31,12 @@ type GetLogSchemaRequest struct {
 	LogType string `json:"log_type" jsonschema:"The type of log to get schema for. Supported values are: ['k8s_audit_logs', 'k8s_application_logs', 'k8s_event_logs']."`
 }
 
+// GetLogSchemaResult is the structured output of the get_log_schema tool.
+type GetLogSchemaResult struct {
+	LogType string `json:"log_type"`
+	Schema  string `json:"schema" jsonschema:"Markdown description of the log type's fields with example queries."`
+}
+
 var supportedLogTypes = map[string]bool{
 	"k8s_audit_logs":       true,
 	"k8s_application_logs": true,
@@ -47,7 +53,7 @@ func installGetLogSchemas(s *mcp.Server) {
 	}, getLogSchema)
 }
 
-func getLogSchema(_ context.Context, _ *mcp.CallToolRequest, req *GetLogSchemaRequest) (*mcp.CallToolResult, any, error) {
+func getLogSchema(_ context.Context, _ *mcp.CallToolRequest, req *GetLogSchemaRequest) (*mcp.CallToolResult, *GetLogSchemaResult, error) {
 	if supportedLogTypes[req.LogType] {
 		fileName := fmt.Sprintf("%s.md", req.LogType)
 		filePath := filepath.Join("schemas", fileName)
@@ -59,7 +65,7 @@ func getLogSchema(_ context.Context, _ *mcp.CallToolRequest, req *GetLogSchemaRe
 			Content: []mcp.Content{
 				&mcp.TextContent{Text: string(content)},
 			},
-		}, nil, nil
+		}, &GetLogSchemaResult{LogType: req.LogType, Schema: string(content)}, nil
 	}
 	return nil, nil, fmt.Errorf("unsupported log_type: %s", req.LogType)
 }
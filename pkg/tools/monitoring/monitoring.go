@@ -19,15 +19,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
 
 	monitoring "cloud.google.com/go/monitoring/apiv3/v2"
 	monitoringpb "cloud.google.com/go/monitoring/apiv3/v2/monitoringpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
-	"google.golang.org/protobuf/encoding/protojson"
+	monitoredrespb "google.golang.org/genproto/googleapis/api/monitoredres"
 )
 
 type handlers struct {
@@ -38,6 +37,24 @@ type listMonitoredResourceDescriptorsArgs struct {
 	ProjectID string `json:"project_id,omitempty" jsonschema:"GCP project ID. Use the default if the user doesn't provide it."`
 }
 
+type labelDescriptor struct {
+	Key         string `json:"key"`
+	ValueType   string `json:"value_type"`
+	Description string `json:"description,omitempty"`
+}
+
+type monitoredResourceDescriptor struct {
+	Type        string            `json:"type" jsonschema:"Monitored resource type, e.g. k8s_container."`
+	DisplayName string            `json:"display_name,omitempty"`
+	Description string            `json:"description,omitempty"`
+	Labels      []labelDescriptor `json:"labels,omitempty"`
+}
+
+type listMonitoredResourceDescriptorsOutput struct {
+	ProjectID   string                        `json:"project_id"`
+	Descriptors []monitoredResourceDescriptor `json:"descriptors,omitempty"`
+}
+
 // Install registers monitoring tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
 	h := &handlers{
@@ -55,14 +72,14 @@ func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
 	return nil
 }
 
-func (h *handlers) listMRDescriptor(ctx context.Context, _ *mcp.CallToolRequest, args *listMonitoredResourceDescriptorsArgs) (*mcp.CallToolResult, any, error) {
+func (h *handlers) listMRDescriptor(ctx context.Context, _ *mcp.CallToolRequest, args *listMonitoredResourceDescriptorsArgs) (*mcp.CallToolResult, *listMonitoredResourceDescriptorsOutput, error) {
 	if args.ProjectID == "" {
 		args.ProjectID = h.c.DefaultProjectID()
 	}
 	if args.ProjectID == "" {
 		return nil, nil, fmt.Errorf("project_id argument cannot be empty")
 	}
-	c, err := monitoring.NewMetricClient(ctx, option.WithUserAgent(h.c.UserAgent()))
+	c, err := monitoring.NewMetricClient(ctx, h.c.ClientOptions(config.ServiceMonitoring)...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -75,7 +92,7 @@ func (h *handlers) listMRDescriptor(ctx context.Context, _ *mcp.CallToolRequest,
 		Name: fmt.Sprintf("projects/%s", args.ProjectID),
 	}
 	it := c.ListMonitoredResourceDescriptors(ctx, req)
-	builder := new(strings.Builder)
+	out := &listMonitoredResourceDescriptorsOutput{ProjectID: args.ProjectID}
 	for {
 		resp, err := it.Next()
 		if err == iterator.Done {
@@ -84,12 +101,29 @@ func (h *handlers) listMRDescriptor(ctx context.Context, _ *mcp.CallToolRequest,
 		if err != nil {
 			return nil, nil, err
 		}
-		builder.WriteString(protojson.Format(resp))
+		out.Descriptors = append(out.Descriptors, newMonitoredResourceDescriptor(resp))
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: builder.String()},
-		},
-	}, nil, nil
+	summary := fmt.Sprintf("Found %d monitored resource descriptors in project %s:", len(out.Descriptors), args.ProjectID)
+	for _, d := range out.Descriptors {
+		summary += fmt.Sprintf("\n- %s: %s", d.Type, d.DisplayName)
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func newMonitoredResourceDescriptor(d *monitoredrespb.MonitoredResourceDescriptor) monitoredResourceDescriptor {
+	out := monitoredResourceDescriptor{
+		Type:        d.GetType(),
+		DisplayName: d.GetDisplayName(),
+		Description: d.GetDescription(),
+	}
+	for _, l := range d.GetLabels() {
+		out.Labels = append(out.Labels, labelDescriptor{
+			Key:         l.GetKey(),
+			ValueType:   l.GetValueType().String(),
+			Description: l.GetDescription(),
+		})
+	}
+	return out
 }
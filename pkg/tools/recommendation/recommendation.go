@@ -19,15 +19,14 @@ import (
 	"context"
 	"fmt"
 	"log"
-	"strings"
+	"time"
 
 	recommender "cloud.google.com/go/recommender/apiv1"
 	recommenderpb "cloud.google.com/go/recommender/apiv1/recommenderpb"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/toolresult"
 	"github.com/modelcontextprotocol/go-sdk/mcp"
 	"google.golang.org/api/iterator"
-	"google.golang.org/api/option"
-	"google.golang.org/protobuf/encoding/protojson"
 )
 
 type handlers struct {
@@ -39,6 +38,23 @@ type listRecommendationsArgs struct {
 	Location  string `json:"location" jsonschema:"GKE cluster location. Leave this empty if the user doesn't doesn't provide it."`
 }
 
+type recommendationSummary struct {
+	Name               string         `json:"name"`
+	Description        string         `json:"description"`
+	RecommenderSubtype string         `json:"recommender_subtype,omitempty" jsonschema:"Kind of recommendation, e.g. the diagnosed problem."`
+	Priority           string         `json:"priority,omitempty" jsonschema:"P1 (highest) to P4."`
+	State              string         `json:"state,omitempty" jsonschema:"ACTIVE, CLAIMED, SUCCEEDED, FAILED or DISMISSED."`
+	Category           string         `json:"category,omitempty" jsonschema:"Category of the primary impact, e.g. RELIABILITY or SECURITY."`
+	LastRefreshTime    string         `json:"last_refresh_time,omitempty"`
+	Overview           map[string]any `json:"overview,omitempty" jsonschema:"Recommender-specific details, such as the affected resources."`
+}
+
+type listRecommendationsOutput struct {
+	ProjectID       string                  `json:"project_id"`
+	Location        string                  `json:"location"`
+	Recommendations []recommendationSummary `json:"recommendations,omitempty"`
+}
+
 // Install registers recommendation tools with the MCP server.
 func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
 
@@ -57,7 +73,7 @@ func Install(_ context.Context, s *mcp.Server, c *config.Config) error {
 	return nil
 }
 
-func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallToolRequest, args *listRecommendationsArgs) (*mcp.CallToolResult, any, error) {
+func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallToolRequest, args *listRecommendationsArgs) (*mcp.CallToolResult, *listRecommendationsOutput, error) {
 	if args.ProjectID == "" {
 		args.ProjectID = h.c.DefaultProjectID()
 	}
@@ -67,7 +83,7 @@ func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallTo
 	if args.Location == "" {
 		return nil, nil, fmt.Errorf("location argument not set")
 	}
-	c, err := recommender.NewClient(ctx, option.WithUserAgent(h.c.UserAgent()))
+	c, err := recommender.NewClient(ctx, h.c.ClientOptions(config.ServiceRecommender)...)
 	if err != nil {
 		return nil, nil, err
 	}
@@ -81,7 +97,10 @@ func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallTo
 		Parent: fmt.Sprintf("projects/%s/locations/%s/recommenders/google.container.DiagnosisRecommender", args.ProjectID, args.Location),
 	}
 	it := c.ListRecommendations(ctx, req)
-	builder := new(strings.Builder)
+	out := &listRecommendationsOutput{
+		ProjectID: args.ProjectID,
+		Location:  args.Location,
+	}
 	for {
 		resp, err := it.Next()
 		if err == iterator.Done {
@@ -90,12 +109,31 @@ func (h *handlers) listProjectRecommendations(ctx context.Context, _ *mcp.CallTo
 		if err != nil {
 			return nil, nil, err
 		}
-		builder.WriteString(protojson.Format(resp))
+		out.Recommendations = append(out.Recommendations, newRecommendationSummary(resp))
 	}
 
-	return &mcp.CallToolResult{
-		Content: []mcp.Content{
-			&mcp.TextContent{Text: builder.String()},
-		},
-	}, nil, nil
+	summary := fmt.Sprintf("Found %d recommendations in project %s, location %s:", len(out.Recommendations), args.ProjectID, args.Location)
+	for _, r := range out.Recommendations {
+		summary += fmt.Sprintf("\n- [%s] %s", r.Priority, r.Description)
+	}
+	res, err := toolresult.New(summary, out)
+	return res, out, err
+}
+
+func newRecommendationSummary(r *recommenderpb.Recommendation) recommendationSummary {
+	s := recommendationSummary{
+		Name:               r.GetName(),
+		Description:        r.GetDescription(),
+		RecommenderSubtype: r.GetRecommenderSubtype(),
+		State:              r.GetStateInfo().GetState().String(),
+		Category:           r.GetPrimaryImpact().GetCategory().String(),
+		Overview:           r.GetContent().GetOverview().AsMap(),
+	}
+	if r.GetPriority() != recommenderpb.Recommendation_PRIORITY_UNSPECIFIED {
+		s.Priority = r.GetPriority().String()
+	}
+	if t := r.GetLastRefreshTime(); t != nil {
+		s.LastRefreshTime = t.AsTime().Format(time.RFC3339)
+	}
+	return s
 }
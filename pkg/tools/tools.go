@@ -17,8 +17,10 @@ package tools
 
 import (
 	"context"
+	"log"
 
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/registry"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/cluster"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/clustertoolkit"
 	"github.com/GoogleCloudPlatform/gke-mcp/pkg/tools/deploy"
@@ -33,25 +35,77 @@ import (
 
 type installer func(ctx context.Context, s *mcp.Server, c *config.Config) error
 
+// toolPackage is a set of tools installed together. Its name is used to
+// select the package's tools in filter rules, e.g. "logging.*".
+type toolPackage struct {
+	name    string
+	install installer
+}
+
 // Install registers all tools with the MCP server.
 func Install(ctx context.Context, s *mcp.Server, c *config.Config) error {
-	installers := []installer{
-		cluster.Install,
-		clustertoolkit.Install,
-		deploy.Install,
-		giq.Install,
-		logging.Install,
-		monitoring.Install,
-		recommendation.Install,
-		k8schangelog.Install,
-		gkereleasenotes.Install,
+	packages := []toolPackage{
+		{"cluster", cluster.Install},
+		{"clustertoolkit", clustertoolkit.Install},
+		{"deploy", deploy.Install},
+		{"giq", giq.Install},
+		{"logging", logging.Install},
+		{"monitoring", monitoring.Install},
+		{"recommendation", recommendation.Install},
+		{"k8schangelog", k8schangelog.Install},
+		{"gkereleasenotes", gkereleasenotes.Install},
+	}
+
+	// Record which package registered each tool so filter rules can select
+	// whole packages.
+	byPackage := map[string][]string{}
+	registered := map[string]bool{}
+	for _, p := range packages {
+		if err := p.install(ctx, s, c); err != nil {
+			return err
+		}
+		tools, err := registry.Tools(ctx, s)
+		if err != nil {
+			return err
+		}
+		for _, t := range tools {
+			if !registered[t.Name] {
+				registered[t.Name] = true
+				byPackage[p.name] = append(byPackage[p.name], t.Name)
+			}
+		}
 	}
 
-	for _, installer := range installers {
-		if err := installer(ctx, s, c); err != nil {
+	if err := registry.RemoveDisabled("tool", c.ToolFilter(), byPackage, s.RemoveTools); err != nil {
+		return err
+	}
+
+	if c.ReadOnly() {
+		if err := removeMutatingTools(ctx, s); err != nil {
 			return err
 		}
 	}
 
 	return nil
 }
+
+// removeMutatingTools unregisters every tool that isn't annotated as read-only.
+// They're removed before the server accepts any session, so clients never
+// list them.
+func removeMutatingTools(ctx context.Context, s *mcp.Server) error {
+	registered, err := registry.Tools(ctx, s)
+	if err != nil {
+		return err
+	}
+	var names []string
+	for _, t := range registered {
+		if t.Annotations == nil || !t.Annotations.ReadOnlyHint {
+			names = append(names, t.Name)
+		}
+	}
+	if len(names) > 0 {
+		log.Printf("Read-only mode: removing tools %v", names)
+		s.RemoveTools(names...)
+	}
+	return nil
+}
@@ -0,0 +1,136 @@
+// Copyright 2025 Google LLC
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tools
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/config"
+	"github.com/GoogleCloudPlatform/gke-mcp/pkg/registry"
+	"github.com/google/go-cmp/cmp"
+	"github.com/modelcontextprotocol/go-sdk/mcp"
+)
+
+type noArgs struct{}
+
+func noop(context.Context, *mcp.CallToolRequest, *noArgs) (*mcp.CallToolResult, any, error) {
+	return &mcp.CallToolResult{}, nil, nil
+}
+
+func newTestServer() *mcp.Server {
+	s := mcp.NewServer(&mcp.Implementation{Name: "test"}, nil)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "read_tool",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: true},
+	}, noop)
+	mcp.AddTool(s, &mcp.Tool{
+		Name:        "write_tool",
+		Annotations: &mcp.ToolAnnotations{ReadOnlyHint: false},
+	}, noop)
+	mcp.AddTool(s, &mcp.Tool{
+		Name: "unannotated_tool",
+	}, noop)
+	return s
+}
+
+func toolNames(t *testing.T, s *mcp.Server) []string {
+	t.Helper()
+	registered, err := registry.Tools(context.Background(), s)
+	if err != nil {
+		t.Fatalf("registry.Tools() error = %v", err)
+	}
+	var names []string
+	for _, tool := range registered {
+		names = append(names, tool.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func TestRemoveMutatingTools(t *testing.T) {
+	s := newTestServer()
+	if err := removeMutatingTools(context.Background(), s); err != nil {
+		t.Fatalf("removeMutatingTools() error = %v", err)
+	}
+	want := []string{"read_tool"}
+	if diff := cmp.Diff(want, toolNames(t, s)); diff != "" {
+		t.Errorf("tools after removeMutatingTools() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestApplyFilter(t *testing.T) {
+	byPackage := map[string][]string{
+		"reader": {"read_tool", "unannotated_tool"},
+		"writer": {"write_tool"},
+	}
+	tests := []struct {
+		name    string
+		rules   []string
+		want    []string
+		wantErr bool
+	}{
+		{
+			name: "no rules",
+			want: []string{"read_tool", "unannotated_tool", "write_tool"},
+		},
+		{
+			name:  "deny single tool",
+			rules: []string{"!write_tool"},
+			want:  []string{"read_tool", "unannotated_tool"},
+		},
+		{
+			name:  "deny package",
+			rules: []string{"!reader.*"},
+			want:  []string{"write_tool"},
+		},
+		{
+			name:  "allow package",
+			rules: []string{"reader.*"},
+			want:  []string{"read_tool", "unannotated_tool"},
+		},
+		{
+			name:  "allow package except tool",
+			rules: []string{"reader.*", "!unannotated_tool"},
+			want:  []string{"read_tool"},
+		},
+		{
+			name:    "unknown name",
+			rules:   []string{"!delete_everything"},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := config.NewFilter(tt.rules)
+			if err != nil {
+				t.Fatalf("NewFilter() error = %v", err)
+			}
+			s := newTestServer()
+			err = registry.RemoveDisabled("tool", f, byPackage, s.RemoveTools)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("RemoveDisabled() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if diff := cmp.Diff(tt.want, toolNames(t, s)); diff != "" {
+				t.Errorf("tools after RemoveDisabled() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}